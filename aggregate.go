@@ -0,0 +1,79 @@
+package client
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// Aggregator sends the same request to several Skylark instances and merges the results.
+type Aggregator struct {
+	// Clients maps names, like regions, to the clients for their instances.
+	Clients map[string]*Client
+}
+
+// AggregateResult holds the merged results of an aggregated request.
+type AggregateResult struct {
+	// Objects holds the objects of all instances, objects with the same uid are only included once.
+	// When instances return the same object, the one of the instance with the lowest name is used.
+	Objects []json.RawMessage
+	// Errors maps the names of instances whose request failed to their error.
+	Errors map[string]error
+}
+
+// Query executes the request with every client in parallel, using each client's endpoint.
+// Collection requests are merged by their objects, requests with an ID by the returned object.
+func (a *Aggregator) Query(r *Request) *AggregateResult {
+	names := make([]string, 0, len(a.Clients))
+	for name := range a.Clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	objects := make([][]json.RawMessage, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, c *Client) {
+			defer wg.Done()
+			objects[i], errs[i] = queryObjects(c, r)
+		}(i, a.Clients[name])
+	}
+	wg.Wait()
+
+	res := &AggregateResult{Errors: make(map[string]error)}
+	dedupe := NewDeduplicator()
+	for i, name := range names {
+		if errs[i] != nil {
+			res.Errors[name] = errs[i]
+			continue
+		}
+		for _, object := range objects[i] {
+			if !dedupe.SeenObject(object) {
+				res.Objects = append(res.Objects, object)
+			}
+		}
+	}
+	return res
+}
+
+// queryObjects executes the request with the client and returns the returned objects.
+func queryObjects(c *Client, r *Request) ([]json.RawMessage, error) {
+	q := r.copy()
+	q.Endpoint = ""
+	q.client = c
+
+	if q.ID != "" {
+		var object json.RawMessage
+		if err := q.Execute(&object); err != nil {
+			return nil, err
+		}
+		return []json.RawMessage{object}, nil
+	}
+	var res struct {
+		Objects []json.RawMessage `json:"objects"`
+	}
+	err := q.Execute(&res)
+	return res.Objects, err
+}
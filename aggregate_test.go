@@ -0,0 +1,39 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAggregator(t *testing.T) {
+	eu := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"objects": [{"uid": "set_1", "region": "eu"}, {"uid": "set_2"}]}`))
+	}))
+	defer eu.Close()
+	us := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"objects": [{"uid": "set_1", "region": "us"}, {"uid": "set_3"}]}`))
+	}))
+	defer us.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	a := &Aggregator{Clients: map[string]*Client{
+		"eu":   NewClient(WithBaseURL(eu.URL)),
+		"us":   NewClient(WithBaseURL(us.URL)),
+		"asia": NewClient(WithBaseURL(broken.URL)),
+	}}
+	res := a.Query(NewRequest("", "sets", ""))
+
+	if len(res.Objects) != 3 {
+		t.Error("incorrect number of merged objects", len(res.Objects))
+	}
+	if string(res.Objects[0]) != `{"uid": "set_1", "region": "eu"}` {
+		t.Error("duplicate should be taken from first instance", string(res.Objects[0]))
+	}
+	if len(res.Errors) != 1 || res.Errors["asia"] == nil {
+		t.Error("incorrect errors", res.Errors)
+	}
+}
@@ -0,0 +1,28 @@
+package client
+
+import "net/http"
+
+// WithHeaderAllowlist enables strict mode, in which only the given headers are sent and all others are removed
+// right before a request leaves the client, including headers set by golark itself, hooks or middleware.
+// Headers added by the HTTP transport, like Host and Content-Length, are not affected.
+func WithHeaderAllowlist(headers ...string) Option {
+	return func(s *settings) {
+		s.headerAllowlist = make(map[string]bool, len(headers))
+		for _, h := range headers {
+			s.headerAllowlist[http.CanonicalHeaderKey(h)] = true
+		}
+	}
+}
+
+// allowlisted removes all headers that are not allowlisted before sending the request with next.
+// It wraps the HTTP client directly so headers set by middlewares are removed too.
+func (s *settings) allowlisted(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		for key := range req.Header {
+			if !s.headerAllowlist[http.CanonicalHeaderKey(key)] {
+				delete(req.Header, key)
+			}
+		}
+		return next(req)
+	}
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// APIError is returned when the server responds with a non 2xx status code.
+// Use errors.As to inspect it, or the predicates like IsNotFound.
+type APIError struct {
+	StatusCode int
+	// Message is the response body with redacted fields and masked values, see WithMaskedParams.
+	Message string
+	// Payload is the decoded response body if it is a JSON object, redacted and masked like Message.
+	Payload map[string]interface{}
+	// Method and URL identify the request, sensitive parameters are masked.
+	Method string
+	URL    string
+	// RetryAfter is the delay requested by the server's Retry-After header.
+	RetryAfter time.Duration
+}
+
+// newAPIError builds the error of a response, body must already be redacted.
+func newAPIError(req *http.Request, res *http.Response, body []byte, s *settings) *APIError {
+	sensitive := s.sensitiveValues(req)
+	e := &APIError{
+		StatusCode: res.StatusCode,
+		Message:    maskString(string(body), sensitive),
+		Method:     req.Method,
+		URL:        s.maskURL(req.URL),
+		RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), s.now()),
+	}
+	if json.Unmarshal(body, &e.Payload) == nil {
+		maskValue(e.Payload, sensitive)
+	}
+	return e
+}
+
+// Error returns the response body, like errors returned before APIError was introduced.
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+func hasStatus(err error, code int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == code
+}
+
+// IsNotFound reports whether err is a 404 Not Found response.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err is a 401 Unauthorized response.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is a 403 Forbidden response.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsRateLimited reports whether err is a 429 Too Many Requests response.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
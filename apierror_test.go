@@ -0,0 +1,32 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"detail": "Not found."}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithMaskedParams("api_key"))
+	err := c.NewRequest("episodes", "ep_1").WithParam("api_key", "secret").Execute(nil)
+	if !IsNotFound(err) || IsUnauthorized(err) || IsForbidden(err) {
+		t.Fatal("expected not found error, got", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("expected APIError")
+	}
+	if apiErr.Payload["detail"] != "Not found." || apiErr.Method != http.MethodGet || apiErr.URL != server.URL+"/episodes/ep_1/?api_key=%2A%2A%2A" {
+		t.Error("incorrect error", apiErr.Payload, apiErr.Method, apiErr.URL)
+	}
+	if err.Error() != `{"detail": "Not found."}` {
+		t.Error("incorrect message", err.Error())
+	}
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditRecord describes a single executed request, including all its retries.
+type AuditRecord struct {
+	Time       time.Time     `json:"time"`
+	Actor      string        `json:"actor,omitempty"`
+	Method     string        `json:"method"`
+	URL        string        `json:"url"`
+	Collection string        `json:"collection"`
+	ID         string        `json:"id,omitempty"`
+	Status     int           `json:"status,omitempty"`
+	Attempts   int           `json:"attempts"`
+	Duration   time.Duration `json:"duration"`
+	Error      string        `json:"error,omitempty"`
+	// Metadata is set with ContextWithMetadata.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// AuditSink receives a record for every request executed by a client.
+// Audit is called synchronously after the request finished and must be safe for concurrent use.
+type AuditSink interface {
+	Audit(record AuditRecord)
+}
+
+// WithAuditSink sets the sink that receives an audit record for every request.
+func WithAuditSink(sink AuditSink) Option {
+	return func(s *settings) {
+		s.audit = sink
+	}
+}
+
+type actorKey struct{}
+
+// WithActor returns a context that attributes requests executed with it to the given actor in audit records.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func (s *settings) auditRecord(r *Request, method string, u *url.URL, status, attempts int, start time.Time, err error) AuditRecord {
+	record := AuditRecord{
+		Time:       start,
+		Method:     method,
+		Collection: r.Collection,
+		ID:         r.ID,
+		Status:     status,
+		Attempts:   attempts,
+		Duration:   s.now().Sub(start),
+	}
+	record.Actor, _ = r.ctx.Value(actorKey{}).(string)
+	record.Metadata = MetadataFromContext(r.ctx)
+	record.URL = s.maskURL(u)
+	if err != nil {
+		record.Error = err.Error()
+	}
+	return record
+}
+
+// JSONAuditSink writes audit records to a writer as newline delimited JSON.
+type JSONAuditSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	c   io.Closer
+}
+
+// NewJSONAuditSink creates a sink that writes to w, for example os.Stdout.
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{enc: json.NewEncoder(w)}
+}
+
+// NewFileAuditSink creates a sink that appends to the file at path, creating it if necessary.
+func NewFileAuditSink(path string) (*JSONAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONAuditSink{enc: json.NewEncoder(f), c: f}, nil
+}
+
+// Audit implements AuditSink.
+// Records that can't be written are dropped.
+func (s *JSONAuditSink) Audit(record AuditRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enc.Encode(record)
+}
+
+// Close closes the underlying file of sinks created by NewFileAuditSink.
+func (s *JSONAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.c == nil {
+		return nil
+	}
+	return s.c.Close()
+}
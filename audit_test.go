@@ -0,0 +1,34 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuditSink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	c := NewClient(WithBaseURL(server.URL), WithAuditSink(NewJSONAuditSink(&out)))
+	ctx := WithActor(context.Background(), "editor@test.com")
+	if err := c.send(c.NewRequest("sets", "").WithContext(ctx), http.MethodPost, map[string]string{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var record AuditRecord
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatal("invalid audit record:", err)
+	}
+	if record.Actor != "editor@test.com" || record.Method != http.MethodPost || record.Status != http.StatusCreated ||
+		record.Collection != "sets" || record.URL != server.URL+"/sets/" || record.Attempts != 1 {
+		t.Errorf("incorrect audit record %+v", record)
+	}
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Authenticator adds credentials to outgoing requests.
+// It is called for every attempt, after the client's headers are set and before request finalizers run.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// Refresher is implemented by authenticators whose credentials can expire.
+// When a request is rejected with 401 Unauthorized, Refresh is called and the request is sent once more.
+type Refresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// WithAuthenticator sets the authenticator applied to every request, replacing the previous one.
+func WithAuthenticator(a Authenticator) Option {
+	return func(s *settings) {
+		s.auth = a
+		s.shareClock(a)
+	}
+}
+
+// WithTokenAuth sends the token as a bearer token in the Authorization header.
+func WithTokenAuth(token string) Option {
+	return WithAuthenticator(TokenAuth(token))
+}
+
+// WithAPIKey sends the key in the Skylark-Api-Key header.
+func WithAPIKey(key string) Option {
+	return WithAuthenticator(APIKeyAuth(key))
+}
+
+// TokenAuth is a static bearer token.
+type TokenAuth string
+
+// Authenticate implements Authenticator.
+func (t TokenAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// APIKeyAuth is a static Skylark API key.
+type APIKeyAuth string
+
+// Authenticate implements Authenticator.
+func (k APIKeyAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Skylark-Api-Key", string(k))
+	return nil
+}
+
+// ChainAuth applies multiple authenticators in order, for deployments that require both a token and an API key.
+type ChainAuth []Authenticator
+
+// Authenticate implements Authenticator.
+func (c ChainAuth) Authenticate(req *http.Request) error {
+	for _, a := range c {
+		if err := a.Authenticate(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetClock sets the clock of every authenticator that tells time.
+func (c ChainAuth) SetClock(clock Clock) {
+	for _, a := range c {
+		if setter, ok := a.(clockSetter); ok {
+			setter.SetClock(clock)
+		}
+	}
+}
+
+// Refresh implements Refresher by refreshing every authenticator that supports it.
+func (c ChainAuth) Refresh(ctx context.Context) error {
+	for _, a := range c {
+		if r, ok := a.(Refresher); ok {
+			if err := r.Refresh(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RefreshingTokenAuth sends bearer tokens obtained from Fetch, for example from an OAuth token endpoint.
+// Tokens are reused until shortly before they expire or the server rejects them.
+type RefreshingTokenAuth struct {
+	// Fetch returns a new token and the time it expires, a zero time never expires.
+	Fetch func(ctx context.Context) (token string, expires time.Time, err error)
+	// Leeway is how long before expiry a token is replaced. Defaults to 30 seconds.
+	Leeway time.Duration
+
+	mu      sync.Mutex
+	clock   Clock
+	token   string
+	expires time.Time
+}
+
+// SetClock sets the clock used to expire tokens, it defaults to the system clock.
+// Clients set it to their clock, see WithClock.
+func (a *RefreshingTokenAuth) SetClock(clock Clock) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clock = clock
+}
+
+// Authenticate implements Authenticator.
+func (a *RefreshingTokenAuth) Authenticate(req *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	leeway := a.Leeway
+	if leeway == 0 {
+		leeway = 30 * time.Second
+	}
+	now := time.Now()
+	if a.clock != nil {
+		now = a.clock.Now()
+	}
+	if a.token == "" || (!a.expires.IsZero() && now.Add(leeway).After(a.expires)) {
+		if err := a.fetch(req.Context()); err != nil {
+			return err
+		}
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+// Refresh implements Refresher by fetching a new token.
+func (a *RefreshingTokenAuth) Refresh(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.fetch(ctx)
+}
+
+func (a *RefreshingTokenAuth) fetch(ctx context.Context) error {
+	if a.Fetch == nil {
+		return errors.New("RefreshingTokenAuth without Fetch")
+	}
+	token, expires, err := a.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	a.token, a.expires = token, expires
+	return nil
+}
+
+// refreshAuth refreshes the authenticator's credentials if err is a 401 response and it supports it.
+// It reports whether the request should be sent again.
+func (s *settings) refreshAuth(ctx context.Context, err error) bool {
+	if !IsUnauthorized(err) {
+		return false
+	}
+	r, ok := s.auth.(Refresher)
+	if !ok {
+		return false
+	}
+	if err := r.Refresh(ctx); err != nil {
+		s.log(ctx, "refreshing credentials failed", "error", err)
+		return false
+	}
+	return true
+}
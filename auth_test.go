@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStaticAuth(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithAuthenticator(ChainAuth{TokenAuth("token"), APIKeyAuth("key")}))
+	if err := c.NewRequest("episodes", "").Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("Authorization") != "Bearer token" || header.Get("Skylark-Api-Key") != "key" {
+		t.Error("incorrect auth headers", header)
+	}
+	if err := c.UpdateConfig(WithAPIKey("other")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.NewRequest("episodes", "").Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("Authorization") != "" || header.Get("Skylark-Api-Key") != "other" {
+		t.Error("incorrect auth headers after update", header)
+	}
+}
+
+func TestRefreshingTokenAuth(t *testing.T) {
+	valid := "token_1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	fetches := 0
+	auth := &RefreshingTokenAuth{Fetch: func(ctx context.Context) (string, time.Time, error) {
+		fetches++
+		return fmt.Sprintf("token_%d", fetches), time.Now().Add(time.Hour), nil
+	}}
+	c := NewClient(WithBaseURL(server.URL), WithAuthenticator(auth))
+	for i := 0; i < 2; i++ {
+		if err := c.NewRequest("episodes", "").Execute(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fetches != 1 {
+		t.Error("token was not reused", fetches)
+	}
+
+	// the server revokes the token
+	valid = "token_2"
+	if err := c.NewRequest("episodes", "").Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if fetches != 2 {
+		t.Error("token was not refreshed", fetches)
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchCall is a request executed by Batch and the value its response is decoded into.
+type BatchCall struct {
+	Request *Request
+	Dest    interface{}
+}
+
+// Batch executes the calls with at most concurrency requests in parallel, all using ctx.
+// A concurrency of zero uses the client's bulk concurrency, see WithBulkConcurrency.
+// The returned errors are in the order of the calls and nil for calls that succeeded,
+// calls that weren't started before ctx was done fail with ctx's error.
+func (c *Client) Batch(ctx context.Context, calls []BatchCall, concurrency int) []error {
+	if concurrency <= 0 {
+		concurrency = c.current().bulkConcurrency
+	}
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	errs := make([]error, len(calls))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, call BatchCall) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r := call.Request.copy()
+			r.ctx = ctx
+			errs[i] = c.Do(r, call.Dest)
+		}(i, call)
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	var active, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		uid := strings.Split(strings.Trim(r.URL.Path, "/"), "/")[1]
+		if uid == "ep_missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"uid": uid})
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	uids := []string{"ep_1", "ep_2", "ep_missing", "ep_4", "ep_5"}
+	dests := make([]struct {
+		UID string `json:"uid"`
+	}, len(uids))
+	calls := make([]BatchCall, len(uids))
+	for i, uid := range uids {
+		calls[i] = BatchCall{Request: c.NewRequest("episodes", uid), Dest: &dests[i]}
+	}
+
+	errs := c.Batch(context.Background(), calls, 2)
+	for i, err := range errs {
+		if (err != nil) != (uids[i] == "ep_missing") {
+			t.Errorf("unexpected error for %s: %v", uids[i], err)
+		}
+		if err == nil && dests[i].UID != uids[i] {
+			t.Errorf("incorrect object for %s: %s", uids[i], dests[i].UID)
+		}
+	}
+	if !IsNotFound(errs[2]) {
+		t.Error("expected a not found error", errs[2])
+	}
+	if peak > 2 {
+		t.Error("concurrency limit exceeded", peak)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range c.Batch(ctx, calls, 1) {
+		if err == nil {
+			t.Error("expected an error for a cancelled context")
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrParamNotAllowed is wrapped by errors of RequestFromHTTP for query parameters that are not allowed.
+var ErrParamNotAllowed = errors.New("query parameter not allowed")
+
+// QueryAllowlist limits what callers of a proxy endpoint can query, see RequestFromHTTP.
+type QueryAllowlist struct {
+	// Fields can be selected with the fields parameter, nested fields are written like image_urls__url.
+	Fields []string
+	// Expand can be expanded with the fields_to_expand parameter.
+	Expand []string
+	// Filters are the allowed filter parameters, for example "season" or "season__gt".
+	Filters []string
+	// Order are the fields the order parameter can sort by.
+	Order []string
+	// MaxLimit caps the limit parameter, requests without limit get it as their limit. Zero allows any limit.
+	MaxLimit int
+}
+
+// RequestFromHTTP converts the query parameters of an incoming request into a request for the collection,
+// to build thin proxy endpoints in front of Skylark.
+// Supported parameters are fields, fields_to_expand, order, limit, offset and filters,
+// a parameter that is not in the allowlist returns an error wrapping ErrParamNotAllowed.
+// The request uses the incoming request's context.
+func (c *Client) RequestFromHTTP(in *http.Request, collection, id string, allow QueryAllowlist) (*Request, error) {
+	allowed := func(list []string, name string) bool {
+		for _, item := range list {
+			if item == name {
+				return true
+			}
+		}
+		return false
+	}
+	notAllowed := func(key, value string) error {
+		return fmt.Errorf("%w: %s=%s", ErrParamNotAllowed, key, value)
+	}
+
+	r := c.NewRequest(collection, id).WithContext(in.Context())
+	limit := allow.MaxLimit
+	for key, values := range in.URL.Query() {
+		value := strings.Join(values, ",")
+		switch key {
+		case "fields", "fields_to_expand":
+			for _, name := range strings.Split(value, ",") {
+				list := allow.Fields
+				if key == "fields_to_expand" {
+					list = allow.Expand
+				}
+				if !allowed(list, name) {
+					return nil, notAllowed(key, name)
+				}
+				f := r.Fields[name]
+				if f == nil {
+					f = NewField(name)
+					f.IsIncluded = false
+				}
+				if key == "fields" {
+					f.IsIncluded = true
+				} else {
+					f.IsExpanded = true
+				}
+				r.AddField(f)
+			}
+		case "order":
+			for _, field := range strings.Split(value, ",") {
+				if !allowed(allow.Order, strings.TrimPrefix(field, "-")) {
+					return nil, notAllowed(key, value)
+				}
+			}
+			r.setParam("order", value, nil)
+		case "limit", "offset":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid %s %q", key, value)
+			}
+			if key == "offset" {
+				r.Offset(n)
+			} else if allow.MaxLimit == 0 || n <= allow.MaxLimit {
+				limit = n
+			} else {
+				return nil, fmt.Errorf("%w: limit %d exceeds %d", ErrParamNotAllowed, n, allow.MaxLimit)
+			}
+		default:
+			if !allowed(allow.Filters, key) {
+				return nil, notAllowed(key, value)
+			}
+			field, op := key, ""
+			if i := strings.LastIndex(key, "__"); i >= 0 && isComparator(constraint(key[i+2:])) {
+				field, op = key[:i], key[i+2:]
+			}
+			filter := NewFilter(constraint(op), value)
+			if filter.err != nil {
+				return nil, filter.err
+			}
+			r.WithFilter(field, filter)
+		}
+	}
+	if limit > 0 {
+		r.Limit(limit)
+	}
+	return r, nil
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestFromHTTP(t *testing.T) {
+	c := NewClient(WithBaseURL("https://test.com"))
+	allow := QueryAllowlist{
+		Fields:   []string{"title", "image_urls__url"},
+		Expand:   []string{"image_urls"},
+		Filters:  []string{"season__gt", "image_urls__type"},
+		Order:    []string{"title"},
+		MaxLimit: 50,
+	}
+
+	in := httptest.NewRequest("GET", "/episodes?fields=title,image_urls__url&fields_to_expand=image_urls&season__gt=2019&image_urls__type=poster&order=-title&offset=10", nil)
+	r, err := c.RequestFromHTTP(in, "episodes", "", allow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testClientURL(c, r, "https://test.com/episodes/?fields=title,image_urls__url&fields_to_expand=image_urls&season__gt=2019&image_urls__type=poster&order=-title&offset=10&limit=50", t)
+
+	for _, query := range []string{"fields=secret", "fields_to_expand=team", "season=2019", "order=secret", "limit=100"} {
+		in := httptest.NewRequest("GET", "/episodes?"+query, nil)
+		if _, err := c.RequestFromHTTP(in, "episodes", "", allow); !errors.Is(err, ErrParamNotAllowed) {
+			t.Errorf("%s: expected not allowed error, got %v", query, err)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// AttemptTimeout splits the time remaining until the context's deadline evenly across the remaining attempts.
+// It returns false if the context has no deadline.
+func AttemptTimeout(ctx context.Context, attemptsLeft int) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	if attemptsLeft < 1 {
+		attemptsLeft = 1
+	}
+	remaining := time.Until(deadline)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining / time.Duration(attemptsLeft), true
+}
+
+// WithAttemptTimeout returns a context for a single attempt that gets its share of the parent's remaining time,
+// so later attempts still have time left when earlier ones time out.
+// If the parent has no deadline it is returned unchanged.
+func WithAttemptTimeout(ctx context.Context, attemptsLeft int) (context.Context, context.CancelFunc) {
+	timeout, ok := AttemptTimeout(ctx, attemptsLeft)
+	if !ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
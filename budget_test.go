@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAttemptTimeout(t *testing.T) {
+	if _, ok := AttemptTimeout(context.Background(), 3); ok {
+		t.Error("expected no timeout without deadline")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	timeout, ok := AttemptTimeout(ctx, 3)
+	if !ok || timeout > time.Second || timeout < 900*time.Millisecond {
+		t.Error("incorrect attempt timeout", timeout)
+	}
+}
+
+func TestSplitDeadline(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 3, SplitDeadline: true}))
+	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Millisecond)
+	defer cancel()
+
+	var res map[string]interface{}
+	if err := c.NewRequest("set", "").WithContext(ctx).Execute(&res); err != nil {
+		t.Fatal("expected second attempt to succeed, got", err)
+	}
+	if calls != 2 {
+		t.Error("incorrect number of attempts", calls)
+	}
+}
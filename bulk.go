@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+const (
+	defaultBulkConcurrency = 4
+	defaultBulkPageSize    = 100
+)
+
+// WithBulkConcurrency sets how many requests bulk operations like UpdateWhere run in parallel.
+func WithBulkConcurrency(n int) Option {
+	return func(s *settings) {
+		s.bulkConcurrency = n
+	}
+}
+
+// UpdateResult is the outcome of updating a single object in a bulk operation.
+type UpdateResult struct {
+	UID string
+	Err error
+}
+
+// UpdateWhere patches every object in the collection that matches all filters with the same partial update.
+// All matching objects are listed before the first update is sent, so updates that change
+// whether an object matches don't affect which objects are updated.
+// The returned error is only set if listing the objects failed, failed updates are reported in the results.
+func (c *Client) UpdateWhere(ctx context.Context, collection string, filters map[string]*Filter, patch interface{}) ([]UpdateResult, error) {
+	uids, err := c.listUIDs(ctx, collection, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := c.current().bulkConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	results := make([]UpdateResult, len(uids))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, uid := range uids {
+		results[i].UID = uid
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(result *UpdateResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result.Err = c.send(c.NewRequest(collection, result.UID).WithContext(ctx), http.MethodPatch, patch, nil)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// listUIDs pages through the collection and returns the uid of every object matching all filters.
+func (c *Client) listUIDs(ctx context.Context, collection string, filters map[string]*Filter) ([]string, error) {
+	uid := NewField("uid")
+	r := c.NewRequest(collection, "").
+		WithContext(ctx).
+		AddField(uid).
+		OrderBy(uid)
+	for field, filter := range filters {
+		r.WithFilter(field, filter)
+	}
+
+	var uids []string
+	err := c.eachPage(r, 0, 0, nil, func(objects []json.RawMessage) error {
+		for _, object := range objects {
+			var meta struct {
+				UID string `json:"uid"`
+			}
+			if err := json.Unmarshal(object, &meta); err != nil {
+				return err
+			}
+			uids = append(uids, meta.UID)
+		}
+		return nil
+	})
+	return uids, err
+}
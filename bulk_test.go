@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestUpdateWhere(t *testing.T) {
+	var mu sync.Mutex
+	patched := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			uid := strings.Split(strings.Trim(r.URL.Path, "/"), "/")[1]
+			if uid == "ep_3" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			patched[uid] = true
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var res struct {
+			Objects []map[string]string `json:"objects"`
+		}
+		for i := offset; i < offset+limit && i < 5; i++ {
+			res.Objects = append(res.Objects, map[string]string{"uid": fmt.Sprintf("ep_%d", i)})
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithPageSize(2), WithBulkConcurrency(2))
+	results, err := c.UpdateWhere(context.Background(), "episodes",
+		map[string]*Filter{"season": NewFilter(Equals, "2020")},
+		map[string]bool{"published": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 5 {
+		t.Fatal("incorrect number of results", len(results))
+	}
+	for _, result := range results {
+		if (result.Err != nil) != (result.UID == "ep_3") {
+			t.Errorf("unexpected result for %s: %v", result.UID, result.Err)
+		}
+	}
+	if len(patched) != 4 {
+		t.Error("incorrect number of patched objects", len(patched))
+	}
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Cache stores response bodies with their validators for conditional requests, see WithCache.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, res *CachedResponse)
+}
+
+// CachedResponse is a response body stored in a Cache.
+type CachedResponse struct {
+	Body         []byte
+	Header       http.Header
+	ETag         string
+	LastModified string
+	Stored       time.Time
+}
+
+// WithCache caches GET responses that have an ETag or Last-Modified header. Later requests for the same URL
+// are sent as conditional requests and the cached body is served if the server responds with 304 Not Modified.
+// Use Request.SkipCache to bypass the cache. Downloads and streamed responses are never cached.
+func WithCache(cache Cache) Option {
+	return func(s *settings) {
+		s.cache = cache
+	}
+}
+
+// SkipCache sends the request without consulting or updating the client's cache.
+func (r *Request) SkipCache() *Request {
+	r.skipCache = true
+	return r
+}
+
+type skipCacheKey struct{}
+
+// cached serves responses from the client's cache for conditional GET requests.
+func (s *settings) cached(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet || req.Context().Value(skipCacheKey{}) != nil {
+			return next(req)
+		}
+		key := req.URL.String()
+		entry, ok := s.cache.Get(key)
+		ok = ok && req.Header.Get("If-None-Match") == "" && req.Header.Get("If-Modified-Since") == ""
+		if ok {
+			if entry.ETag != "" {
+				req.Header.Set("If-None-Match", entry.ETag)
+			}
+			if entry.LastModified != "" {
+				req.Header.Set("If-Modified-Since", entry.LastModified)
+			}
+		}
+
+		res, err := next(req)
+		if err != nil {
+			return nil, err
+		}
+		s.stats.cacheLookup(ok && res.StatusCode == http.StatusNotModified)
+		if ok && res.StatusCode == http.StatusNotModified {
+			res.Body.Close()
+			return &http.Response{
+				Status:        "200 OK",
+				StatusCode:    http.StatusOK,
+				Proto:         res.Proto,
+				ProtoMajor:    res.ProtoMajor,
+				ProtoMinor:    res.ProtoMinor,
+				Header:        entry.Header.Clone(),
+				Body:          ioutil.NopCloser(bytes.NewReader(entry.Body)),
+				ContentLength: int64(len(entry.Body)),
+				Request:       req,
+			}, nil
+		}
+
+		etag, modified := res.Header.Get("ETag"), res.Header.Get("Last-Modified")
+		if res.StatusCode != http.StatusOK || etag == "" && modified == "" {
+			return res, nil
+		}
+		// the body is buffered before execute applies the maximum response size, so it is capped here too,
+		// downloads and streams skip the cache and are never buffered
+		var rd io.Reader = res.Body
+		if s.maxResponseSize > 0 {
+			rd = &cappedReader{r: rd, left: s.maxResponseSize}
+		}
+		body, err := ioutil.ReadAll(rd)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(key, &CachedResponse{Body: body, Header: res.Header.Clone(), ETag: etag, LastModified: modified, Stored: s.now()})
+		res.Body = ioutil.NopCloser(bytes.NewReader(body))
+		return res, nil
+	}
+}
+
+// withSkipCache marks requests sent with ctx to bypass the cache.
+func withSkipCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipCacheKey{}, true)
+}
+
+// LRUCache is an in-memory Cache that evicts the least recently used responses.
+type LRUCache struct {
+	size int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key string
+	res *CachedResponse
+}
+
+// NewLRUCache creates a cache holding up to size responses, zero means no limit.
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Get returns the cached response.
+func (c *LRUCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*lruEntry).res, true
+}
+
+// Set stores the response, evicting the least recently used response if the cache is full.
+func (c *LRUCache) Set(key string, res *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value = &lruEntry{key: key, res: res}
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, res: res})
+	for c.size > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of cached responses.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
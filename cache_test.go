@@ -0,0 +1,95 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	var sent, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"title":"Season 2020"}`))
+	}))
+	defer server.Close()
+
+	cache := NewLRUCache(10)
+	c := NewClient(WithBaseURL(server.URL), WithCache(cache))
+	for i := 0; i < 3; i++ {
+		var v struct {
+			Title string `json:"title"`
+		}
+		if err := c.Do(c.NewRequest("sets", "set_1"), &v); err != nil {
+			t.Fatal(err)
+		}
+		if v.Title != "Season 2020" {
+			t.Error("incorrect title", v.Title)
+		}
+	}
+	if sent != 3 || notModified != 2 {
+		t.Error("expected conditional requests", sent, notModified)
+	}
+	if cache.Len() != 1 {
+		t.Error("incorrect number of cached responses", cache.Len())
+	}
+
+	if err := c.Do(c.NewRequest("sets", "set_1").SkipCache(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if notModified != 2 {
+		t.Error("SkipCache sent a conditional request")
+	}
+
+	e, err := c.NewRequest("sets", "set_1").Explain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.Cached {
+		t.Error("explanation is not cached")
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", &CachedResponse{ETag: "a"})
+	cache.Set("b", &CachedResponse{ETag: "b"})
+	cache.Get("a")
+	cache.Set("c", &CachedResponse{ETag: "c"})
+	if _, ok := cache.Get("b"); ok {
+		t.Error("least recently used response was not evicted")
+	}
+	if res, ok := cache.Get("a"); !ok || res.ETag != "a" {
+		t.Error("recently used response was evicted")
+	}
+}
+
+func TestCacheMaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"title":"` + strings.Repeat("a", 10*1024) + `"}`))
+	}))
+	defer server.Close()
+
+	cache := NewLRUCache(10)
+	c := NewClient(WithBaseURL(server.URL), WithCache(cache), WithMaxResponseSize(1024))
+	r := c.NewRequest("sets", "set_1")
+	if err := r.Execute(&struct{}{}); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatal("expected ErrResponseTooLarge, got", err)
+	}
+	u, err := c.current().buildURL(r, c.current().endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get(u.String()); ok {
+		t.Error("response over the maximum size was cached")
+	}
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// ErrChaos is the network error injected by ChaosMiddleware.
+var ErrChaos = errors.New("injected failure")
+
+// Chaos configures the failures injected by ChaosMiddleware.
+type Chaos struct {
+	// Fraction is the fraction of requests that are affected, between 0 and 1.
+	Fraction float64
+	// Latency delays affected requests by a random duration up to Latency.
+	Latency time.Duration
+	// ErrorFraction is the fraction of affected requests that fail instead of being sent.
+	ErrorFraction float64
+	// StatusCode is the status of the responses of failed requests, zero fails them with ErrChaos like a network error.
+	StatusCode int
+	// Rand returns random numbers in [0, 1), it defaults to math/rand.
+	Rand func() float64
+}
+
+// ChaosMiddleware injects latency and failures into a fraction of requests, to test timeouts and fallbacks
+// against realistic failures. It is meant for non-production environments.
+//
+//	c.Use(client.ChaosMiddleware(client.Chaos{Fraction: 0.1, Latency: 2 * time.Second, ErrorFraction: 0.5, StatusCode: 503}))
+func ChaosMiddleware(chaos Chaos) Middleware {
+	random := chaos.Rand
+	if random == nil {
+		random = rand.Float64
+	}
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if random() >= chaos.Fraction {
+				return next(req)
+			}
+			if chaos.Latency > 0 {
+				if err := sleep(req.Context(), time.Duration(random()*float64(chaos.Latency))); err != nil {
+					return nil, err
+				}
+			}
+			if random() >= chaos.ErrorFraction {
+				return next(req)
+			}
+			if chaos.StatusCode == 0 {
+				return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrChaos)
+			}
+			body := []byte(ErrChaos.Error())
+			return &http.Response{
+				Status:        fmt.Sprintf("%d %s", chaos.StatusCode, http.StatusText(chaos.StatusCode)),
+				StatusCode:    chaos.StatusCode,
+				Proto:         "HTTP/1.1",
+				ProtoMajor:    1,
+				ProtoMinor:    1,
+				Header:        http.Header{"Content-Type": {"text/plain"}},
+				Body:          ioutil.NopCloser(bytes.NewReader(body)),
+				ContentLength: int64(len(body)),
+				Request:       req,
+			}, nil
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChaosMiddleware(t *testing.T) {
+	var sent int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	half := func() float64 { return 0.5 }
+	tests := []struct {
+		chaos Chaos
+		check func(error) bool
+		sent  int
+	}{
+		{Chaos{Fraction: 0.4, ErrorFraction: 1, Rand: half}, func(err error) bool { return err == nil }, 1},
+		{Chaos{Fraction: 1, ErrorFraction: 0.4, Latency: 20 * time.Millisecond, Rand: half}, func(err error) bool { return err == nil }, 1},
+		{Chaos{Fraction: 1, ErrorFraction: 1, StatusCode: http.StatusServiceUnavailable, Rand: half}, func(err error) bool {
+			var apiErr *APIError
+			return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusServiceUnavailable
+		}, 0},
+		{Chaos{Fraction: 1, ErrorFraction: 1, Rand: half}, func(err error) bool { return errors.Is(err, ErrChaos) }, 0},
+	}
+	for i, test := range tests {
+		sent = 0
+		c := NewClient(WithBaseURL(server.URL))
+		c.Use(ChaosMiddleware(test.chaos))
+		start := time.Now()
+		err := c.Do(c.NewRequest("sets", "set_1"), nil)
+		if !test.check(err) {
+			t.Errorf("%d: unexpected error %v", i, err)
+		}
+		if sent != test.sent {
+			t.Errorf("%d: incorrect number of sent requests %d", i, sent)
+		}
+		if test.chaos.Latency > 0 && time.Since(start) < test.chaos.Latency/2 {
+			t.Errorf("%d: latency was not injected", i)
+		}
+	}
+}
@@ -0,0 +1,672 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Client executes requests against a Skylark API.
+// The zero value is not usable, create clients with NewClient.
+// A client is safe for concurrent use and can be reconfigured at runtime with UpdateConfig.
+type Client struct {
+	mu       sync.RWMutex
+	settings *settings
+	life     lifecycle
+	inflight sync.WaitGroup
+}
+
+// settings holds a client's configuration.
+// Once a client uses them they are never modified, UpdateConfig replaces them with an updated copy.
+type settings struct {
+	endpoint   string
+	httpClient *http.Client
+	header     http.Header
+	retry      RetryPolicy
+	pageSize   int
+	err        error
+
+	version      string
+	versionStyle VersionStyle
+
+	defaultFields  map[string][]*Field
+	identityFields bool
+	resolver       EndpointResolver
+	flags          map[string]bool
+
+	bulkConcurrency int
+	validators      map[string][]Validator
+	redacted        map[string]bool
+	signer          Signer
+	pins            *pinSet
+	// transportOptions are the changes of options like WithTLSMinVersion to the transport, see modifyTransport.
+	transportOptions []func(*http.Transport)
+	audit            AuditSink
+	secretHeaders    []secretHeader
+	maskedParams     []string
+	maskedHeaders    []string
+	headerAllowlist  map[string]bool
+	contextProxy     bool
+	logger           Logger
+	pathTemplates    map[string]string
+	objectCache      *ObjectCache
+	dryRun           bool
+	compressMin      int
+	timeEncoder      TimeEncoder
+	maxObjects       int
+	objectLimitWarn  func(*Request, *ObjectLimitError)
+	maxURLLength     int
+	auth             Authenticator
+	stale            *ObjectCache
+	queries          Queries
+	middleware       []Middleware
+	cache            Cache
+	instrumentor     Instrumentor
+	tagValues        *tagValues
+	redirect         *RedirectPolicy
+	ipPreference     IPPreference
+	fallbackDelay    time.Duration
+	maxResponseSize  int64
+	clock            Clock
+	sleeper          Sleeper
+	debug            io.Writer
+	limiter          *rateLimiter
+	fieldFallback    bool
+	fieldsDropped    func(*Request, []string)
+	envelope         EnvelopeStrategy
+	deprecated       map[string]map[string]string
+	deprecationWarn  func(*Request, *DeprecationWarning)
+	sampling         *Sampling
+	fieldDecoders    map[string]map[string]FieldDecoder
+	expandFallback   bool
+	stats            *clientStats
+	queryEncoder     QueryEncoder
+	noPanics         bool
+	costs            *costMeter
+}
+
+// Option configures a Client.
+type Option func(*settings)
+
+// NewClient creates a new client with the given options.
+func NewClient(opts ...Option) *Client {
+	s := &settings{
+		httpClient:      &http.Client{},
+		header:          make(http.Header),
+		maxURLLength:    defaultMaxURLLength,
+		maxResponseSize: defaultMaxResponseSize,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return newClient(s)
+}
+
+func newClient(s *settings) *Client {
+	s.stats = &clientStats{}
+	return &Client{settings: s, life: lifecycle{shutdown: make(chan struct{})}}
+}
+
+// UpdateConfig applies the options to the client's current configuration.
+// Requests that are already executing finish with the previous configuration,
+// retries and new requests use the updated one.
+// If the updated configuration is invalid it is discarded and the error is returned.
+func (c *Client) UpdateConfig(opts ...Option) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.settings.clone()
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.err != nil {
+		return s.err
+	}
+	c.settings = s
+	return nil
+}
+
+func (c *Client) current() *settings {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings
+}
+
+// clone returns a copy of the settings that options can modify without affecting s.
+func (s *settings) clone() *settings {
+	clone := *s
+	httpClient := *s.httpClient
+	clone.httpClient = &httpClient
+	clone.header = s.header.Clone()
+	clone.defaultFields = make(map[string][]*Field, len(s.defaultFields))
+	for collection, fields := range s.defaultFields {
+		clone.defaultFields[collection] = append([]*Field(nil), fields...)
+	}
+	clone.validators = make(map[string][]Validator, len(s.validators))
+	for collection, validators := range s.validators {
+		clone.validators[collection] = append([]Validator(nil), validators...)
+	}
+	clone.secretHeaders = append([]secretHeader(nil), s.secretHeaders...)
+	clone.middleware = append([]Middleware(nil), s.middleware...)
+	clone.maskedParams = append([]string(nil), s.maskedParams...)
+	clone.maskedHeaders = append([]string(nil), s.maskedHeaders...)
+	clone.pathTemplates = make(map[string]string, len(s.pathTemplates))
+	for collection, template := range s.pathTemplates {
+		clone.pathTemplates[collection] = template
+	}
+	clone.redacted = make(map[string]bool, len(s.redacted))
+	for field := range s.redacted {
+		clone.redacted[field] = true
+	}
+	clone.queries = make(Queries, len(s.queries))
+	for name, q := range s.queries {
+		clone.queries[name] = q
+	}
+	clone.deprecated = make(map[string]map[string]string, len(s.deprecated))
+	for collection, fields := range s.deprecated {
+		clone.deprecated[collection] = make(map[string]string, len(fields))
+		for field, replacement := range fields {
+			clone.deprecated[collection][field] = replacement
+		}
+	}
+	clone.fieldDecoders = make(map[string]map[string]FieldDecoder, len(s.fieldDecoders))
+	for collection, decoders := range s.fieldDecoders {
+		clone.fieldDecoders[collection] = make(map[string]FieldDecoder, len(decoders))
+		for field, dec := range decoders {
+			clone.fieldDecoders[collection][field] = dec
+		}
+	}
+	clone.flags = make(map[string]bool, len(s.flags))
+	for flag := range s.flags {
+		clone.flags[flag] = true
+	}
+	return &clone
+}
+
+// WithHTTPClient sets the HTTP client used to send requests, for example to use a custom transport.
+// The client is copied, later options don't modify it. Transport options like WithTLSMinVersion and
+// WithPinnedPublicKeys are applied to a copy of its transport regardless of the order of the options,
+// so its transport must be an *http.Transport if any are used.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(s *settings) {
+		if httpClient == nil {
+			httpClient = &http.Client{}
+		}
+		hc := *httpClient
+		s.httpClient = &hc
+		for _, fn := range s.transportOptions {
+			s.applyTransport(fn)
+		}
+	}
+}
+
+// WithBaseURL sets the endpoint used for requests that don't specify one.
+// An invalid endpoint makes every request fail with an error wrapping ErrInvalidEndpoint.
+func WithBaseURL(endpoint string) Option {
+	return func(s *settings) {
+		s.endpoint, s.err = normalizeEndpoint(endpoint)
+	}
+}
+
+// WithTimeout sets the time limit for each HTTP request made by the client.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *settings) {
+		s.httpClient.Timeout = timeout
+	}
+}
+
+// WithHeader sets a header that is sent with every request.
+func WithHeader(key, value string) Option {
+	return func(s *settings) {
+		s.header.Set(key, value)
+	}
+}
+
+// WithDefaultHeaders sets headers that are sent with every request, replacing previously set values of the same keys.
+func WithDefaultHeaders(header http.Header) Option {
+	return func(s *settings) {
+		for key, values := range header {
+			s.header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(s *settings) {
+		s.retry = p
+	}
+}
+
+// WithPageSize sets the limit used for collection requests that don't set one.
+func WithPageSize(n int) Option {
+	return func(s *settings) {
+		s.pageSize = n
+	}
+}
+
+// NewRequest creates a request that uses the client's endpoint.
+func (c *Client) NewRequest(collection, id string) *Request {
+	r := NewRequest("", collection, id)
+	r.client = c
+	return r
+}
+
+// Do executes the request and writes it's results to the value pointed to by v.
+// Collection requests whose URL would be too long are split into concurrent requests for parts of
+// the values of their largest In filter, the objects of all responses are merged into one response.
+func (c *Client) Do(r *Request, v interface{}) error {
+	return c.do(r, v, nil)
+}
+
+// DoResult executes the request like Do and returns how it was executed.
+// The result is returned even if the request failed.
+func (c *Client) DoResult(r *Request, v interface{}) (*Result, error) {
+	res := &Result{}
+	err := c.do(r, v, res)
+	return res, err
+}
+
+func (c *Client) do(r *Request, v interface{}, res *Result) (err error) {
+	defer c.recoverPanic(&err)
+	if r.dedupe != nil && v != nil && r.ID == "" {
+		var raw json.RawMessage
+		if err := c.do(r.withoutDedupe(), &raw, res); err != nil {
+			return err
+		}
+		return c.current().dedupeObjects(r.dedupe, raw, v)
+	}
+	if decoders := c.current().fieldDecoders[r.Collection]; len(decoders) > 0 && v != nil && !r.rawFields {
+		var raw json.RawMessage
+		if err := c.do(r.withoutFieldDecoding(), &raw, res); err != nil {
+			return err
+		}
+		return decodeFields(decoders, raw, v)
+	}
+	if len(r.postProcessors) > 0 && v != nil {
+		var raw json.RawMessage
+		if err := c.do(r.withoutPostProcessing(), &raw, res); err != nil {
+			return err
+		}
+		return c.current().postProcess(r.postProcessors, raw, v)
+	}
+	if parts := c.splitIn(r); parts != nil {
+		return c.doSplit(parts, v, res)
+	}
+	if err := c.transmit(r, http.MethodGet, nil, jsonContentType, v, res); err != nil {
+		if c.current().expandFallback && rejectsExpansion(r, err) {
+			return c.expandSeparately(r, v, res, err)
+		}
+		return c.fallback(r, v, res, err)
+	}
+	return nil
+}
+
+// jsonContentType is the content type of request bodies sent by send.
+const jsonContentType = "application/json"
+
+// send executes the request with the given method and JSON encoded body.
+// Only requests with idempotent methods are retried.
+func (c *Client) send(r *Request, method string, body interface{}, v interface{}) (err error) {
+	defer c.recoverPanic(&err)
+	var payload []byte
+	if body != nil {
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+	return c.transmit(r, method, payload, jsonContentType, v, nil)
+}
+
+// transmit executes the request with the given method and encoded body.
+// Validators only run for JSON bodies. If res is set the attempts are recorded in it.
+func (c *Client) transmit(r *Request, method string, payload []byte, contentType string, v interface{}, res *Result) (err error) {
+	ctx, end, err := c.begin(r.ctx)
+	if err != nil {
+		return err
+	}
+	defer end()
+	r = r.tagged()
+	cost, err := c.current().charge(r, method)
+	if err != nil {
+		return err
+	}
+	if cost > 0 {
+		costed := *r
+		costed.ctx = ContextWithMetadata(r.ctx, "cost", formatCost(cost))
+		ctx = ContextWithMetadata(ctx, "cost", formatCost(cost))
+		r = &costed
+		if res != nil {
+			res.Cost += cost
+		}
+	}
+	ctx, finish := c.current().instrument(ctx, r, method)
+
+	var (
+		s         *settings
+		u         *url.URL
+		status    int
+		attempt   int
+		refreshed bool
+		start     = c.current().now()
+	)
+	defer func() {
+		if err != nil && attempt > 0 && s.serveStale(r.ctx, method, u, v, err) {
+			err = nil
+			if res != nil {
+				res.Stale = true
+			}
+		}
+		if res != nil {
+			res.NotModified = status == http.StatusNotModified
+			res.StatusCode = status
+			res.Attempts += attempt
+			res.Duration = c.current().now().Sub(start)
+		}
+		// a 304 is returned as ErrNotModified, but the request succeeded
+		failure := err
+		if IsNotModified(err) {
+			failure = nil
+		}
+		if s != nil {
+			s.stats.request(r.Collection, attempt, failure)
+		}
+		if s != nil && s.audit != nil {
+			s.audit.Audit(s.auditRecord(r, method, u, status, attempt, start, failure))
+		}
+		finish(status, attempt, failure)
+	}()
+
+	c.current().warnDeprecated(r)
+	for {
+		s = c.current()
+		if s.err != nil {
+			return s.err
+		}
+		if contentType == jsonContentType {
+			if err := s.validate(r.Collection, method, payload); err != nil {
+				return err
+			}
+		}
+		if err := s.checkProxy(r); err != nil {
+			return err
+		}
+		endpoint, resolved, err := s.endpointFor(r)
+		if err != nil {
+			return err
+		}
+		u, err = s.buildURL(r, endpoint)
+		if err != nil {
+			return err
+		}
+		if err := s.checkObjectLimit(r, method, u); err != nil {
+			return err
+		}
+		if s.dryRun || r.dryRun {
+			return s.dryRunCall(&call{ctx: r.ctx, method: method, url: u, payload: payload, contentType: contentType, request: r})
+		}
+		attempt++
+		attemptCtx, cancel := s.retry.attemptContext(ctx, attempt)
+		attemptStart := s.now()
+		target, raw := s.staleTarget(method, v)
+		status, err = s.do(&call{ctx: attemptCtx, method: method, url: u, payload: payload, contentType: contentType, request: r}, target)
+		cancel()
+		if err == nil && raw != nil {
+			err = s.keepStale(u, raw, v)
+		}
+		if res != nil {
+			res.AttemptDurations = append(res.AttemptDurations, s.now().Sub(attemptStart))
+		}
+		if err != nil && ctx.Err() != nil {
+			// the caller gave up, which says nothing about the endpoint
+			return ctx.Err()
+		}
+		if IsNotModified(err) {
+			// the caller's copy is current, so the endpoint answered and nothing is logged
+			if resolved != "" {
+				s.resolver.Report(resolved, nil)
+			}
+			return err
+		}
+		if resolved != "" {
+			s.resolver.Report(resolved, err)
+		}
+		if err == nil {
+			if s.objectCache != nil && r.ID != "" && method != http.MethodGet && method != http.MethodHead {
+				s.objectCache.Remove(r.Collection, r.ID)
+			}
+			return nil
+		}
+		if !refreshed && s.refreshAuth(ctx, err) {
+			refreshed = true
+			continue
+		}
+		if !idempotent(method) || !s.retry.shouldRetry(ctx, attempt, err) {
+			s.log(r.ctx, "request failed", "method", method, "url", s.maskURL(u), "attempt", attempt, "error", err)
+			return err
+		}
+		delay := s.retry.delay(attempt, err)
+		if !fitsDeadline(ctx, delay, s.now()) {
+			s.log(r.ctx, "request failed, no time left to retry", "method", method, "url", s.maskURL(u), "attempt", attempt, "error", err)
+			return err
+		}
+		s.log(r.ctx, "retrying request", "method", method, "url", s.maskURL(u), "attempt", attempt, "delay", delay, "error", err)
+		if err := s.sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// url returns the URL the client would use for the request.
+func (c *Client) url(r *Request) (*url.URL, error) {
+	s := c.current()
+	if s.err != nil {
+		return nil, s.err
+	}
+	endpoint, _, err := s.endpointFor(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildURL(r, endpoint)
+}
+
+// endpointFor returns the endpoint for the request.
+// If it was picked by the client's resolver, the unnormalized resolver result is returned as well.
+func (s *settings) endpointFor(r *Request) (string, string, error) {
+	if r.Endpoint != "" {
+		return r.Endpoint, "", nil
+	}
+	if s.resolver != nil {
+		resolved, err := s.resolver.Endpoint(r.ctx)
+		if err != nil {
+			return "", "", err
+		}
+		endpoint, err := normalizeEndpoint(resolved)
+		return endpoint, resolved, err
+	}
+	if s.endpoint == "" {
+		return "", "", fmt.Errorf("%w: no endpoint configured", ErrInvalidEndpoint)
+	}
+	return s.endpoint, "", nil
+}
+
+func (s *settings) buildURL(r *Request, endpoint string) (*url.URL, error) {
+	temp := *r
+	temp.memo = nil
+	temp.Endpoint = endpoint
+	if s.version != "" && s.versionStyle == VersionPath {
+		temp.Endpoint += s.version + "/"
+	}
+	temp.Fields = s.withDefaultFields(r)
+	temp.timeEncoder = s.timeEncoder
+	if template, ok := s.pathTemplates[r.Collection]; ok {
+		path, err := renderPath(template, r)
+		if err != nil {
+			return nil, err
+		}
+		temp.path = path
+	}
+	r = &temp
+	u, err := r.ToURL()
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	if s.pageSize > 0 && r.ID == "" && q.Get("limit") == "" {
+		q.Set("limit", strconv.Itoa(s.pageSize))
+	}
+	for _, param := range r.experimental {
+		if s.flags[param.flag] {
+			q.Add(param.key, param.value)
+		}
+	}
+	u.RawQuery = s.encodeQuery(r, q)
+	return u, nil
+}
+
+// call holds everything needed to make a single HTTP request.
+type call struct {
+	ctx         context.Context
+	method      string
+	url         *url.URL
+	payload     []byte
+	contentType string
+	// request is the request being executed, it is nil for raw calls like replays.
+	request *Request
+}
+
+// do makes a single HTTP request and returns the response status code.
+func (s *settings) do(c *call, v interface{}) (int, error) {
+	req, err := s.newHTTPRequest(c)
+	if err != nil {
+		return 0, err
+	}
+	status, err := s.execute(req, v)
+	return status, mask(err, s.sensitiveValues(req))
+}
+
+// newHTTPRequest creates the HTTP request with all headers set.
+func (s *settings) newHTTPRequest(c *call) (*http.Request, error) {
+	payload, compressed, err := s.compress(c)
+	if err != nil {
+		return nil, err
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	ctx := c.ctx
+	if c.request != nil && c.request.skipCache {
+		ctx = withSkipCache(ctx)
+	}
+	if c.request != nil && c.request.debug != nil {
+		ctx = withDebug(ctx, c.request.debug)
+	}
+	if c.request != nil && c.request.proxy != nil {
+		ctx = ContextWithProxy(ctx, c.request.proxy)
+	}
+	req, err := http.NewRequestWithContext(ctx, c.method, c.url.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", c.contentType)
+		if compressed {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+		if c.request != nil && c.request.uploadProgress != nil {
+			req.Body = ioutil.NopCloser(&progressReader{r: body, total: req.ContentLength, fn: c.request.uploadProgress})
+		}
+	}
+	for key, values := range s.header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if c.request != nil && !c.request.ifModifiedSince.IsZero() {
+		req.Header.Set("If-Modified-Since", c.request.ifModifiedSince.UTC().Format(http.TimeFormat))
+	}
+	if s.version != "" && s.versionStyle == VersionHeader {
+		req.Header.Set(VersionHeaderName, s.version)
+	}
+	for _, h := range s.secretHeaders {
+		value, err := h.value(c.ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(h.key, value)
+	}
+	if s.auth != nil {
+		if err := s.auth.Authenticate(req); err != nil {
+			return nil, err
+		}
+	}
+	if c.request != nil {
+		for _, finalize := range c.request.finalizers {
+			if err := finalize(req); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if s.signer != nil {
+		if err := s.signer.Sign(req, payload); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// execute sends the HTTP request and decodes a successful response into v.
+func (s *settings) execute(req *http.Request, v interface{}) (int, error) {
+	res, err := s.roundTrip(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if header, ok := req.Context().Value(responseHeaderKey{}).(*responseHeader); ok {
+		header.set(res.Header)
+	}
+	body, err := s.responseBody(res)
+	if err != nil {
+		return res.StatusCode, err
+	}
+	if w := s.debugWriter(req); w != nil {
+		if body, err = s.dump(w, req, res, body); err != nil {
+			return res.StatusCode, err
+		}
+	}
+	if body, err = s.sample(req, res, body); err != nil {
+		return res.StatusCode, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		message, err := ioutil.ReadAll(body)
+		if err != nil {
+			return res.StatusCode, fmt.Errorf("Unable to read error message from server: %w", err)
+		}
+		return res.StatusCode, newAPIError(req, res, redact(message, s.redacted), s)
+	}
+
+	if v == nil || res.StatusCode == http.StatusNoContent {
+		return res.StatusCode, nil
+	}
+	if st, ok := v.(*objectStream); ok {
+		return res.StatusCode, st.decode(body)
+	}
+	return res.StatusCode, json.NewDecoder(body).Decode(v)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -1,10 +1,13 @@
 package client
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -36,6 +39,19 @@ func TestMultipleExpansion(t *testing.T) {
 	testURL(request, "https://test.com/api/session-occurrence/?fields=channel_urls,channel_urls__self,channel_urls__name,channel_urls__driver_urls,channel_urls__driver_urls__driver_racingnumber,channel_urls__driver_urls__team_url,channel_urls__driver_urls__team_url__name,channel_urls__driver_urls__team_url__colour&fields_to_expand=channel_urls,channel_urls__driver_urls,channel_urls__driver_urls__team_url&slug=test", t)
 }
 
+func TestExpandChild(t *testing.T) {
+	request := NewRequest("https://test.com/api/", "episodes", "").
+		AddField(NewField("title")).
+		AddField(NewField("driver_urls").
+			ExpandChild(NewField("team_url").
+				WithSubField(NewField("name"))).
+			ExpandChild(NewField("image_urls").
+				WithSubField(NewField("url")).
+				WithSubField(NewField("type"))))
+
+	testURL(request, "https://test.com/api/episodes/?fields=title,driver_urls,driver_urls__team_url,driver_urls__team_url__name,driver_urls__image_urls,driver_urls__image_urls__url,driver_urls__image_urls__type&fields_to_expand=driver_urls,driver_urls__team_url,driver_urls__image_urls", t)
+}
+
 func TestRequestFilter(t *testing.T) {
 	request := NewRequest("https://test.com/api/", "sets", "").
 		AddField(NewField("title")).
@@ -56,6 +72,24 @@ func TestOrder(t *testing.T) {
 	testURL(request, "https://test.com/api/race-season/?fields=year,name,self&order=year", t)
 }
 
+func TestMultiFieldOrder(t *testing.T) {
+	year := NewField("year")
+	name := NewField("name")
+	request := NewRequest("https://test.com/api/", "race-season", "").
+		AddField(year).
+		AddField(name).
+		OrderByDesc(year).
+		OrderBy(name)
+
+	testURL(request, "https://test.com/api/race-season/?fields=year,name&order=-year,name", t)
+	if order := request.QueryParams().Get("order"); order != "-year,name" {
+		t.Error("incorrect order", order)
+	}
+
+	request.OrderBy(year)
+	testURL(request, "https://test.com/api/race-season/?fields=year,name&order=year,name", t)
+}
+
 func TestFieldFilter(t *testing.T) {
 	request := NewRequest("https://test.com/api/", "race-season", "").
 		AddField(NewField("year").
@@ -66,6 +100,23 @@ func TestFieldFilter(t *testing.T) {
 	testURL(request, "https://test.com/api/race-season/?fields=year,name,self&year__gt=2017", t)
 }
 
+func TestMultipleFilters(t *testing.T) {
+	request := NewRequest("https://test.com/api/", "race-season", "").
+		AddField(NewField("name")).
+		WithFilter("year", NewRangeFilter(2017, 2019)).
+		WithFilter("uid", NewInFilter("a", "b", "c")).
+		WithFilter("tag", NewFilter(Equals, "x")).
+		WithFilter("tag", NewFilter(Equals, "y")).
+		WithFilter("name", NewNotFilter(NewFilter(StartsWith, "F")))
+
+	testURL(request, "https://test.com/api/race-season/?fields=name&year__gte=2017&year__lte=2019&uid__in=a,b,c&tag=x&tag=y&name__not_startswith=F", t)
+
+	if _, err := NewRequest("https://test.com/api/", "race-season", "").
+		WithFilter("year", NewNotFilter(NewRangeFilter(2017, 2019))).ToURL(); err == nil {
+		t.Error("expected an error for a negated range")
+	}
+}
+
 func TestExpandedField(t *testing.T) {
 	request := NewRequest("https://test.com/api/", "driver", driverID).
 		AddField(NewField("first_name")).
@@ -84,6 +135,254 @@ func TestAllFields(t *testing.T) {
 	testURL(request, "https://test.com/api/driver/driv_123/", t)
 }
 
+func TestEndpointNormalization(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		expected string
+		valid    bool
+	}{
+		{"https://test.com/api/", "https://test.com/api/", true},
+		{"https://test.com/api", "https://test.com/api/", true},
+		{" http://test.com ", "http://test.com/", true},
+		{"test.com/api/", "", false},
+		{"ftp://test.com/api/", "", false},
+		{"https:///api/", "", false},
+		{"https://test.com/api/?key=value", "", false},
+	}
+
+	for _, test := range tests {
+		request := NewRequest(test.endpoint, "driver", driverID)
+		_, err := request.ToURL()
+		if !test.valid {
+			if !errors.Is(err, ErrInvalidEndpoint) {
+				t.Errorf("expected invalid endpoint error for %q, got %v", test.endpoint, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", test.endpoint, err)
+		}
+		if request.Endpoint != test.expected {
+			t.Errorf("incorrect endpoint\nexpected: %s\ngot:      %s", test.expected, request.Endpoint)
+		}
+	}
+}
+
+func TestURLBuilding(t *testing.T) {
+	tests := []struct {
+		name       string
+		endpoint   string
+		collection string
+		id         string
+		expected   string
+		err        error
+	}{
+		{"collection", "https://test.com/api", "sets", "", "https://test.com/api/sets/", nil},
+		{"object", "https://test.com/api/", "sets", "set_1", "https://test.com/api/sets/set_1/", nil},
+		{"escaped endpoint", "https://test.com/my%20api/", "sets", "set_1", "https://test.com/my%20api/sets/set_1/", nil},
+		{"id with slash", "https://test.com/api/", "sets", "../admin", "https://test.com/api/sets/..%2Fadmin/", nil},
+		{"id with query", "https://test.com/api/", "sets", "set_1?fields=secret", "https://test.com/api/sets/set_1%3Ffields=secret/", nil},
+		{"collection with space", "https://test.com/api/", "race results", "", "https://test.com/api/race%20results/", nil},
+		{"dot dot id", "https://test.com/api/", "sets", "..", "", ErrInvalidPath},
+		{"dot collection", "https://test.com/api/", ".", "set_1", "", ErrInvalidPath},
+		{"empty collection", "https://test.com/api/", "", "set_1", "", ErrInvalidPath},
+		{"control character", "https://test.com/api/", "sets", "set\n1", "", ErrInvalidPath},
+		{"invalid endpoint", "test.com", "sets", "", "", ErrInvalidEndpoint},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			u, err := NewRequest(test.endpoint, test.collection, test.id).ToURL()
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Errorf("expected %v, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.String() != test.expected {
+				t.Errorf("incorrect URL\nexpected: %s\ngot:      %s", test.expected, u)
+			}
+		})
+	}
+}
+
+func TestAPIVersionPath(t *testing.T) {
+	c := NewClient(WithBaseURL("https://test.com/api"), WithAPIVersion("v2", VersionPath))
+
+	testClientURL(c, c.NewRequest("driver", driverID), "https://test.com/api/v2/driver/driv_123/", t)
+}
+
+func TestDefaultFields(t *testing.T) {
+	c := NewClient(
+		WithBaseURL("https://test.com/api/"),
+		WithDefaultFields("driver", NewField("uid"), NewField("self")),
+		WithDefaultExpansions("driver", NewField("team_url")))
+
+	request := c.NewRequest("driver", driverID).
+		AddField(NewField("first_name"))
+	testClientURL(c, request, "https://test.com/api/driver/driv_123/?fields=first_name,uid,self&fields_to_expand=team_url", t)
+
+	request = c.NewRequest("driver", driverID)
+	testClientURL(c, request, "https://test.com/api/driver/driv_123/?fields_to_expand=team_url", t)
+
+	request = c.NewRequest("team", teamID)
+	testClientURL(c, request, "https://test.com/api/team/team_123/", t)
+
+	// selecting only sub fields of an expanded field selects fields too
+	request = c.NewRequest("driver", driverID).
+		Expand(NewField("team_url").WithSubField(NewField("name")))
+	testClientURL(c, request, "https://test.com/api/driver/driv_123/?fields=team_url__name,uid,self&fields_to_expand=team_url", t)
+
+	team := NewField("team_url")
+	WithDefaultExpansions("driver", team)
+	if team.IsExpanded || !team.IsIncluded {
+		t.Error("WithDefaultExpansions modified the given field")
+	}
+}
+
+func TestIdentityFields(t *testing.T) {
+	c := NewClient(WithBaseURL("https://test.com/api/"), WithIdentityFields())
+
+	request := c.NewRequest("driver", driverID).
+		AddField(NewField("first_name"))
+	testClientURL(c, request, "https://test.com/api/driver/driv_123/?fields=first_name,self,uid", t)
+
+	request = c.NewRequest("driver", "").
+		WithFilter("last_name", NewFilter(Equals, "Hamilton"))
+	testClientURL(c, request, "https://test.com/api/driver/?last_name=Hamilton", t)
+
+	request = c.NewRequest("driver", driverID).
+		AddField(NewField("team_url").WithSubField(NewField("name")))
+	testClientURL(c, request, "https://test.com/api/driver/driv_123/?fields=self,team_url,team_url__name,uid&fields_to_expand=team_url", t)
+}
+
+func TestExperimentalParams(t *testing.T) {
+	c := NewClient(WithBaseURL("https://test.com/api/"), WithFeatureFlags("search"))
+
+	request := c.NewRequest("sets", "").
+		WithExperimentalParam("search", "q", "monaco").
+		WithExperimentalParam("facets", "facet", "year")
+	testClientURL(c, request, "https://test.com/api/sets/?q=monaco", t)
+}
+
+func TestPathTemplate(t *testing.T) {
+	c := NewClient(WithBaseURL("https://test.com/api/v2/"), WithPathTemplate("items", "sets/{set}/items/{id}/"))
+
+	request := c.NewRequest("items", "item_1").WithPathParam("set", "set_123")
+	testClientURL(c, request, "https://test.com/api/v2/sets/set_123/items/item_1/", t)
+
+	request = c.NewRequest("items", "").WithPathParam("set", "set 123")
+	testClientURL(c, request, "https://test.com/api/v2/sets/set%20123/items/", t)
+
+	if _, err := c.url(c.NewRequest("items", "item_1")); err == nil {
+		t.Error("expected error for missing path param")
+	}
+	if _, err := c.url(c.NewRequest("items", "item_1").WithPathParam("set", "..")); !errors.Is(err, ErrInvalidPath) {
+		t.Error("expected ErrInvalidPath for a path param leaving the path, got", err)
+	}
+	if _, err := c.url(c.NewRequest("items", "..").WithPathParam("set", "set_123")); !errors.Is(err, ErrInvalidPath) {
+		t.Error("expected ErrInvalidPath for an ID leaving the path, got", err)
+	}
+
+	c = NewClient(WithBaseURL("https://test.com/api/v2/"), WithPathTemplate("items", "items/"))
+	if _, err := c.url(c.NewRequest("items", "..")); !errors.Is(err, ErrInvalidPath) {
+		t.Error("expected ErrInvalidPath for an ID of a template without it, got", err)
+	}
+}
+
+var overlap = RegisterComparator("overlap", func(value string) error {
+	if value == "" {
+		return errors.New("empty range")
+	}
+	return nil
+})
+
+func TestRegisterComparator(t *testing.T) {
+	request := NewRequest("https://test.com", "sessions", "").WithFilter("window", NewFilter(overlap, "2020,2021"))
+	testURL(request, "https://test.com/sessions/?window__overlap=2020%2C2021", t)
+
+	if _, err := NewRequest("https://test.com", "sessions", "").WithFilter("window", NewFilter(overlap, "")).ToURL(); err == nil {
+		t.Error("expected error for invalid value")
+	}
+	field := NewField("window").WithFilter(NewFilter("within", "2020"))
+	if _, err := NewRequest("https://test.com", "sessions", "").AddField(field).ToURL(); err == nil {
+		t.Error("expected error for unregistered comparator")
+	}
+}
+
+func TestWithEndpoint(t *testing.T) {
+	c := NewClient(WithBaseURL("https://test.com/api/"), WithAPIVersion("v2", VersionPath))
+	testClientURL(c, c.NewRequest("episodes", "ep_1").WithEndpoint("https://staging.test.com/api"), "https://staging.test.com/api/v2/episodes/ep_1/", t)
+
+	if _, err := c.url(c.NewRequest("episodes", "").WithEndpoint("staging")); !errors.Is(err, ErrInvalidEndpoint) {
+		t.Error("expected invalid endpoint error, got", err)
+	}
+}
+
+func TestTimeEncoding(t *testing.T) {
+	start := time.Date(2020, 3, 15, 5, 10, 0, 0, time.UTC)
+	newRequest := func(c *Client) *Request {
+		return c.NewRequest("episodes", "").
+			WithFilter("modified", NewFilterValue(GreaterThan, start)).
+			AddField(NewField("title").WithFilter(NewFilterValue(LessThan, start)))
+	}
+
+	c := NewClient(WithBaseURL("https://test.com/"))
+	testClientURL(c, newRequest(c), "https://test.com/episodes/?fields=title&modified__gt=2020-03-15T05:10:00Z&title__lt=2020-03-15T05:10:00Z", t)
+
+	c = NewClient(WithBaseURL("https://test.com/"), WithTimeEncoding(TimeEpoch))
+	testClientURL(c, newRequest(c), "https://test.com/episodes/?fields=title&modified__gt=1584249000&title__lt=1584249000", t)
+
+	c = NewClient(WithBaseURL("https://test.com/"), WithTimeEncoding(TimeDateOnly))
+	testClientURL(c, newRequest(c).WithParam("since", start), "https://test.com/episodes/?fields=title&modified__gt=2020-03-15&title__lt=2020-03-15&since=2020-03-15", t)
+
+	// every time of a list is encoded
+	c = NewClient(WithBaseURL("https://test.com/"), WithTimeEncoding(TimeEpoch))
+	in := c.NewRequest("episodes", "").WithFilter("date", NewInFilter(start, start.Add(time.Hour)))
+	testClientURL(c, in, "https://test.com/episodes/?date__in=1584249000,1584252600", t)
+	bound := c.NewRequest("episodes", "").WithFilter("date", NewPlaceholderFilter(In, "{dates}")).
+		Bind(map[string]interface{}{"dates": []time.Time{start, start.Add(time.Hour)}})
+	testClientURL(c, bound, "https://test.com/episodes/?date__in=1584249000,1584252600", t)
+}
+
+func TestSelfRef(t *testing.T) {
+	var res struct {
+		Self SelfRef `json:"self"`
+	}
+	if err := json.Unmarshal([]byte(`{"self": "/api/team/team_123/"}`), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Self.Collection != "team" || res.Self.ID != teamID {
+		t.Error("incorrect self ref", res.Self)
+	}
+	testURL(res.Self.Request("https://test.com/api/"), "https://test.com/api/team/team_123/", t)
+
+	if err := json.Unmarshal([]byte(`{"self": "team"}`), &res); err == nil {
+		t.Error("expected error for invalid self URL")
+	}
+}
+
+func testClientURL(c *Client, r *Request, expectedURL string, t *testing.T) {
+	expected, err := url.Parse(expectedURL)
+	if err != nil {
+		t.Error("Invalid expected URL:", err)
+	}
+
+	actual, err := c.url(r)
+	if err != nil {
+		t.Fatal("Error generating URL:", err)
+	}
+
+	if expected.Path != actual.Path {
+		t.Error(fmt.Sprintf("incorrect URL path\nexpected: %s\ngot:      %s", expected.Path, actual.Path))
+	}
+
+	compareValues(expected.Query(), actual.Query(), t)
+}
+
 func testURL(r *Request, expectedURL string, t *testing.T) {
 	expected, err := url.Parse(expectedURL)
 	if err != nil {
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the current time. It is used for expiry, deadlines and timestamps so tests can control it.
+type Clock interface {
+	Now() time.Time
+}
+
+// Sleeper waits between retries and rate limited pages. It returns early with the context's error if ctx is done.
+type Sleeper interface {
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// ClockFunc adapts a function to a Clock.
+type ClockFunc func() time.Time
+
+// Now implements Clock.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SleeperFunc adapts a function to a Sleeper.
+type SleeperFunc func(ctx context.Context, d time.Duration) error
+
+// Sleep implements Sleeper.
+func (f SleeperFunc) Sleep(ctx context.Context, d time.Duration) error {
+	return f(ctx, d)
+}
+
+// WithClock sets the clock used for the expiry of the WithStaleIfError cache, the storage time of cached responses,
+// retry deadlines, Retry-After dates, snapshot times, measured request and page durations and the elapsed time
+// reported to progress functions, it defaults to the system clock. The clock is also set on the client's
+// RegionalResolver, RefreshingTokenAuth, CachedSecrets and HMACSigner. Caches set with WithObjectCache
+// use their own clock, see ObjectCache.SetClock.
+func WithClock(clock Clock) Option {
+	return func(s *settings) {
+		s.clock = clock
+		if s.stale != nil {
+			s.stale.SetClock(clock)
+		}
+		s.shareClock(s.resolver)
+		s.shareClock(s.auth)
+		s.shareClock(s.signer)
+		for _, h := range s.secretHeaders {
+			s.shareClock(h.provider)
+		}
+	}
+}
+
+// clockSetter is implemented by the parts of a client that tell time.
+type clockSetter interface {
+	SetClock(Clock)
+}
+
+// shareClock sets the client's clock on v if both exist.
+func (s *settings) shareClock(v interface{}) {
+	if setter, ok := v.(clockSetter); ok && s.clock != nil {
+		setter.SetClock(s.clock)
+	}
+}
+
+// WithSleeper sets how the client waits between retries, download resumptions, rate limited pages, watch polls
+// and import requests, it defaults to a timer.
+func WithSleeper(sleeper Sleeper) Option {
+	return func(s *settings) {
+		s.sleeper = sleeper
+	}
+}
+
+func (s *settings) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
+}
+
+func (s *settings) sleep(ctx context.Context, d time.Duration) error {
+	if s.sleeper == nil {
+		return sleep(ctx, d)
+	}
+	return s.sleeper.Sleep(ctx, d)
+}
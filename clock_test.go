@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock and Sleeper whose sleeps advance its time instantly.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return ctx.Err()
+}
+
+func TestFakeSleeper(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 4 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	c := NewClient(WithBaseURL(server.URL), WithSleeper(clock),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 4, Backoff: time.Minute, Multiplier: 2}))
+	start := time.Now()
+	if err := c.NewRequest("episodes", "").Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Error("retries waited for the real backoff")
+	}
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute}
+	if len(clock.sleeps) != len(want) {
+		t.Fatal("unexpected sleeps", clock.sleeps)
+	}
+	for i, d := range want {
+		if clock.sleeps[i] != d {
+			t.Errorf("sleep %d: expected %v, got %v", i, d, clock.sleeps[i])
+		}
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cache := NewObjectCache(1, time.Hour)
+	cache.SetClock(clock)
+	cache.Add("images", "img_1", json.RawMessage(`{}`))
+	clock.Sleep(context.Background(), 59*time.Minute)
+	if _, ok := cache.Get("images", "img_1"); !ok {
+		t.Error("object expired early")
+	}
+	clock.Sleep(context.Background(), 2*time.Minute)
+	if _, ok := cache.Get("images", "img_1"); ok {
+		t.Error("expired object was returned")
+	}
+
+	// the stale cache follows the client's clock regardless of option order
+	c := NewClient(WithClock(clock), WithStaleIfError(1, time.Minute))
+	if c.current().stale.now() != clock.Now() {
+		t.Error("stale cache does not use the client's clock")
+	}
+	c = NewClient(WithStaleIfError(1, time.Minute), WithClock(clock))
+	if c.current().stale.now() != clock.Now() {
+		t.Error("stale cache does not use the client's clock")
+	}
+}
+
+func TestSharedClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	resolver := &RegionalResolver{Endpoints: map[string]string{"eu": "https://eu", "us": "https://us"}, Fallbacks: []string{"eu", "us"}}
+	var fetches int
+	auth := &RefreshingTokenAuth{Fetch: func(ctx context.Context) (string, time.Time, error) {
+		fetches++
+		return "token", clock.Now().Add(time.Hour), nil
+	}}
+	signer := &HMACSigner{Secret: []byte("key")}
+	provider := &countingSecrets{}
+	secrets := CachedSecrets(provider, time.Minute)
+	// the clock is shared with parts set before and after it
+	c := NewClient(WithEndpointResolver(resolver), WithAuthenticator(auth), WithClock(clock),
+		WithSigner(signer), WithSecretHeader("X-Secret", "", secrets, "name"))
+
+	resolver.Report("https://eu", &APIError{StatusCode: http.StatusServiceUnavailable})
+	clock.Sleep(context.Background(), time.Minute)
+	if endpoint, _ := resolver.Endpoint(context.Background()); endpoint != "https://eu" {
+		t.Error("cooldown did not follow the client's clock, got", endpoint)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://eu/api/sets/", nil)
+	auth.Authenticate(req)
+	clock.Sleep(context.Background(), 2*time.Hour)
+	auth.Authenticate(req)
+	if fetches != 2 {
+		t.Error("token expiry did not follow the client's clock, fetches:", fetches)
+	}
+
+	if err := signer.Sign(req, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ts := req.Header.Get("X-Timestamp"); ts != fmt.Sprint(clock.Now().Unix()) {
+		t.Error("signature timestamp did not follow the client's clock, got", ts)
+	}
+	if err := signer.Verify(req, nil); err != nil {
+		t.Error("verify did not follow the client's clock:", err)
+	}
+
+	secrets.Secret(context.Background(), "name")
+	clock.Sleep(context.Background(), 2*time.Minute)
+	secrets.Secret(context.Background(), "name")
+	if provider.calls != 2 {
+		t.Error("secret expiry did not follow the client's clock, calls:", provider.calls)
+	}
+
+	retryAt := clock.Now().Add(10 * time.Second).Format(http.TimeFormat)
+	if d := parseRetryAfter(retryAt, c.current().now()); d != 10*time.Second {
+		t.Error("Retry-After date not relative to the client's clock, got", d)
+	}
+}
+
+func TestClockDurations(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clock.Sleep(context.Background(), time.Minute)
+		if strings.HasSuffix(r.URL.Path, "/set_1/") {
+			fmt.Fprintf(w, `{"id": 1, "polled": %d}`, clock.Now().Unix())
+			return
+		}
+		if r.URL.Query().Get("offset") == "1" {
+			fmt.Fprint(w, `{"meta": {"total_count": 2, "next": null}, "objects": [{"id": 2}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"meta": {"total_count": 2, "next": "/api/sets/?offset=1"}, "objects": [{"id": 1}]}`)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithClock(clock), WithSleeper(clock))
+	var elapsed []time.Duration
+	var sets []json.RawMessage
+	err := c.NewRequest("sets", "").Limit(1).WithProgress(func(p Progress) {
+		elapsed = append(elapsed, p.Elapsed)
+	}).ExecuteAll(&sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, d := range elapsed {
+		if d%time.Minute != 0 || d == 0 {
+			t.Errorf("progress %d: elapsed %v does not follow the client's clock", i, d)
+		}
+	}
+
+	// watch polls wait with the client's sleeper
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events := c.NewRequest("sets", "set_1").Watch(ctx, time.Hour)
+	<-events
+	<-events
+	cancel()
+	for range events {
+	}
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	var polls int
+	for _, d := range clock.sleeps {
+		if d == time.Hour {
+			polls++
+		}
+	}
+	if polls == 0 {
+		t.Error("watch did not wait with the client's sleeper")
+	}
+}
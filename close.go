@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrClientClosed is returned for requests executed with a client after Close was called.
+var ErrClientClosed = errors.New("client is closed")
+
+// lifecycle tracks the requests executing with a client so Close can wait for them.
+type lifecycle struct {
+	closed bool
+	// shutdown is closed when Close gives up waiting and cancels in-flight requests.
+	shutdown chan struct{}
+}
+
+// begin registers an executing request and returns its context, which is canceled if Close stops waiting for it.
+// end must be called once the request finished.
+func (c *Client) begin(parent context.Context) (ctx context.Context, end func(), err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.life.closed {
+		return nil, nil, ErrClientClosed
+	}
+	c.inflight.Add(1)
+
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-c.life.shutdown:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		cancel()
+		c.inflight.Done()
+	}, nil
+}
+
+// Close stops the client from accepting new requests and waits for executing ones to finish.
+// If ctx is done first the remaining requests are canceled.
+// Afterwards the object cache is purged, idle connections are closed and the audit sink is closed if it implements io.Closer.
+// Watches and feeds using the client stop once their next request fails with ErrClientClosed.
+func (c *Client) Close(ctx context.Context) error {
+	c.mu.Lock()
+	if c.life.closed {
+		c.mu.Unlock()
+		return ErrClientClosed
+	}
+	c.life.closed = true
+	s := c.settings
+	c.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(drained)
+	}()
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+		close(c.life.shutdown)
+		<-drained
+	}
+
+	if s.objectCache != nil {
+		s.objectCache.Purge()
+	}
+	s.httpClient.CloseIdleConnections()
+	if closer, ok := s.audit.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClose(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	cache := NewObjectCache(10, 0)
+	cache.Add("episodes", "ep_1", []byte(`{}`))
+	c := NewClient(WithBaseURL(server.URL), WithObjectCache(cache))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.NewRequest("episodes", "").Execute(nil)
+	}()
+	<-started
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- c.Close(context.Background())
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if err := c.NewRequest("episodes", "").Execute(nil); !errors.Is(err, ErrClientClosed) {
+		t.Error("expected closed error, got", err)
+	}
+	select {
+	case <-closed:
+		t.Fatal("close returned before in-flight request finished")
+	default:
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+	if err := <-closed; err != nil {
+		t.Error(err)
+	}
+	if cache.Len() != 0 {
+		t.Error("cache was not purged")
+	}
+}
+
+func TestCloseCancels(t *testing.T) {
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	done := make(chan error, 1)
+	go func() {
+		done <- c.NewRequest("episodes", "").Execute(nil)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := c.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("expected deadline error, got", err)
+	}
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Error("expected canceled request, got", err)
+	}
+}
@@ -0,0 +1,85 @@
+// Command golarkgen generates Go types, field name constants and request builders for Skylark collections.
+//
+// The schema is inferred from sample objects of a Skylark instance, or read from a schema exported as JSON:
+//
+//	golarkgen -endpoint https://test.com/api/ -collections episodes,seasons -package skylark -o skylark.go
+//	golarkgen -schema schema.json -package skylark -o skylark.go
+//
+// With -dump-schema the inferred schema is written instead of code, so it can be reviewed and edited.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	client "github.com/SoMuchForSubtlety/golark"
+	"github.com/SoMuchForSubtlety/golark/codegen"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "golarkgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("golarkgen", flag.ContinueOnError)
+	endpoint := flags.String("endpoint", "", "Skylark endpoint to introspect")
+	collections := flags.String("collections", "", "comma separated collections to introspect")
+	schemaPath := flags.String("schema", "", "schema JSON file to read instead of introspecting")
+	sample := flags.Int("sample", 20, "number of objects sampled per collection")
+	pkg := flags.String("package", "skylark", "package name of the generated code")
+	out := flags.String("o", "", "output file, defaults to stdout")
+	dump := flags.Bool("dump-schema", false, "write the schema as JSON instead of generating code")
+	timeout := flags.Duration("timeout", 30*time.Second, "timeout for introspecting")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var schema *codegen.Schema
+	switch {
+	case *schemaPath != "":
+		f, err := os.Open(*schemaPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if schema, err = codegen.ReadSchema(f); err != nil {
+			return err
+		}
+	case *endpoint != "" && *collections != "":
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		var err error
+		c := client.NewClient(client.WithBaseURL(*endpoint))
+		if schema, err = codegen.Introspect(ctx, c, strings.Split(*collections, ","), *sample); err != nil {
+			return err
+		}
+	default:
+		return errors.New("either -schema or -endpoint and -collections are required")
+	}
+
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if *dump {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(schema)
+	}
+	return codegen.Generate(w, *pkg, schema)
+}
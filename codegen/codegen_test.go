@@ -0,0 +1,90 @@
+package codegen
+
+import (
+	"bytes"
+	"context"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	client "github.com/SoMuchForSubtlety/golark"
+	"github.com/SoMuchForSubtlety/golark/golarktest"
+)
+
+func TestIntrospect(t *testing.T) {
+	srv := golarktest.NewServer()
+	defer srv.Close()
+	err := srv.Seed("episodes",
+		map[string]interface{}{"uid": "ep_1", "title": "Race", "duration": 7200, "rating": 4, "published": true,
+			"aired": "2020-07-05T13:10:00Z", "season": "/seasons/s_2020/", "image_urls": []string{"/images/img_1/"}, "extra": nil},
+		map[string]interface{}{"uid": "ep_2", "title": "Qualifying", "duration": 3600, "rating": 3.5, "published": false,
+			"aired": "2020-07-04T14:00:00Z", "season": "/seasons/s_2020/", "image_urls": []string{}, "extra": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+	schema, err := Introspect(context.Background(), c, []string{"episodes"}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := make(map[string]string)
+	for _, f := range schema.Collections[0].Fields {
+		types[f.Name] = f.Type + f.Items
+	}
+	expected := map[string]string{
+		"uid": TypeString, "title": TypeString, "duration": TypeInteger, "rating": TypeFloat, "published": TypeBoolean,
+		"aired": TypeDateTime, "season": TypeReference, "image_urls": TypeList + TypeReference, "extra": TypeAny, "self": TypeReference,
+	}
+	for name, typ := range expected {
+		if types[name] != typ {
+			t.Errorf("incorrect type for %s: expected %s, got %s", name, typ, types[name])
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	schema, err := ReadSchema(strings.NewReader(`{"collections": [
+		{"name": "race-seasons", "fields": [{"name": "year", "type": "integer"}, {"name": "uid", "type": "string"}]},
+		{"name": "episodes", "fields": [{"name": "image_urls", "type": "list", "items": "reference"}, {"name": "aired", "type": "datetime"}]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := Generate(&b, "skylark", schema); err != nil {
+		t.Fatal(err)
+	}
+	src := b.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "skylark.go", src, 0); err != nil {
+		t.Fatal("generated code doesn't parse:", err)
+	}
+	for _, snippet := range []string{
+		"type RaceSeason struct",
+		"UID  string `json:\"uid\"`",
+		"RaceSeasonFieldYear = \"year\"",
+		"func NewRaceSeasons(c *client.Client) *client.Collection[RaceSeason]",
+		"ImageURLs []client.SelfRef `json:\"image_urls\"`",
+		"Aired     time.Time",
+		"func NewEpisodeRequest(c *client.Client, id string) *client.Request",
+	} {
+		if !strings.Contains(src, snippet) {
+			t.Errorf("generated code doesn't contain %q\n%s", snippet, src)
+		}
+	}
+
+	if _, err := ReadSchema(strings.NewReader(`{"collections": [{"fields": []}]}`)); err == nil {
+		t.Error("expected an error for a collection without name")
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	for collection, expected := range map[string]string{
+		"episodes": "Episode", "race-seasons": "RaceSeason", "categories": "Category", "driver": "Driver", "classes": "Class",
+	} {
+		if got := TypeName(collection); got != expected {
+			t.Errorf("incorrect type name for %s: expected %s, got %s", collection, expected, got)
+		}
+	}
+}
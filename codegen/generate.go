@@ -0,0 +1,148 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]bool{"ID": true, "UID": true, "URL": true, "URLS": true, "API": true, "HTTP": true, "JSON": true}
+
+// Generate writes a Go source file declaring, for every collection of the schema, a struct type,
+// constants for its field names, a typed collection constructor and a request builder selecting all fields.
+func Generate(w io.Writer, pkg string, s *Schema) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by golarkgen. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+
+	collections := append([]Collection(nil), s.Collections...)
+	sort.Slice(collections, func(i, j int) bool { return collections[i].Name < collections[j].Name })
+	var usesTime bool
+	for _, c := range collections {
+		for _, f := range c.Fields {
+			if f.Type == TypeDateTime || f.Items == TypeDateTime {
+				usesTime = true
+			}
+		}
+	}
+	b.WriteString("import (\n")
+	if usesTime {
+		b.WriteString("\t\"time\"\n\n")
+	}
+	b.WriteString("\tclient \"github.com/SoMuchForSubtlety/golark\"\n)\n")
+
+	seen := make(map[string]string)
+	for _, c := range collections {
+		typeName := TypeName(c.Name)
+		if other, ok := seen[typeName]; ok {
+			return fmt.Errorf("collections %s and %s both generate type %s", other, c.Name, typeName)
+		}
+		seen[typeName] = c.Name
+		writeCollection(&b, typeName, c)
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("generated invalid code: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+func writeCollection(b *bytes.Buffer, typeName string, c Collection) {
+	fields := append([]Field(nil), c.Fields...)
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+
+	fmt.Fprintf(b, "\n// %s is an object of the %s collection.\ntype %s struct {\n", typeName, c.Name, typeName)
+	for _, f := range fields {
+		fmt.Fprintf(b, "\t%s %s `json:%q`\n", FieldName(f.Name), goType(f), f.Name)
+	}
+	b.WriteString("}\n")
+
+	if len(fields) > 0 {
+		fmt.Fprintf(b, "\n// Field names of the %s collection.\nconst (\n", c.Name)
+		for _, f := range fields {
+			fmt.Fprintf(b, "\t%sField%s = %q\n", typeName, FieldName(f.Name), f.Name)
+		}
+		b.WriteString(")\n")
+	}
+
+	plural := exported(c.Name)
+	fmt.Fprintf(b, "\n// %sCollection is the name of the %s collection.\nconst %sCollection = %q\n", plural, c.Name, plural, c.Name)
+	fmt.Fprintf(b, "\n// New%s returns a typed client for the %s collection.\n", plural, c.Name)
+	fmt.Fprintf(b, "func New%s(c *client.Client) *client.Collection[%s] {\n\treturn client.NewCollection[%s](c, %sCollection)\n}\n", plural, typeName, typeName, plural)
+	fmt.Fprintf(b, "\n// New%sRequest returns a request for %s objects selecting all fields of %s.\n", typeName, c.Name, typeName)
+	fmt.Fprintf(b, "func New%sRequest(c *client.Client, id string) *client.Request {\n\tr := c.NewRequest(%sCollection, id)\n", typeName, plural)
+	for _, f := range fields {
+		fmt.Fprintf(b, "\tr.AddField(client.NewField(%sField%s))\n", typeName, FieldName(f.Name))
+	}
+	b.WriteString("\treturn r\n}\n")
+}
+
+func goType(f Field) string {
+	if f.Type == TypeList {
+		item := goType(Field{Type: f.Items})
+		return "[]" + item
+	}
+	switch f.Type {
+	case TypeString:
+		return "string"
+	case TypeInteger:
+		return "int64"
+	case TypeFloat:
+		return "float64"
+	case TypeBoolean:
+		return "bool"
+	case TypeDateTime:
+		return "time.Time"
+	case TypeReference:
+		return "client.SelfRef"
+	case TypeObject:
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// TypeName returns the name of the struct type generated for a collection, the singular of its name.
+func TypeName(collection string) string {
+	name := exported(collection)
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "sses"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+// FieldName returns the name of the struct field generated for a Skylark field.
+func FieldName(field string) string {
+	return exported(field)
+}
+
+// exported converts a snake case or kebab case name to an exported Go identifier.
+func exported(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		upper := strings.ToUpper(word)
+		if initialisms[upper] {
+			if upper == "URLS" {
+				upper = "URLs"
+			}
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "X" + s
+	}
+	return s
+}
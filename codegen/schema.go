@@ -0,0 +1,163 @@
+// Package codegen generates Go types, field names and request builders for Skylark collections.
+// It is used by the golarkgen command.
+package codegen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	client "github.com/SoMuchForSubtlety/golark"
+)
+
+// Field types of a schema.
+const (
+	TypeString    = "string"
+	TypeInteger   = "integer"
+	TypeFloat     = "float"
+	TypeBoolean   = "boolean"
+	TypeDateTime  = "datetime"
+	TypeReference = "reference"
+	TypeObject    = "object"
+	TypeList      = "list"
+	TypeAny       = "any"
+)
+
+// Schema describes the fields of collections, it can be exported as JSON and read with ReadSchema.
+type Schema struct {
+	Collections []Collection `json:"collections"`
+}
+
+// Collection describes a collection and the fields of its objects.
+type Collection struct {
+	Name   string  `json:"name"`
+	Fields []Field `json:"fields"`
+}
+
+// Field is a field of a collection's objects. Items is the type of the elements of list fields.
+type Field struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Items string `json:"items,omitempty"`
+}
+
+// ReadSchema reads a schema exported as JSON.
+func ReadSchema(r io.Reader) (*Schema, error) {
+	var s Schema
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	for _, c := range s.Collections {
+		if c.Name == "" {
+			return nil, fmt.Errorf("invalid schema: collection without name")
+		}
+	}
+	return &s, nil
+}
+
+// Introspect infers the schema of the collections from up to sample objects of each, it defaults to 20.
+// Fields only have a type if it is the same in every sampled object that has them, otherwise they are TypeAny.
+func Introspect(ctx context.Context, c *client.Client, collections []string, sample int) (*Schema, error) {
+	if sample <= 0 {
+		sample = 20
+	}
+	s := &Schema{}
+	for _, name := range collections {
+		var res client.List[map[string]interface{}]
+		if err := c.NewRequest(name, "").WithContext(ctx).Limit(sample).Execute(&res); err != nil {
+			return nil, fmt.Errorf("introspecting %s: %w", name, err)
+		}
+		s.Collections = append(s.Collections, inferCollection(name, res.Objects))
+	}
+	return s, nil
+}
+
+// inferCollection merges the field types of sampled objects.
+func inferCollection(name string, objects []map[string]interface{}) Collection {
+	types := make(map[string]Field)
+	for _, object := range objects {
+		for key, value := range object {
+			f := inferField(key, value)
+			if prev, ok := types[key]; ok {
+				f = mergeField(prev, f)
+			}
+			types[key] = f
+		}
+	}
+	c := Collection{Name: name}
+	for _, f := range types {
+		if f.Type == "" {
+			f.Type = TypeAny
+		}
+		c.Fields = append(c.Fields, f)
+	}
+	sort.Slice(c.Fields, func(i, j int) bool { return c.Fields[i].Name < c.Fields[j].Name })
+	return c
+}
+
+// inferField returns the field for a decoded value, null values have no type.
+func inferField(name string, value interface{}) Field {
+	f := Field{Name: name, Type: inferType(value)}
+	if list, ok := value.([]interface{}); ok {
+		for _, item := range list {
+			f.Items = mergeType(f.Items, inferType(item))
+		}
+	}
+	return f
+}
+
+func inferType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		return TypeBoolean
+	case float64:
+		if v == math.Trunc(v) {
+			return TypeInteger
+		}
+		return TypeFloat
+	case string:
+		if _, err := client.ParseSelf(v); err == nil && strings.HasPrefix(v, "/") && strings.HasSuffix(v, "/") {
+			return TypeReference
+		}
+		if _, err := time.Parse(time.RFC3339, v); err == nil {
+			return TypeDateTime
+		}
+		return TypeString
+	case []interface{}:
+		return TypeList
+	case map[string]interface{}:
+		return TypeObject
+	}
+	return TypeAny
+}
+
+func mergeField(a, b Field) Field {
+	a.Type = mergeType(a.Type, b.Type)
+	a.Items = mergeType(a.Items, b.Items)
+	return a
+}
+
+// mergeType returns the type that can hold values of both types, an empty type is unknown.
+func mergeType(a, b string) string {
+	switch {
+	case a == "" || a == b:
+		return b
+	case b == "":
+		return a
+	case a == TypeInteger && b == TypeFloat || a == TypeFloat && b == TypeInteger:
+		return TypeFloat
+	case a == TypeString && (b == TypeDateTime || b == TypeReference) || b == TypeString && (a == TypeDateTime || a == TypeReference):
+		// some values only happened to look like times or references
+		return TypeString
+	}
+	return TypeAny
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// ListOptions selects the objects and fields returned by collection helpers.
+type ListOptions struct {
+	Fields  []*Field
+	Expand  []*Field
+	Filters map[string]*Filter
+	OrderBy *Field
+	// Limit is the number of objects per page, it defaults to the client's page size.
+	Limit  int
+	Offset int
+	// Progress is called by Iter after every page.
+	Progress ProgressFunc
+}
+
+// apply adds the options to the request.
+func (o ListOptions) apply(r *Request) *Request {
+	for _, f := range o.Fields {
+		r.AddField(f)
+	}
+	for _, f := range o.Expand {
+		r.Expand(f)
+	}
+	for field, filter := range o.Filters {
+		r.WithFilter(field, filter)
+	}
+	if o.OrderBy != nil {
+		r.OrderBy(o.OrderBy)
+	}
+	return r
+}
+
+// Collection is a typed client for a single collection.
+type Collection[T any] struct {
+	client *Client
+	name   string
+}
+
+// NewCollection creates a typed client for the collection.
+func NewCollection[T any](c *Client, name string) *Collection[T] {
+	return &Collection[T]{client: c, name: name}
+}
+
+// Get fetches the object with the given ID.
+func (c *Collection[T]) Get(ctx context.Context, id string, fields ...*Field) (T, error) {
+	var v T
+	r := c.client.NewRequest(c.name, id).WithContext(ctx)
+	for _, f := range fields {
+		r.AddField(f)
+	}
+	err := r.Execute(&v)
+	return v, err
+}
+
+// List fetches a single page of objects.
+func (c *Collection[T]) List(ctx context.Context, opts ListOptions) ([]T, error) {
+	objects, _, err := c.Page(ctx, opts)
+	return objects, err
+}
+
+// Page fetches a single page of objects and its pagination metadata.
+func (c *Collection[T]) Page(ctx context.Context, opts ListOptions) ([]T, PageInfo, error) {
+	r := opts.apply(c.client.NewRequest(c.name, "").WithContext(ctx))
+	if opts.Limit > 0 {
+		r.additionalFields["limit"] = strconv.Itoa(opts.Limit)
+	}
+	if opts.Offset > 0 {
+		r.additionalFields["offset"] = strconv.Itoa(opts.Offset)
+	}
+	res, err := ExecuteList[T](r)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+	return res.Objects, res.PageInfo, nil
+}
+
+// Iter returns an iterator over all matching objects, fetching pages as needed.
+func (c *Collection[T]) Iter(ctx context.Context, opts ListOptions) *Iterator[T] {
+	pageSize := opts.Limit
+	if pageSize <= 0 {
+		pageSize = c.client.current().pageSize
+	}
+	if pageSize <= 0 {
+		pageSize = defaultBulkPageSize
+	}
+	return &Iterator[T]{
+		request:  opts.apply(c.client.NewRequest(c.name, "").WithContext(ctx)),
+		pageSize: pageSize,
+		offset:   opts.Offset,
+		progress: newProgressTracker(opts.Progress, c.client.current().now),
+	}
+}
+
+// Iterator iterates over the objects of a collection.
+// Pages that are rejected with 429 Too Many Requests are fetched again after a delay,
+// which grows while the server keeps rejecting pages and is reported as Progress.Pace.
+//
+//	it := episodes.Iter(ctx, opts)
+//	for it.Next() {
+//		episode := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	request  *Request
+	pageSize int
+	offset   int
+
+	page []T
+	pos  int
+	last bool
+	err  error
+
+	// objects and previous track the progress for stopping before the context's deadline
+	objects  int
+	previous time.Duration
+	progress *progressTracker
+	info     PageInfo
+}
+
+// Next advances to the next object, fetching the next page if necessary.
+// It returns false when all objects were returned or an error occurred.
+func (it *Iterator[T]) Next() bool {
+	for it.pos+1 >= len(it.page) {
+		if it.last || it.err != nil {
+			return false
+		}
+		it.fetch()
+	}
+	it.pos++
+	return true
+}
+
+// Value returns the current object.
+func (it *Iterator[T]) Value() T {
+	return it.page[it.pos]
+}
+
+// PageInfo returns the pagination metadata of the most recently fetched page.
+func (it *Iterator[T]) PageInfo() PageInfo {
+	return it.info
+}
+
+// Err returns the error that stopped the iteration.
+// If the context's deadline would pass before the next page arrives, it is a *PartialResultError
+// whose offset can be used to continue.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+func (it *Iterator[T]) fetch() {
+	if it.objects > 0 {
+		if it.err = checkPageDeadline(it.request.ctx, it.progress.now(), it.previous, it.objects, it.offset); it.err != nil {
+			return
+		}
+	}
+	r := it.request.copy()
+	r.dedupe = nil
+	r.additionalFields["limit"] = strconv.Itoa(it.pageSize)
+	r.additionalFields["offset"] = strconv.Itoa(it.offset)
+
+	if err := it.progress.pacer.wait(it.request.ctx, it.request.executor().current().sleep); err != nil {
+		it.err = pageFailed(it.request.ctx, err, it.objects, it.offset)
+		return
+	}
+	start := it.progress.now()
+	objects, info, err := r.executeList()
+	if err != nil {
+		if !it.progress.pacer.throttled(err) {
+			it.err = pageFailed(it.request.ctx, err, it.objects, it.offset)
+		}
+		return
+	}
+	it.progress.pacer.succeeded()
+	it.previous = it.progress.now().Sub(start)
+	it.info = info
+	it.progress.counted(info, it.offset-it.objects)
+	unseen := it.request.dedupe.filter(objects)
+	it.page, it.pos = make([]T, len(unseen)), -1
+	for i, object := range unseen {
+		if it.err = json.Unmarshal(object, &it.page[i]); it.err != nil {
+			// the partly decoded page must not be returned by Next
+			it.page, it.pos = nil, -1
+			return
+		}
+	}
+	it.last = !info.more(it.offset, len(objects), it.pageSize)
+	it.offset += len(objects)
+	it.objects += len(objects)
+	it.progress.page(len(objects))
+	if it.last {
+		it.progress.done()
+	}
+}
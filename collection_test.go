@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type episode struct {
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+}
+
+func TestCollection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/episodes/ep_") {
+			w.Write([]byte(`{"uid": "ep_1", "title": "Race"}`))
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var objects []string
+		for i := offset; i < offset+limit && i < 5; i++ {
+			objects = append(objects, fmt.Sprintf(`{"uid": "ep_%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"objects": [%s], "meta": {"total_count": 5, "limit": %d, "offset": %d, "next": "/episodes/?offset=%d"}}`,
+			strings.Join(objects, ","), limit, offset, offset+limit)
+	}))
+	defer server.Close()
+
+	episodes := NewCollection[episode](NewClient(WithBaseURL(server.URL)), "episodes")
+	ctx := context.Background()
+
+	ep, err := episodes.Get(ctx, "ep_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.Title != "Race" {
+		t.Error("incorrect episode", ep)
+	}
+
+	page, err := episodes.List(ctx, ListOptions{Limit: 2, Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || page[0].UID != "ep_1" {
+		t.Error("incorrect page", page)
+	}
+
+	_, info, err := episodes.Page(ctx, ListOptions{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Count != 5 || info.Limit != 2 || info.Offset != 2 || !info.HasNext() || info.HasPrevious() {
+		t.Error("incorrect page info", info)
+	}
+
+	it := episodes.Iter(ctx, ListOptions{Limit: 2})
+	var uids []string
+	for it.Next() {
+		uids = append(uids, it.Value().UID)
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if strings.Join(uids, ",") != "ep_0,ep_1,ep_2,ep_3,ep_4" {
+		t.Error("incorrect iteration", uids)
+	}
+}
+
+func TestExecuteList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"objects": [{"title": "Race"}], "meta": {"total_count": 3, "limit": 1, "offset": 1, "next": "/episodes/?offset=2", "previous": "/episodes/?offset=0"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	l, err := ExecuteList[episode](c.NewRequest("episodes", "").Limit(1).Offset(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Objects) != 1 || l.Objects[0].Title != "Race" {
+		t.Error("incorrect objects", l.Objects)
+	}
+	if l.Count != 3 || l.Limit != 1 || l.Offset != 1 || !l.HasNext() || !l.HasPrevious() {
+		t.Error("incorrect metadata", l.PageInfo)
+	}
+}
+
+func TestIteratorPacing(t *testing.T) {
+	var limited bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if offset == 2 && !limited {
+			limited = true
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		var objects []string
+		for i := offset; i < offset+2 && i < 5; i++ {
+			objects = append(objects, fmt.Sprintf(`{"uid": "ep_%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"objects": [%s]}`, strings.Join(objects, ","))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	var paces []time.Duration
+	it := NewCollection[episode](c, "episodes").Iter(context.Background(), ListOptions{Limit: 2, Progress: func(p Progress) {
+		paces = append(paces, p.Pace)
+	}})
+	var n int
+	for it.Next() {
+		n++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 5 {
+		t.Error("incorrect number of objects", n)
+	}
+	if len(paces) < 2 || paces[0] != 0 || paces[1] == 0 {
+		t.Error("pace was not reported", paces)
+	}
+}
+
+func TestIteratorMalformedPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"objects": [{"uid": "ep_0"}, {"uid": 1}]}`))
+	}))
+	defer server.Close()
+
+	episodes := NewCollection[episode](NewClient(WithBaseURL(server.URL)), "episodes")
+	it := episodes.Iter(context.Background(), ListOptions{Limit: 2})
+	if it.Next() {
+		t.Error("object of a malformed page was returned", it.Value())
+	}
+	if it.Err() == nil {
+		t.Error("expected a decoding error")
+	}
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+var (
+	comparatorsMu sync.RWMutex
+	comparators   = map[constraint]func(value string) error{
+		Equals:             nil,
+		GreaterThan:        nil,
+		GreaterThanOrEqual: nil,
+		LessThan:           nil,
+		LessThanOrEqual:    nil,
+		Contains:           nil,
+		StartsWith:         nil,
+		In:                 nil,
+	}
+	comparatorName = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+)
+
+// RegisterComparator declares a custom lookup operator supported by a deployment, like "overlap" for field__overlap filters,
+// and returns the constraint to create filters with. validate checks filter values and may be nil.
+// Filters with unregistered constraints or invalid values make requests fail.
+// It panics if the name is invalid or already registered, it is meant to be called during initialization.
+func RegisterComparator(name string, validate func(value string) error) constraint {
+	c, err := DeclareComparator(name, validate)
+	if err != nil {
+		panic(err.Error())
+	}
+	return c
+}
+
+// DeclareComparator registers a comparator like RegisterComparator, it returns an error instead of panicking.
+func DeclareComparator(name string, validate func(value string) error) (constraint, error) {
+	c := constraint(name)
+	if !comparatorName.MatchString(name) {
+		return "", fmt.Errorf("invalid comparator name %q", name)
+	}
+	comparatorsMu.Lock()
+	defer comparatorsMu.Unlock()
+	if _, ok := comparators[c]; ok {
+		return "", fmt.Errorf("comparator %q is already registered", name)
+	}
+	comparators[c] = validate
+	return c, nil
+}
+
+// checkComparator returns an error if the constraint is unknown or the value is invalid for it.
+func checkComparator(c constraint, value string) error {
+	comparatorsMu.RLock()
+	validate, ok := comparators[c]
+	comparatorsMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown comparator %q, declare it with RegisterComparator", string(c))
+	}
+	if validate != nil {
+		if err := validate(value); err != nil {
+			return fmt.Errorf("invalid value %q for comparator %q: %w", value, string(c), err)
+		}
+	}
+	return nil
+}
+
+// isComparator reports whether the constraint is registered.
+func isComparator(c constraint) bool {
+	comparatorsMu.RLock()
+	defer comparatorsMu.RUnlock()
+	_, ok := comparators[c]
+	return ok
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestCompression(t *testing.T) {
+	var encodings, bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		body := r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			body = zr
+		}
+		data, _ := ioutil.ReadAll(body)
+		bodies = append(bodies, string(data))
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithRequestCompression(100))
+	long := strings.Repeat("a", 200)
+	for _, title := range []string{"Race", long} {
+		if err := c.send(c.NewRequest("sets", ""), http.MethodPost, map[string]string{"title": title}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(encodings) != 2 || encodings[0] != "" || encodings[1] != "gzip" {
+		t.Error("incorrect encodings", encodings)
+	}
+	if len(bodies) != 2 || bodies[1] != `{"title":"`+long+`"}` {
+		t.Error("incorrect bodies", bodies)
+	}
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds client settings that can be loaded from a file.
+type Config struct {
+	Endpoint string        `yaml:"endpoint"`
+	Auth     AuthConfig    `yaml:"auth"`
+	Timeout  time.Duration `yaml:"timeout"`
+	Retry    RetryPolicy   `yaml:"retry"`
+	PageSize int           `yaml:"page_size"`
+}
+
+// AuthConfig holds the credentials sent with every request.
+type AuthConfig struct {
+	Token  string `yaml:"token"`
+	APIKey string `yaml:"api_key"`
+}
+
+// FromConfigFile creates a client from a YAML, JSON or TOML config file, TOML files are detected
+// by their .toml extension. Durations are written like "10s" or "500ms". Invalid settings, like a malformed
+// endpoint, are returned as errors.
+//
+//	endpoint: https://test.com/api/
+//	timeout: 10s
+//	page_size: 100
+//	auth:
+//	  token: secret
+//	retry:
+//	  max_attempts: 3
+//	  backoff: 500ms
+func FromConfigFile(path string) (*Client, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rd io.Reader = f
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		// TOML documents are decoded through YAML so both are checked against the same fields
+		var doc map[string]interface{}
+		if _, err := toml.NewDecoder(f).Decode(&doc); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		data, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		rd = bytes.NewReader(data)
+	}
+
+	var cfg Config
+	dec := yaml.NewDecoder(rd)
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	c := NewClient(cfg.Options()...)
+	if err := c.current().err; err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Options converts the config into client options.
+func (cfg Config) Options() []Option {
+	var opts []Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, WithBaseURL(cfg.Endpoint))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, WithTimeout(cfg.Timeout))
+	}
+	var auth ChainAuth
+	if cfg.Auth.Token != "" {
+		auth = append(auth, TokenAuth(cfg.Auth.Token))
+	}
+	if cfg.Auth.APIKey != "" {
+		auth = append(auth, APIKeyAuth(cfg.Auth.APIKey))
+	}
+	if len(auth) > 0 {
+		opts = append(opts, WithAuthenticator(auth))
+	}
+	if cfg.PageSize > 0 {
+		opts = append(opts, WithPageSize(cfg.PageSize))
+	}
+	return append(opts, WithRetryPolicy(cfg.Retry))
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFromConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golark")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "golark.yaml")
+	config := `
+endpoint: https://test.com/api/
+timeout: 10s
+page_size: 50
+auth:
+  token: secret
+retry:
+  max_attempts: 3
+  backoff: 500ms
+`
+	if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := FromConfigFile(path)
+	if err != nil {
+		t.Fatal("Error loading config:", err)
+	}
+	if c.settings.endpoint != "https://test.com/api/" {
+		t.Error("incorrect endpoint", c.settings.endpoint)
+	}
+	if c.settings.httpClient.Timeout != 10*time.Second {
+		t.Error("incorrect timeout", c.settings.httpClient.Timeout)
+	}
+	if c.settings.pageSize != 50 {
+		t.Error("incorrect page size", c.settings.pageSize)
+	}
+	var dryRun *DryRunError
+	if err := c.NewRequest("episodes", "").DryRun().Execute(nil); !errors.As(err, &dryRun) {
+		t.Fatal("expected dry run error, got", err)
+	}
+	if dryRun.Request.Header.Get("Authorization") != "Bearer secret" {
+		t.Error("incorrect auth header", dryRun.Request.Header.Get("Authorization"))
+	}
+	if c.settings.retry.MaxAttempts != 3 || c.settings.retry.Backoff != 500*time.Millisecond {
+		t.Error("incorrect retry policy", c.settings.retry)
+	}
+}
+
+func TestFromConfigFileUnknownKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golark")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "golark.yaml")
+	if err := ioutil.WriteFile(path, []byte("endpont: https://test.com/api/\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := FromConfigFile(path); err == nil {
+		t.Error("expected error for unknown config key")
+	}
+}
+
+func TestFromConfigFileInvalidEndpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golark")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "golark.yaml")
+	if err := ioutil.WriteFile(path, []byte("endpoint: \"://test.com/api/\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := FromConfigFile(path); err == nil {
+		t.Error("expected error for invalid endpoint")
+	}
+}
+
+func TestUpdateConfig(t *testing.T) {
+	var token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("Authorization")
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithHeader("Authorization", "Bearer old"))
+	var res map[string]interface{}
+	if err := c.NewRequest("set", "").Execute(&res); err != nil {
+		t.Fatal(err)
+	}
+	if token != "Bearer old" {
+		t.Error("incorrect token", token)
+	}
+
+	if err := c.UpdateConfig(WithHeader("Authorization", "Bearer new")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.NewRequest("set", "").Execute(&res); err != nil {
+		t.Fatal(err)
+	}
+	if token != "Bearer new" {
+		t.Error("incorrect token after update", token)
+	}
+
+	if err := c.UpdateConfig(WithBaseURL("not a url")); err == nil {
+		t.Error("expected error for invalid endpoint")
+	}
+	if err := c.NewRequest("set", "").Execute(&res); err != nil {
+		t.Error("invalid update should be discarded, got", err)
+	}
+}
+
+func TestFromTOMLConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golark")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "golark.toml")
+	config := `
+# staging
+endpoint = "https://test.com/api/"
+timeout = "10s"
+page_size = 50
+auth = { token = 'secret' }
+
+[retry]
+max_attempts = 3 # including the first one
+backoff = "500ms"
+multiplier = 1.5
+`
+	if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := FromConfigFile(path)
+	if err != nil {
+		t.Fatal("Error loading config:", err)
+	}
+	if c.settings.endpoint != "https://test.com/api/" || c.settings.httpClient.Timeout != 10*time.Second || c.settings.pageSize != 50 {
+		t.Error("incorrect settings", c.settings.endpoint, c.settings.httpClient.Timeout, c.settings.pageSize)
+	}
+	if c.settings.retry.MaxAttempts != 3 || c.settings.retry.Backoff != 500*time.Millisecond || c.settings.retry.Multiplier != 1.5 {
+		t.Error("incorrect retry policy", c.settings.retry)
+	}
+	var dryRun *DryRunError
+	if err := c.NewRequest("episodes", "").DryRun().Execute(nil); !errors.As(err, &dryRun) {
+		t.Fatal("expected dry run error, got", err)
+	}
+	if dryRun.Request.Header.Get("Authorization") != "Bearer secret" {
+		t.Error("incorrect auth header", dryRun.Request.Header.Get("Authorization"))
+	}
+
+	for _, invalid := range []string{"unknown = 1", "endpoint = \"a\"\nendpoint = \"b\"", "[retry\nmax_attempts = 1", "timeout = 10s", "\"page_size = 1\" = 2"} {
+		if err := ioutil.WriteFile(path, []byte(invalid), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := FromConfigFile(path); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
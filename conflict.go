@@ -0,0 +1,35 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrConflictingQuery is returned for requests whose parts contradict each other,
+// the server would interpret them unpredictably.
+var ErrConflictingQuery = errors.New("conflicting query")
+
+// fieldConflict reports replacing a field that is returned with one that is only expanded, or the other way around.
+func fieldConflict(existing, f *Field) error {
+	if existing == nil || existing == f || existing.IsIncluded == f.IsIncluded {
+		return nil
+	}
+	return fmt.Errorf("%w: field %q is both returned and only expanded", ErrConflictingQuery, f.Name)
+}
+
+// orderConflict reports ordering by a relation the request expands, related objects have no order.
+// Ordering by a field the request doesn't return is fine, the server sorts by any field of the collection.
+func (r *Request) orderConflict() error {
+	order := r.additionalFields["order"]
+	if order == "" {
+		return nil
+	}
+	for _, name := range strings.Split(order, ",") {
+		name = strings.TrimPrefix(name, "-")
+		if f, ok := r.Fields[name]; ok && f.IsExpanded {
+			return fmt.Errorf("%w: ordering by %q, which is an expanded relation", ErrConflictingQuery, name)
+		}
+	}
+	return nil
+}
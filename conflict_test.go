@@ -0,0 +1,35 @@
+package client
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConflicts(t *testing.T) {
+	const endpoint = "https://test.com/api/"
+	tests := []struct {
+		name     string
+		request  *Request
+		conflict bool
+	}{
+		{"expand after add", NewRequest(endpoint, "sets", "").AddField(NewField("image_urls")).Expand(NewField("image_urls")), true},
+		{"add after expand", NewRequest(endpoint, "sets", "").Expand(NewField("image_urls")).AddField(NewField("image_urls")), true},
+		{"add and expand", NewRequest(endpoint, "sets", "").AddField(NewField("image_urls").WithSubField(NewField("url"))), false},
+		{"order by returned field", NewRequest(endpoint, "sets", "").AddField(NewField("title")).OrderBy(NewField("title")), false},
+		{"order by filtered field", NewRequest(endpoint, "sets", "").AddField(NewField("title")).OrderBy(NewField("created")).WithFilter("created", NewFilter(GreaterThan, "2020")), false},
+		{"order by sub field", NewRequest(endpoint, "sets", "").AddField(NewField("image_urls").WithSubField(NewField("url"))).OrderBy(NewField("image_urls__url")), false},
+		{"order by other field", NewRequest(endpoint, "sets", "").AddField(NewField("title")).OrderBy(NewField("created")), false},
+		{"order by other field descending", NewRequest(endpoint, "sets", "").AddField(NewField("name")).OrderBy(NewField("-year")), false},
+		{"order by expanded relation", NewRequest(endpoint, "sets", "").Expand(NewField("image_urls")).OrderBy(NewField("image_urls")), true},
+		{"order by field of expanded relation", NewRequest(endpoint, "sets", "").Expand(NewField("image_urls")).OrderBy(NewField("image_urls__url")), false},
+		{"order without fields", NewRequest(endpoint, "sets", "").OrderBy(NewField("created")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.request.ToURL()
+			if errors.Is(err, ErrConflictingQuery) != tt.conflict {
+				t.Errorf("expected conflict %t, got %v", tt.conflict, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// readOnlyFields are set by the server and never written when copying objects.
+var readOnlyFields = []string{"uid", "self", "created", "modified"}
+
+// CopyOptions configures CopyObjects.
+type CopyOptions struct {
+	// Fields limits the fields that are read from the source, all fields are read if it is empty.
+	Fields []*Field
+	// Expand lists reference fields whose objects are copied to the target as well.
+	// References to them are replaced with the self URLs of the copies.
+	Expand []*Field
+	// Omit lists additional fields that are not written to the target.
+	Omit []string
+	// RemapURL translates reference URLs that were not expanded.
+	// By default only URLs of objects copied in the same call are remapped.
+	RemapURL func(self string) string
+}
+
+// CopyObjects reads the objects with the given IDs from one client and creates them with another,
+// for example to copy content between environments.
+// It returns a map from the self URLs of the source objects to the self URLs of their copies,
+// which contains all objects copied before an error occurred.
+func CopyObjects(ctx context.Context, from, to *Client, collection string, ids []string, opts CopyOptions) (map[string]string, error) {
+	cp := &copier{ctx: ctx, from: from, to: to, opts: opts, copied: make(map[string]string)}
+	for _, id := range ids {
+		r := from.NewRequest(collection, id).WithContext(ctx)
+		for _, f := range opts.Fields {
+			r.AddField(f)
+		}
+		for _, f := range opts.Expand {
+			r.Expand(f)
+		}
+
+		var object map[string]interface{}
+		if err := r.Execute(&object); err != nil {
+			return cp.copied, fmt.Errorf("unable to read %s %s: %w", collection, id, err)
+		}
+		if _, err := cp.copy(collection, object); err != nil {
+			return cp.copied, err
+		}
+	}
+	return cp.copied, nil
+}
+
+type copier struct {
+	ctx      context.Context
+	from, to *Client
+	opts     CopyOptions
+	copied   map[string]string
+}
+
+// copy creates the object with the target client and returns the self URL of the copy.
+func (cp *copier) copy(collection string, object map[string]interface{}) (string, error) {
+	self, _ := object["self"].(string)
+	if target, ok := cp.copied[self]; ok {
+		return target, nil
+	}
+
+	body := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		body[key] = value
+	}
+	for _, key := range append(readOnlyFields, cp.opts.Omit...) {
+		delete(body, key)
+	}
+	for key, value := range body {
+		remapped, err := cp.remap(value)
+		if err != nil {
+			return "", err
+		}
+		body[key] = remapped
+	}
+
+	var created struct {
+		Self string `json:"self"`
+	}
+	err := cp.to.send(cp.to.NewRequest(collection, "").WithContext(cp.ctx), http.MethodPost, body, &created)
+	if err != nil {
+		return "", fmt.Errorf("unable to create copy of %s: %w", self, err)
+	}
+	if self != "" {
+		cp.copied[self] = created.Self
+	}
+	return created.Self, nil
+}
+
+// remap copies expanded objects and replaces references with the self URLs of their copies.
+func (cp *copier) remap(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		self, ok := v["self"].(string)
+		if !ok {
+			return v, nil
+		}
+		collection, _, ok := parseSelf(self)
+		if !ok {
+			return nil, fmt.Errorf("unable to determine collection of %s", self)
+		}
+		return cp.copy(collection, v)
+	case []interface{}:
+		remapped := make([]interface{}, len(v))
+		for i, item := range v {
+			r, err := cp.remap(item)
+			if err != nil {
+				return nil, err
+			}
+			remapped[i] = r
+		}
+		return remapped, nil
+	case string:
+		if target, ok := cp.copied[v]; ok {
+			return target, nil
+		}
+		if cp.opts.RemapURL != nil && strings.HasPrefix(v, "/") {
+			return cp.opts.RemapURL(v), nil
+		}
+		return v, nil
+	}
+	return value, nil
+}
+
+// parseSelf splits a self URL like /api/sets/set_123/ into its collection and ID.
+func parseSelf(self string) (string, string, bool) {
+	parts := strings.Split(strings.Trim(self, "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-1] == "" || parts[len(parts)-2] == "" {
+		return "", "", false
+	}
+	return parts[len(parts)-2], parts[len(parts)-1], true
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCopyObjects(t *testing.T) {
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"uid": "set_1", "self": "/api/sets/set_1/", "title": "Season",
+			"image_urls": [{"uid": "img_1", "self": "/api/images/img_1/", "url": "a.png"}]
+		}`))
+	}))
+	defer source.Close()
+
+	var created []map[string]interface{}
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		created = append(created, body)
+		if r.URL.Path == "/images/" {
+			w.Write([]byte(`{"self": "/api/images/img_2/"}`))
+		} else {
+			w.Write([]byte(`{"self": "/api/sets/set_2/"}`))
+		}
+	}))
+	defer target.Close()
+
+	copied, err := CopyObjects(context.Background(),
+		NewClient(WithBaseURL(source.URL)), NewClient(WithBaseURL(target.URL)),
+		"sets", []string{"set_1"}, CopyOptions{Expand: []*Field{NewField("image_urls")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if copied["/api/sets/set_1/"] != "/api/sets/set_2/" || copied["/api/images/img_1/"] != "/api/images/img_2/" {
+		t.Error("incorrect mapping", copied)
+	}
+	if len(created) != 2 {
+		t.Fatal("incorrect number of created objects", len(created))
+	}
+	if _, ok := created[0]["uid"]; ok {
+		t.Error("read only field was copied")
+	}
+	images, _ := created[1]["image_urls"].([]interface{})
+	if len(images) != 1 || images[0] != "/api/images/img_2/" {
+		t.Error("reference was not remapped", created[1]["image_urls"])
+	}
+}
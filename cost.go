@@ -0,0 +1,156 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// ErrQuotaExceeded is returned for requests whose estimated cost would exceed the quota set with WithCostModel.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// CostModel estimates the cost of sending a request with the given method.
+type CostModel interface {
+	Cost(r *Request, method string) float64
+}
+
+// CostModelFunc adapts a function to a CostModel.
+type CostModelFunc func(r *Request, method string) float64
+
+// Cost implements CostModel.
+func (f CostModelFunc) Cost(r *Request, method string) float64 {
+	return f(r, method)
+}
+
+// LinearCost is a CostModel that adds up fixed costs for the parts of a request.
+type LinearCost struct {
+	// PerRequest is charged for every request.
+	PerRequest float64
+	// PerPage is charged for every page of a collection, each page is a separate request.
+	PerPage float64
+	// PerExpansion is charged for every expanded reference field.
+	PerExpansion float64
+	// PerWrite is charged for requests that modify objects, like Create or Patch.
+	PerWrite float64
+}
+
+// Cost implements CostModel.
+func (l LinearCost) Cost(r *Request, method string) float64 {
+	cost := l.PerRequest
+	if method == http.MethodGet && r.ID == "" {
+		cost += l.PerPage
+	}
+	if method != http.MethodGet && method != http.MethodHead {
+		cost += l.PerWrite
+	}
+	return cost + l.PerExpansion*float64(countExpansions(r.Fields))
+}
+
+func countExpansions(fields map[string]*Field) int {
+	n := 0
+	for _, f := range fields {
+		if f.IsExpanded {
+			n++
+		}
+		n += countExpansions(f.SubFields)
+	}
+	return n
+}
+
+// Quota limits the total estimated cost of requests, aggregated by the value of a request tag, see Request.WithTag.
+type Quota struct {
+	// Tag is the tag usage is aggregated by, like "tenant". Requests without it are aggregated under the empty value.
+	// If Tag is empty all requests are aggregated together.
+	Tag string
+	// Limits are the maximum total costs per tag value.
+	Limits map[string]float64
+	// Default is the limit for tag values without one in Limits, zero means unlimited.
+	Default float64
+}
+
+// WithCostModel estimates the cost of every request with model before it is sent and adds it to the usage
+// of its tag value, see Client.Usage. The cost is added to the metadata of logs and audit records as "cost"
+// and to Result.Cost. Requests that would exceed their quota fail with an error wrapping ErrQuotaExceeded.
+// Dry runs are not charged.
+func WithCostModel(model CostModel, quota Quota) Option {
+	return func(s *settings) {
+		limits := make(map[string]float64, len(quota.Limits))
+		for value, limit := range quota.Limits {
+			limits[value] = limit
+		}
+		quota.Limits = limits
+		s.costs = &costMeter{model: model, quota: quota, usage: make(map[string]float64)}
+	}
+}
+
+// Usage returns the total estimated cost of the requests sent so far per value of the quota's tag.
+func (c *Client) Usage() map[string]float64 {
+	m := c.current().costs
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage := make(map[string]float64, len(m.usage))
+	for value, cost := range m.usage {
+		usage[value] = cost
+	}
+	return usage
+}
+
+// ResetUsage resets the usage of all tag values, for example at the start of a billing period.
+func (c *Client) ResetUsage() {
+	if m := c.current().costs; m != nil {
+		m.mu.Lock()
+		m.usage = make(map[string]float64)
+		m.mu.Unlock()
+	}
+}
+
+// costMeter aggregates the costs of a client's requests. It is shared by all its configurations.
+type costMeter struct {
+	model CostModel
+	quota Quota
+
+	mu    sync.Mutex
+	usage map[string]float64
+}
+
+// estimate returns the cost of the request, zero if the client has no cost model.
+func (s *settings) estimate(r *Request, method string) float64 {
+	if s.costs == nil {
+		return 0
+	}
+	temp := *r
+	temp.Fields = s.withDefaultFields(r)
+	return s.costs.model.Cost(&temp, method)
+}
+
+// charge adds the estimated cost of the request to its usage, it fails if that exceeds the quota.
+func (s *settings) charge(r *Request, method string) (float64, error) {
+	if s.costs == nil || s.dryRun || r.dryRun {
+		return 0, nil
+	}
+	cost := s.estimate(r, method)
+	m := s.costs
+	value := r.tags[m.quota.Tag]
+	limit, ok := m.quota.Limits[value]
+	if !ok {
+		limit = m.quota.Default
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if limit > 0 && m.usage[value]+cost > limit {
+		return cost, fmt.Errorf("%w: %s %q has used %g of %g, request costs %g", ErrQuotaExceeded, m.quota.Tag, value, m.usage[value], limit, cost)
+	}
+	m.usage[value] += cost
+	return cost, nil
+}
+
+// formatCost formats a cost for metadata.
+func formatCost(cost float64) string {
+	return strconv.FormatFloat(cost, 'g', -1, 64)
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCostModel(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"objects":[]}`))
+	}))
+	defer server.Close()
+
+	model := LinearCost{PerRequest: 1, PerPage: 2, PerExpansion: 0.5}
+	c := NewClient(WithBaseURL(server.URL), WithCostModel(model, Quota{Tag: "tenant", Limits: map[string]float64{"acme": 5}}))
+	expanded := c.NewRequest("episodes", "").
+		AddField(NewField("title")).
+		Expand(NewField("team")).
+		WithTag("tenant", "acme")
+	res, err := expanded.ExecuteResult(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Cost != 3.5 {
+		t.Error("unexpected cost", res.Cost)
+	}
+	e, err := expanded.Explain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Cost != 3.5 {
+		t.Error("unexpected explained cost", e.Cost)
+	}
+
+	if err := expanded.Execute(nil); !errors.Is(err, ErrQuotaExceeded) {
+		t.Error("expected ErrQuotaExceeded, got", err)
+	}
+	if requests != 1 {
+		t.Error("request over quota was sent")
+	}
+	// tag values without a limit are unlimited
+	for i := 0; i < 3; i++ {
+		if err := c.NewRequest("episodes", "ep_1").WithTag("tenant", "other").Execute(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	usage := c.Usage()
+	if usage["acme"] != 3.5 || usage["other"] != 3 {
+		t.Error("unexpected usage", usage)
+	}
+
+	c.ResetUsage()
+	if err := expanded.Execute(nil); err != nil {
+		t.Error("quota was not reset", err)
+	}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// WithDebug dumps every request's final URL and headers and the response's status, headers and body to w.
+// Masked parameters and headers are masked and redacted fields are redacted, see WithMaskedParams,
+// WithMaskedHeaders and WithRedactedFields. Writes to w are not synchronized.
+func WithDebug(w io.Writer) Option {
+	return func(s *settings) {
+		s.debug = w
+	}
+}
+
+// Debug dumps the request and its response to w like WithDebug, but only for this request.
+func (r *Request) Debug(w io.Writer) *Request {
+	r.debug = w
+	return r
+}
+
+// ExecuteRaw executes the request and returns the response body as sent by the server, without post processing
+// or field decoders.
+func (r *Request) ExecuteRaw() ([]byte, error) {
+	var raw json.RawMessage
+	if err := r.withoutPostProcessing().withoutFieldDecoding().Execute(&raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+type debugKey struct{}
+
+// withDebug makes requests sent with ctx dump themselves to w.
+func withDebug(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, debugKey{}, w)
+}
+
+// debugWriter returns where the request is dumped to, if anywhere.
+func (s *settings) debugWriter(req *http.Request) io.Writer {
+	if w, ok := req.Context().Value(debugKey{}).(io.Writer); ok {
+		return w
+	}
+	return s.debug
+}
+
+// dump writes the request and response to w and returns a reader for the consumed body.
+func (s *settings) dump(w io.Writer, req *http.Request, res *http.Response, body io.Reader) (io.Reader, error) {
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s\n", req.Method, s.maskURL(req.URL))
+	s.maskHeader(req.Header).Write(&buf)
+	fmt.Fprintf(&buf, "\n%s %s\n", res.Proto, res.Status)
+	s.maskHeader(res.Header).Write(&buf)
+	buf.WriteByte('\n')
+	buf.Write(redact(data, s.redacted))
+	buf.WriteString("\n\n")
+	w.Write(buf.Bytes())
+	return bytes.NewReader(data), nil
+}
+
+// maskHeader returns a copy of the header with the values of masked and secret headers masked.
+func (s *settings) maskHeader(header http.Header) http.Header {
+	masked := header.Clone()
+	names := append(append([]string(nil), defaultMaskedHeaders...), s.maskedHeaders...)
+	for _, h := range s.secretHeaders {
+		names = append(names, h.key)
+	}
+	for _, name := range names {
+		key := http.CanonicalHeaderKey(name)
+		for i := range masked[key] {
+			masked[key][i] = MaskedValue
+		}
+	}
+	return masked
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"uid":"ep_1","token":"secret-token"}`))
+	}))
+	defer server.Close()
+
+	var client, request bytes.Buffer
+	c := NewClient(WithBaseURL(server.URL), WithDebug(&client), WithHeader("Authorization", "Bearer abcdefgh"),
+		WithRedactedFields("token"), WithMaskedParams("key"))
+	var v struct{ UID string }
+	r := c.NewRequest("episodes", "ep_1").Debug(&request)
+	r.additionalFields["key"] = "hunter22"
+	if err := r.Execute(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v.UID != "ep_1" {
+		t.Error("response was not decoded after dumping it", v)
+	}
+	if client.Len() != 0 {
+		t.Error("client debug writer was used for a request with its own")
+	}
+	dump := request.String()
+	for _, want := range []string{"GET " + server.URL + "/episodes/ep_1/?key=" + strings.ReplaceAll(MaskedValue, "*", "%2A"), "Authorization: ***", "200 OK", "Content-Type: application/json", `"token":"[REDACTED]"`} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("dump does not contain %q:\n%s", want, dump)
+		}
+	}
+	for _, leaked := range []string{"abcdefgh", "hunter22", "secret-token"} {
+		if strings.Contains(dump, leaked) {
+			t.Errorf("dump leaks %q", leaked)
+		}
+	}
+
+	if err := c.NewRequest("episodes", "ep_1").Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(client.String(), "GET "+server.URL+"/episodes/ep_1/") {
+		t.Error("client debug writer was not used", client.String())
+	}
+}
+
+func TestExecuteRaw(t *testing.T) {
+	body := `{ "objects": [ {"uid": "ep_1"} ] }`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	raw, err := c.NewRequest("episodes", "").PostProcess(SortObjects("uid")).ExecuteRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != body {
+		t.Errorf("expected %s, got %s", body, raw)
+	}
+}
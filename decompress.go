@@ -0,0 +1,64 @@
+package client
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds the client's maximum response size.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// defaultMaxResponseSize caps decoded response bodies unless WithMaxResponseSize is used.
+const defaultMaxResponseSize = 256 << 20
+
+// WithMaxResponseSize caps the size of response bodies after decompression, regardless of their Content-Length,
+// so a broken or malicious server can't exhaust memory with a compressed body. The default is 256 MiB,
+// zero or less disables the cap. Downloads are not capped, also when the response cache is enabled.
+func WithMaxResponseSize(n int64) Option {
+	return func(s *settings) {
+		s.maxResponseSize = n
+	}
+}
+
+// responseBody returns the decompressed body of the response, limited to the client's maximum response size.
+// Bodies the transport didn't decompress are decompressed if they are gzip encoded.
+func (s *settings) responseBody(res *http.Response) (io.Reader, error) {
+	var body io.Reader = res.Body
+	if !res.Uncompressed && strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		zr, err := gzip.NewReader(res.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip response: %w", err)
+		}
+		body = zr
+	}
+	if s.maxResponseSize <= 0 {
+		return body, nil
+	}
+	return &cappedReader{r: body, left: s.maxResponseSize}, nil
+}
+
+// cappedReader fails with ErrResponseTooLarge once more than left bytes were read.
+type cappedReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	if c.left < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// read one byte more than allowed to detect bodies that exceed the cap
+	if int64(len(p)) > c.left+1 {
+		p = p[:c.left+1]
+	}
+	n, err := c.r.Read(p)
+	c.left -= int64(n)
+	if c.left < 0 {
+		return n, ErrResponseTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxResponseSize(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(`{"title":"` + strings.Repeat("a", 1<<20) + `"}`))
+	zw.Close()
+	bomb := buf.Bytes()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(bomb)
+	}))
+	defer server.Close()
+
+	for _, header := range []string{"", "gzip"} {
+		c := NewClient(WithBaseURL(server.URL), WithMaxResponseSize(1<<10))
+		if header != "" {
+			// the transport doesn't decompress responses to requests with their own Accept-Encoding
+			c.UpdateConfig(WithHeader("Accept-Encoding", header))
+		}
+		var v struct {
+			Title string `json:"title"`
+		}
+		if err := c.Do(c.NewRequest("sets", "set_1"), &v); !errors.Is(err, ErrResponseTooLarge) {
+			t.Errorf("Accept-Encoding %q: expected ErrResponseTooLarge, got %v", header, err)
+		}
+
+		c.UpdateConfig(WithMaxResponseSize(2 << 20))
+		if err := c.Do(c.NewRequest("sets", "set_1"), &v); err != nil || len(v.Title) != 1<<20 {
+			t.Errorf("Accept-Encoding %q: unexpected error %v", header, err)
+		}
+	}
+}
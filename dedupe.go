@@ -0,0 +1,98 @@
+package client
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Deduplicator remembers the uids of objects it has seen, so helpers sharing one
+// skip objects that were already returned by an earlier, overlapping query.
+// It is safe for concurrent use.
+type Deduplicator struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// NewDeduplicator creates an empty deduplicator.
+func NewDeduplicator() *Deduplicator {
+	return &Deduplicator{seen: make(map[string]bool)}
+}
+
+// Seen records the uid and reports whether it was seen before.
+// Empty uids are never considered duplicates.
+func (d *Deduplicator) Seen(uid string) bool {
+	if uid == "" {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.seen[uid] {
+		return true
+	}
+	d.seen[uid] = true
+	return false
+}
+
+// SeenObject is like Seen for the uid field of a raw JSON object.
+// Objects without a uid are never considered duplicates.
+func (d *Deduplicator) SeenObject(object json.RawMessage) bool {
+	var meta struct {
+		UID string `json:"uid"`
+	}
+	if json.Unmarshal(object, &meta) != nil {
+		return false
+	}
+	return d.Seen(meta.UID)
+}
+
+// Len returns the number of distinct uids seen.
+func (d *Deduplicator) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.seen)
+}
+
+// Deduplicate skips the objects of the collection request's responses that d has already seen,
+// share d between requests for overlapping queries. It is honoured by Execute and Batch, which remove
+// duplicates from the response's objects list, and by ExecuteStream, ExecuteAll, Iterate, ExecuteList,
+// ExecuteJournaled and change feeds polling the request. Pages are still fetched by their full size.
+// Change feeds skip later changes of an object too, since they have the same uid.
+func (r *Request) Deduplicate(d *Deduplicator) *Request {
+	r.dedupe = d
+	return r
+}
+
+// withoutDedupe returns a copy of the request that returns all objects.
+func (r *Request) withoutDedupe() *Request {
+	c := *r
+	c.dedupe = nil
+	return &c
+}
+
+// filter returns the objects that were not seen before, d may be nil.
+func (d *Deduplicator) filter(objects []json.RawMessage) []json.RawMessage {
+	if d == nil {
+		return objects
+	}
+	unseen := objects[:0:0]
+	for _, object := range objects {
+		if !d.SeenObject(object) {
+			unseen = append(unseen, object)
+		}
+	}
+	return unseen
+}
+
+// dedupeObjects removes the objects d has seen from the objects of the response and decodes it into v.
+func (s *settings) dedupeObjects(d *Deduplicator, data json.RawMessage, v interface{}) error {
+	objects, info, err := s.decodeEnvelope(data)
+	if err != nil {
+		return err
+	}
+	if objects != nil {
+		if data, err = s.replaceObjects(data, d.filter(objects), info); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDeduplicate(t *testing.T) {
+	// every collection has the objects ep_1 to ep_3, so queries of different collections overlap
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if limit == 0 {
+			limit = 3
+		}
+		res := struct {
+			Objects []map[string]string `json:"objects"`
+		}{Objects: []map[string]string{}}
+		for i := offset; i < 3 && i < offset+limit; i++ {
+			res.Objects = append(res.Objects, map[string]string{"uid": "ep_" + strconv.Itoa(i+1), "modified": "2020-01-01T00:00:00Z"})
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+	c := NewClient(WithBaseURL(server.URL))
+
+	type list struct {
+		Objects []episode `json:"objects"`
+	}
+	d := NewDeduplicator()
+	var first, second list
+	errs := c.Batch(context.Background(), []BatchCall{
+		{Request: c.NewRequest("episodes", "").Deduplicate(d), Dest: &first},
+		{Request: c.NewRequest("sets", "").Deduplicate(d), Dest: &second},
+	}, 1)
+	if errs[0] != nil || errs[1] != nil {
+		t.Fatal(errs)
+	}
+	if len(first.Objects)+len(second.Objects) != 3 {
+		t.Error("duplicates were returned by Batch", first, second)
+	}
+
+	d = NewDeduplicator()
+	d.Seen("ep_1")
+	streamed := 0
+	if err := c.NewRequest("episodes", "").Deduplicate(d).ExecuteStream(func(json.RawMessage) error {
+		streamed++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if streamed != 2 {
+		t.Error("expected 2 streamed objects, got", streamed)
+	}
+
+	// pages keep their size, so all pages are fetched even if they only hold duplicates
+	d = NewDeduplicator()
+	d.Seen("ep_1")
+	d.Seen("ep_2")
+	var all []episode
+	if err := c.NewRequest("episodes", "").Limit(2).Deduplicate(d).ExecuteAll(&all); err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].UID != "ep_3" {
+		t.Error("incorrect objects of ExecuteAll", all)
+	}
+
+	d = NewDeduplicator()
+	d.Seen("ep_2")
+	it := c.NewRequest("episodes", "").Limit(1).Deduplicate(d).Iterate()
+	iterated := 0
+	for it.Next() {
+		iterated++
+	}
+	if it.Err() != nil || iterated != 2 {
+		t.Error("incorrect iteration", iterated, it.Err())
+	}
+
+	d = NewDeduplicator()
+	d.Seen("ep_1")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	feed := &ChangeFeed{Request: c.NewRequest("episodes", "").Deduplicate(d), Interval: time.Minute}
+	events := feed.Run(ctx)
+	for _, expected := range []string{"ep_2", "ep_3"} {
+		event := <-events
+		var object episode
+		json.Unmarshal(event.Object, &object)
+		if event.Err != nil || object.UID != expected {
+			t.Error("unexpected event", string(event.Object), event.Err)
+		}
+	}
+	cancel()
+	for range events {
+	}
+}
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+var defaultClient atomic.Value
+
+func init() {
+	defaultClient.Store(newClient(&settings{httpClient: http.DefaultClient, header: make(http.Header), maxURLLength: defaultMaxURLLength, maxResponseSize: defaultMaxResponseSize}))
+}
+
+// DefaultClient returns the client used to execute requests that were not created by
+// or bound to a client. Unless replaced with SetDefaultClient it uses http.DefaultClient.
+func DefaultClient() *Client {
+	return defaultClient.Load().(*Client)
+}
+
+// SetDefaultClient replaces the default client.
+// It is meant to be called once at startup, requests that are already executing keep using the previous client.
+// Panics on nil client, see ReplaceDefaultClient.
+func SetDefaultClient(c *Client) {
+	if c == nil {
+		panic("nil client")
+	}
+	defaultClient.Store(c)
+}
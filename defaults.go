@@ -0,0 +1,86 @@
+package client
+
+// WithDefaultFields registers fields that are merged into every request for the collection.
+// They are only merged into requests that already select fields,
+// since adding them to a request for all fields would restrict its response.
+// Fields the request already contains take precedence.
+func WithDefaultFields(collection string, fields ...*Field) Option {
+	return func(s *settings) {
+		if s.defaultFields == nil {
+			s.defaultFields = make(map[string][]*Field)
+		}
+		s.defaultFields[collection] = append(s.defaultFields[collection], fields...)
+	}
+}
+
+// WithDefaultExpansions registers fields that are expanded in every request for the collection,
+// without explicitly listing them as fields to return.
+func WithDefaultExpansions(collection string, fields ...*Field) Option {
+	expansions := make([]*Field, len(fields))
+	for i, f := range fields {
+		expansions[i] = f.clone()
+		expansions[i].IsExpanded = true
+		expansions[i].IsIncluded = false
+	}
+	return WithDefaultFields(collection, expansions...)
+}
+
+// identityFields are the fields added by WithIdentityFields.
+var identityFields = []string{"uid", "self"}
+
+// WithIdentityFields always adds uid and self to requests that select fields,
+// since references can't be hydrated without them. Requests for all fields are unaffected.
+func WithIdentityFields() Option {
+	return func(s *settings) {
+		s.identityFields = true
+	}
+}
+
+// withDefaultFields returns the request's fields merged with the client's defaults for its collection.
+func (s *settings) withDefaultFields(r *Request) map[string]*Field {
+	defaults := s.defaultFields[r.Collection]
+	if s.identityFields && selectsFields(r.Fields) {
+		fields := make(map[string]*Field, len(r.Fields)+len(identityFields))
+		for name, f := range r.Fields {
+			fields[name] = f
+		}
+		for _, name := range identityFields {
+			if f, ok := fields[name]; !ok {
+				fields[name] = NewField(name)
+			} else if !f.IsIncluded {
+				included := *f
+				included.IsIncluded = true
+				fields[name] = &included
+			}
+		}
+		temp := *r
+		temp.Fields = fields
+		r = &temp
+	}
+	if len(defaults) == 0 {
+		return r.Fields
+	}
+
+	fields := make(map[string]*Field, len(r.Fields)+len(defaults))
+	for name, f := range r.Fields {
+		fields[name] = f
+	}
+	for _, f := range defaults {
+		if _, ok := fields[f.Name]; ok || (f.IsIncluded && !selectsFields(r.Fields)) {
+			continue
+		}
+		fields[f.Name] = f
+	}
+	return fields
+}
+
+// selectsFields reports whether any of the fields or their sub fields are included,
+// which restricts the response to the included fields.
+func selectsFields(fields map[string]*Field) bool {
+	for _, f := range fields {
+		if f.IsIncluded || selectsFields(f.SubFields) {
+			return true
+		}
+	}
+	return false
+}
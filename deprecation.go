@@ -0,0 +1,81 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DeprecationWarning reports a request using a deprecated field.
+type DeprecationWarning struct {
+	Collection string
+	Field      string
+	// Replacement is the field to use instead, it is empty if there is none.
+	Replacement string
+}
+
+func (w *DeprecationWarning) String() string {
+	if w.Replacement == "" {
+		return fmt.Sprintf("field %s of %s is deprecated", w.Field, w.Collection)
+	}
+	return fmt.Sprintf("field %s of %s is deprecated, use %s instead", w.Field, w.Collection, w.Replacement)
+}
+
+// WithDeprecatedField registers a deprecated field of the collection and the field replacing it, which can be empty.
+// Requests that return, expand, filter or order by the field log a warning and are reported to
+// the function set with WithDeprecationWarning. They are sent unchanged.
+func WithDeprecatedField(collection, field, replacement string) Option {
+	return func(s *settings) {
+		if s.deprecated == nil {
+			s.deprecated = make(map[string]map[string]string)
+		}
+		if s.deprecated[collection] == nil {
+			s.deprecated[collection] = make(map[string]string)
+		}
+		s.deprecated[collection][field] = replacement
+	}
+}
+
+// WithDeprecationWarning sets a function that is called for every deprecated field a request uses,
+// for example to collect them in tests or metrics.
+func WithDeprecationWarning(fn func(r *Request, w *DeprecationWarning)) Option {
+	return func(s *settings) {
+		s.deprecationWarn = fn
+	}
+}
+
+// deprecations returns the deprecated fields the request uses, ordered by field.
+func (s *settings) deprecations(r *Request) []*DeprecationWarning {
+	deprecated := s.deprecated[r.Collection]
+	if len(deprecated) == 0 {
+		return nil
+	}
+	used := make(map[string]bool)
+	collectFieldNames(r.Fields, used)
+	for _, f := range r.filters {
+		used[f.field] = true
+	}
+	if order := r.additionalFields["order"]; order != "" {
+		for _, name := range strings.Split(order, ",") {
+			used[strings.TrimPrefix(name, "-")] = true
+		}
+	}
+	var warnings []*DeprecationWarning
+	for field, replacement := range deprecated {
+		if used[field] {
+			warnings = append(warnings, &DeprecationWarning{Collection: r.Collection, Field: field, Replacement: replacement})
+		}
+	}
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i].Field < warnings[j].Field })
+	return warnings
+}
+
+// warnDeprecated logs and reports the deprecated fields the request uses.
+func (s *settings) warnDeprecated(r *Request) {
+	for _, w := range s.deprecations(r) {
+		s.log(r.ctx, "request uses deprecated field", "collection", w.Collection, "field", w.Field, "replacement", w.Replacement)
+		if s.deprecationWarn != nil {
+			s.deprecationWarn(r, w)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDeprecatedFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var warnings []string
+	var logged string
+	c := NewClient(WithBaseURL(server.URL),
+		WithDeprecatedField("sets", "set_type", "set_type_slug"),
+		WithDeprecatedField("sets", "image_urls__image_type", ""),
+		WithDeprecationWarning(func(r *Request, w *DeprecationWarning) { warnings = append(warnings, w.String()) }),
+		WithLogger(LoggerFunc(func(msg string, keyvals ...interface{}) { logged = msg })))
+
+	r := c.NewRequest("sets", "").
+		AddField(NewField("image_urls").WithSubField(NewField("image_type"))).
+		AddField(NewField("set_type")).
+		OrderByDesc(NewField("set_type"))
+	if err := r.Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 2 || warnings[0] != "field image_urls__image_type of sets is deprecated" ||
+		warnings[1] != "field set_type of sets is deprecated, use set_type_slug instead" {
+		t.Error("incorrect warnings", warnings)
+	}
+	if logged != "request uses deprecated field" {
+		t.Error("deprecation was not logged", logged)
+	}
+
+	warnings = nil
+	if err := c.NewRequest("sets", "").WithFilter("set_type_slug", NewFilter(Equals, "video")).Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.NewRequest("episodes", "").AddField(NewField("set_type")).Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 0 {
+		t.Error("unexpected warnings", warnings)
+	}
+
+	e, err := c.NewRequest("sets", "").WithFilter("set_type", NewFilter(Equals, "video")).Explain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Warnings) != 1 || !strings.Contains(e.Warnings[0], "set_type_slug") {
+		t.Error("explanation does not report the deprecation", e.Warnings)
+	}
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// FieldChange describes a field that differs between two versions of an object.
+// Old is nil if the field was added, New is nil if it was removed.
+type FieldChange struct {
+	// Path is the field name, fields of nested objects are separated by "__".
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// Diff compares two versions of an object and returns the changed fields sorted by path.
+// Lists are compared as a whole.
+func Diff(old, new json.RawMessage) ([]FieldChange, error) {
+	var oldObject, newObject map[string]interface{}
+	if err := json.Unmarshal(old, &oldObject); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(new, &newObject); err != nil {
+		return nil, err
+	}
+
+	changes := diffObjects("", oldObject, newObject)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+func diffObjects(prefix string, old, new map[string]interface{}) []FieldChange {
+	var changes []FieldChange
+	for key, oldValue := range old {
+		path := key
+		if prefix != "" {
+			path = prefix + "__" + key
+		}
+		newValue, ok := new[key]
+		if !ok {
+			changes = append(changes, FieldChange{Path: path, Old: oldValue})
+			continue
+		}
+		oldChild, oldIsObject := oldValue.(map[string]interface{})
+		newChild, newIsObject := newValue.(map[string]interface{})
+		if oldIsObject && newIsObject {
+			changes = append(changes, diffObjects(path, oldChild, newChild)...)
+		} else if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, FieldChange{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+	for key, newValue := range new {
+		if _, ok := old[key]; !ok {
+			path := key
+			if prefix != "" {
+				path = prefix + "__" + key
+			}
+			changes = append(changes, FieldChange{Path: path, New: newValue})
+		}
+	}
+	return changes
+}
+
+// DiffWith fetches the object and compares it to a previously fetched copy.
+// It returns the changed fields and the fresh object.
+func (r *Request) DiffWith(cached json.RawMessage) ([]FieldChange, json.RawMessage, error) {
+	var fresh json.RawMessage
+	if err := r.Execute(&fresh); err != nil {
+		return nil, nil, err
+	}
+	changes, err := Diff(cached, fresh)
+	return changes, fresh, err
+}
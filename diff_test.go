@@ -0,0 +1,26 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := []byte(`{"title": "Race", "year": 2019, "image": {"url": "a.png", "type": "png"}, "tags": ["f1"]}`)
+	new := []byte(`{"title": "Race", "year": 2020, "image": {"url": "b.png", "type": "png"}, "tags": ["f1", "f2"], "slug": "race"}`)
+
+	changes, err := Diff(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []FieldChange{
+		{Path: "image__url", Old: "a.png", New: "b.png"},
+		{Path: "slug", New: "race"},
+		{Path: "tags", Old: []interface{}{"f1"}, New: []interface{}{"f1", "f2"}},
+		{Path: "year", Old: 2019.0, New: 2020.0},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("incorrect changes\nexpected: %v\ngot:      %v", expected, changes)
+	}
+}
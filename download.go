@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// DownloadOptions configures Download.
+type DownloadOptions struct {
+	// Offset is the number of bytes that were already downloaded, for example the size of a partially written file.
+	// The download continues after them.
+	Offset int64
+	// Progress is called after every write with the number of bytes downloaded so far, including Offset,
+	// and the total size or -1 if it is unknown.
+	Progress func(written, total int64)
+}
+
+// writeError marks errors of the destination writer, they are never retried.
+type writeError struct {
+	err error
+}
+
+func (e *writeError) Error() string { return e.err.Error() }
+func (e *writeError) Unwrap() error { return e.err }
+
+// Download writes the asset at assetURL, for example the url of an expanded image, to w.
+// Interrupted downloads are retried according to the client's retry policy and resumed with Range requests,
+// servers that ignore the Range header send the whole asset again and the already written part is skipped.
+// The client's API headers are not sent, assets are usually served from a different host.
+// It returns the number of bytes written to w.
+func (c *Client) Download(ctx context.Context, assetURL string, w io.Writer, opts DownloadOptions) (int64, error) {
+	ctx, end, err := c.begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer end()
+
+	written := opts.Offset
+	for attempt := 1; ; attempt++ {
+		s := c.current()
+		n, err := s.download(ctx, assetURL, written, w, opts.Progress)
+		written += n
+		if err == nil {
+			return written - opts.Offset, nil
+		}
+		var werr *writeError
+		if errors.As(err, &werr) || !s.retry.shouldRetry(ctx, attempt, err) {
+			s.log(ctx, "download failed", "url", assetURL, "attempt", attempt, "written", written, "error", err)
+			return written - opts.Offset, err
+		}
+		s.log(ctx, "resuming download", "url", assetURL, "attempt", attempt, "written", written, "error", err)
+		if err := s.sleep(ctx, s.retry.delay(attempt, err)); err != nil {
+			return written - opts.Offset, err
+		}
+	}
+}
+
+// download makes a single request for the asset starting at offset and returns the number of bytes written.
+func (s *settings) download(ctx context.Context, assetURL string, offset int64, w io.Writer, progress func(written, total int64)) (int64, error) {
+	// the cache would buffer the whole asset in memory
+	req, err := http.NewRequestWithContext(withSkipCache(ctx), http.MethodGet, assetURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	res, err := s.roundTrip(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	total := int64(-1)
+	switch {
+	case res.StatusCode == http.StatusPartialContent:
+		var start, end int64
+		if _, err := fmt.Sscanf(res.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total); err != nil {
+			total = -1
+			if _, err := fmt.Sscanf(res.Header.Get("Content-Range"), "bytes %d-%d/*", &start, &end); err != nil {
+				return 0, fmt.Errorf("invalid Content-Range %q: %w", res.Header.Get("Content-Range"), err)
+			}
+		}
+		if start != offset {
+			return 0, fmt.Errorf("server resumed download at byte %d instead of %d", start, offset)
+		}
+	case res.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// the asset was already downloaded completely
+		return 0, nil
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		if res.ContentLength >= 0 {
+			total = res.ContentLength
+		}
+		if _, err := io.CopyN(ioutil.Discard, res.Body, offset); err != nil {
+			return 0, err
+		}
+	default:
+		message, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return 0, fmt.Errorf("Unable to read error message from server: %w", err)
+		}
+		return 0, newAPIError(req, res, message, s)
+	}
+
+	var n int64
+	buf := make([]byte, 32*1024)
+	for {
+		read, err := res.Body.Read(buf)
+		if read > 0 {
+			if _, err := w.Write(buf[:read]); err != nil {
+				return n, &writeError{err: err}
+			}
+			n += int64(read)
+			if progress != nil {
+				progress(offset+n, total)
+			}
+		}
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
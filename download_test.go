@@ -0,0 +1,69 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadResume(t *testing.T) {
+	asset := strings.Repeat("0123456789", 1000)
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if len(ranges) == 1 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(asset)))
+			w.Write([]byte(asset[:4000]))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "asset.jpg", time.Time{}, strings.NewReader(asset))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithRetryPolicy(RetryPolicy{MaxAttempts: 2}))
+	var out bytes.Buffer
+	var written, total int64
+	n, err := c.Download(context.Background(), server.URL+"/asset.jpg", &out, DownloadOptions{
+		Progress: func(w, t int64) { written, total = w, t },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(asset)) || out.String() != asset {
+		t.Error("incorrect download", n)
+	}
+	if len(ranges) != 2 || ranges[0] != "" || ranges[1] != "bytes=4000-" {
+		t.Error("incorrect ranges", ranges)
+	}
+	if written != int64(len(asset)) || total != int64(len(asset)) {
+		t.Error("incorrect progress", written, total)
+	}
+}
+
+func TestDownloadBypassesCache(t *testing.T) {
+	asset := strings.Repeat("0123456789", 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"asset"`)
+		w.Write([]byte(asset))
+	}))
+	defer server.Close()
+
+	cache := NewLRUCache(0)
+	c := NewClient(WithCache(cache), WithMaxResponseSize(100))
+	var out bytes.Buffer
+	if _, err := c.Download(context.Background(), server.URL+"/asset.jpg", &out, DownloadOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != asset {
+		t.Error("incorrect download", out.Len())
+	}
+	if cache.Len() != 0 {
+		t.Error("download was cached")
+	}
+}
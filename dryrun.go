@@ -0,0 +1,55 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrDryRun is wrapped by the error returned for requests executed in dry run mode.
+var ErrDryRun = errors.New("dry run, request not sent")
+
+// DryRunError holds the fully built HTTP request that a dry run did not send.
+// The request is not masked, its error message is, see WithMaskedParams.
+type DryRunError struct {
+	Request *http.Request
+	// Body is the encoded request body, it is nil for requests without one.
+	Body []byte
+	// maskedURL is the URL of the request as it is logged.
+	maskedURL string
+}
+
+func (e *DryRunError) Error() string {
+	u := e.maskedURL
+	if u == "" {
+		u = e.Request.URL.Redacted()
+	}
+	return ErrDryRun.Error() + ": " + e.Request.Method + " " + u
+}
+
+func (e *DryRunError) Unwrap() error {
+	return ErrDryRun
+}
+
+// DryRun makes executing the request build and validate the HTTP request without sending it.
+// Execute returns a *DryRunError holding the request instead.
+func (r *Request) DryRun() *Request {
+	r.dryRun = true
+	return r
+}
+
+// WithDryRun makes the client build and validate every request without sending it, see Request.DryRun.
+func WithDryRun(enabled bool) Option {
+	return func(s *settings) {
+		s.dryRun = enabled
+	}
+}
+
+// dryRunCall builds the HTTP request for the call, logs it and returns it as a *DryRunError.
+func (s *settings) dryRunCall(c *call) error {
+	req, err := s.newHTTPRequest(c)
+	if err != nil {
+		return err
+	}
+	s.log(c.ctx, "dry run", "method", c.method, "url", s.maskURL(c.url))
+	return &DryRunError{Request: req, Body: c.payload, maskedURL: s.maskURL(c.url)}
+}
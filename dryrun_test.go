@@ -0,0 +1,53 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	var sent int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+	}))
+	defer server.Close()
+	c := NewClient(WithBaseURL(server.URL), WithTokenFromSecrets(EnvSecrets{}, "GOLARK_DRY_RUN_TOKEN"))
+	t.Setenv("GOLARK_DRY_RUN_TOKEN", "secret")
+
+	err := c.NewRequest("episodes", "ep_1").DryRun().Execute(nil)
+	var dryRun *DryRunError
+	if !errors.Is(err, ErrDryRun) || !errors.As(err, &dryRun) {
+		t.Fatal("expected dry run error, got", err)
+	}
+	if dryRun.Request.URL.Path != "/episodes/ep_1/" || dryRun.Request.Header.Get("Authorization") != "Bearer secret" {
+		t.Error("incorrect request", dryRun.Request.URL, dryRun.Request.Header)
+	}
+
+	if err := c.UpdateConfig(WithDryRun(true)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.NewRequest("episodes", "").Execute(nil); !errors.Is(err, ErrDryRun) {
+		t.Error("expected dry run error, got", err)
+	}
+	if sent != 0 {
+		t.Error("dry run sent", sent, "requests")
+	}
+}
+
+func TestDryRunMasked(t *testing.T) {
+	c := NewClient(WithBaseURL("https://x.example/api/"), WithDryRun(true), WithMaskedParams("token"))
+	err := c.NewRequest("episodes", "").WithFilter("token", NewFilter(Equals, "supersecretvalue")).Execute(nil)
+	var dryRun *DryRunError
+	if !errors.As(err, &dryRun) {
+		t.Fatal("expected a dry run error, got", err)
+	}
+	if strings.Contains(err.Error(), "supersecretvalue") {
+		t.Error("masked parameter leaked into the error", err)
+	}
+	if dryRun.Request.URL.Query().Get("token") != "supersecretvalue" {
+		t.Error("request should not be masked")
+	}
+}
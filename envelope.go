@@ -0,0 +1,192 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EnvelopeStrategy decodes the envelope around the objects of collection responses,
+// for forks and lookalike APIs that don't use Skylark's objects and meta keys.
+type EnvelopeStrategy interface {
+	// Decode returns the objects of the response body and its pagination metadata.
+	Decode(body []byte) (objects []json.RawMessage, info PageInfo, err error)
+}
+
+// KeyEnvelope is an EnvelopeStrategy that reads the objects, total count and next page from the given keys.
+// Keys of nested values are separated by dots, like "meta.total_count". Empty and missing keys are not decoded.
+type KeyEnvelope struct {
+	Items string
+	Count string
+	Next  string
+}
+
+// EnvelopeEncoder is implemented by envelope strategies that can replace the objects of a response.
+// Collection requests are only split into parts, see WithMaxURLLength, and can only be deduplicated
+// or post-processed if the client's strategy implements it. KeyEnvelope implements it.
+type EnvelopeEncoder interface {
+	// Replace returns the response body with the objects, total count and next page of info replacing its own.
+	Replace(body []byte, objects []json.RawMessage, info PageInfo) ([]byte, error)
+}
+
+// ErrEnvelopeNotEncodable is returned when the objects of a response can't be replaced
+// because the client's EnvelopeStrategy doesn't implement EnvelopeEncoder.
+var ErrEnvelopeNotEncodable = errors.New("envelope strategy can't replace objects")
+
+// SkylarkEnvelope is the envelope of Skylark responses.
+var SkylarkEnvelope = KeyEnvelope{Items: "objects", Count: "meta.total_count", Next: "meta.next"}
+
+// WithEnvelope sets how Iterate, ExecuteList and the paging helpers like Export decode collection responses.
+// By default the Skylark envelope is decoded with all of its metadata.
+func WithEnvelope(strategy EnvelopeStrategy) Option {
+	return func(s *settings) {
+		s.envelope = strategy
+	}
+}
+
+// Decode implements EnvelopeStrategy.
+func (e KeyEnvelope) Decode(body []byte) ([]json.RawMessage, PageInfo, error) {
+	var (
+		objects []json.RawMessage
+		info    PageInfo
+	)
+	targets := []struct {
+		key string
+		v   interface{}
+	}{{e.Items, &objects}, {e.Count, &info.Count}, {e.Next, &info.Next}}
+	for _, target := range targets {
+		if target.key == "" {
+			continue
+		}
+		value, err := lookupKey(body, target.key)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		if value == nil {
+			continue
+		}
+		if err := json.Unmarshal(value, target.v); err != nil {
+			return nil, PageInfo{}, fmt.Errorf("envelope key %s: %w", target.key, err)
+		}
+	}
+	info.linked = e.Next != ""
+	return objects, info, nil
+}
+
+// Replace implements EnvelopeEncoder, keys of the body other than the envelope's are kept.
+// An empty next page is encoded as null.
+func (e KeyEnvelope) Replace(body []byte, objects []json.RawMessage, info PageInfo) ([]byte, error) {
+	if objects == nil {
+		objects = []json.RawMessage{}
+	}
+	var next interface{}
+	if info.Next != "" {
+		next = info.Next
+	}
+	targets := []struct {
+		key string
+		v   interface{}
+	}{{e.Items, objects}, {e.Count, info.Count}, {e.Next, next}}
+	for _, target := range targets {
+		if target.key == "" {
+			continue
+		}
+		value, err := json.Marshal(target.v)
+		if err != nil {
+			return nil, err
+		}
+		if body, err = setKey(body, target.key, value); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+// setKey returns the body with the value at the dot separated key, objects on the way are created if missing.
+func setKey(body []byte, key string, value json.RawMessage) ([]byte, error) {
+	object := make(map[string]json.RawMessage)
+	if len(body) > 0 && string(body) != "null" {
+		if err := json.Unmarshal(body, &object); err != nil {
+			return nil, fmt.Errorf("envelope key %s: %w", key, err)
+		}
+	}
+	part, rest, nested := strings.Cut(key, ".")
+	if nested {
+		var err error
+		if value, err = setKey(object[part], rest, value); err != nil {
+			return nil, err
+		}
+	}
+	object[part] = value
+	return json.Marshal(object)
+}
+
+// lookupKey returns the value at the dot separated key, nil if it is missing or null.
+func lookupKey(body []byte, key string) (json.RawMessage, error) {
+	value := json.RawMessage(body)
+	for _, part := range strings.Split(key, ".") {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(value, &object); err != nil {
+			return nil, fmt.Errorf("envelope key %s: %w", key, err)
+		}
+		if value = object[part]; value == nil {
+			return nil, nil
+		}
+	}
+	if string(value) == "null" {
+		return nil, nil
+	}
+	return value, nil
+}
+
+// executeList executes the collection request and decodes its envelope with the client's strategy.
+func (r *Request) executeList() ([]json.RawMessage, PageInfo, error) {
+	var body json.RawMessage
+	if err := r.Execute(&body); err != nil {
+		return nil, PageInfo{}, err
+	}
+	return r.executor().current().decodeEnvelope(body)
+}
+
+// decodeEnvelope returns the objects and pagination metadata of a collection response with the client's strategy.
+func (s *settings) decodeEnvelope(body []byte) ([]json.RawMessage, PageInfo, error) {
+	if s.envelope == nil {
+		return decodeList(body)
+	}
+	return s.envelope.Decode(body)
+}
+
+// encoder returns the client's envelope strategy as EnvelopeEncoder, or nil if it can't replace objects.
+func (s *settings) encoder() EnvelopeEncoder {
+	if s.envelope == nil {
+		return SkylarkEnvelope
+	}
+	encoder, _ := s.envelope.(EnvelopeEncoder)
+	return encoder
+}
+
+// replaceObjects returns the collection response body with its objects and metadata replaced.
+func (s *settings) replaceObjects(body []byte, objects []json.RawMessage, info PageInfo) ([]byte, error) {
+	encoder := s.encoder()
+	if encoder == nil {
+		return nil, fmt.Errorf("%w: %T", ErrEnvelopeNotEncodable, s.envelope)
+	}
+	return encoder.Replace(body, objects, info)
+}
+
+// decodeList decodes a response with Skylark's envelope and all of its metadata.
+func decodeList(body []byte) ([]json.RawMessage, PageInfo, error) {
+	var l List[json.RawMessage]
+	if err := json.Unmarshal(body, &l); err != nil {
+		return nil, PageInfo{}, err
+	}
+	var keys struct {
+		Meta map[string]json.RawMessage `json:"meta"`
+	}
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return nil, PageInfo{}, err
+	}
+	_, l.linked = keys.Meta["next"]
+	return l.Objects, l.PageInfo, nil
+}
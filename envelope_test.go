@@ -0,0 +1,61 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if offset >= 3 {
+			fmt.Fprint(w, `{"results": [], "pagination": {"total": 3, "next_url": null}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"results": [{"uid": "ep_%d"}, {"uid": "ep_%d"}], "pagination": {"total": 3, "next_url": "/next/"}}`, offset, offset+1)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithEnvelope(KeyEnvelope{Items: "results", Count: "pagination.total", Next: "pagination.next_url"}))
+	l, err := ExecuteList[struct{ UID string }](c.NewRequest("episodes", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Objects) != 2 || l.Objects[1].UID != "ep_1" || l.Count != 3 || !l.HasNext() {
+		t.Error("incorrect list", l)
+	}
+
+	var uids []string
+	it := c.NewRequest("episodes", "").Limit(2).Iterate()
+	for it.Next() {
+		uids = append(uids, string(it.Value()))
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if len(uids) != 4 || it.PageInfo().Count != 3 {
+		t.Error("incorrect iteration", uids, it.PageInfo())
+	}
+
+	var res struct {
+		Results []struct{ UID string } `json:"results"`
+	}
+	dedupe := NewDeduplicator()
+	dedupe.SeenObject([]byte(`{"uid": "ep_0"}`))
+	if err := c.NewRequest("episodes", "").Deduplicate(dedupe).PostProcess(SortObjects("-uid")).Execute(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Results) != 1 || res.Results[0].UID != "ep_1" {
+		t.Error("objects were not deduplicated under the envelope", res.Results)
+	}
+
+	if _, _, err := SkylarkEnvelope.Decode([]byte(`{"objects": [{}], "meta": {"total_count": 1, "next": null}}`)); err != nil {
+		t.Error(err)
+	}
+	if _, _, err := (KeyEnvelope{Items: "data.items"}).Decode([]byte(`{"data": []}`)); err == nil {
+		t.Error("expected error for a key below a non-object")
+	}
+}
@@ -1,6 +1,9 @@
 package client
 
-import "log"
+import (
+	"log"
+	"time"
+)
 
 // This is the most basic way to make a request.
 // It will request the person with ID "pers_123" with all it's fields.
@@ -39,3 +42,19 @@ func ExampleRequest_all() {
 		log.Fatal(err)
 	}
 }
+
+// A default client can be configured once at startup.
+// Requests without an endpoint then use the client's endpoint and settings.
+func ExampleSetDefaultClient() {
+	SetDefaultClient(NewClient(
+		WithBaseURL("https://test.com/api/"),
+		WithTimeout(10*time.Second)))
+
+	var p map[string]interface{}
+
+	r := NewRequest("", "person", "pers_123")
+	err := r.Execute(&p)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,28 @@
+package client
+
+import "context"
+
+// Executor executes requests and writes their results to the value pointed to by v.
+// It is implemented by Client, so code built on golark can depend on it and decorate execution,
+// for example to add tenants or quotas, or replace it with a mock in tests.
+type Executor interface {
+	Execute(ctx context.Context, r *Request, v interface{}) error
+}
+
+// ExecutorFunc adapts a function to an Executor.
+type ExecutorFunc func(ctx context.Context, r *Request, v interface{}) error
+
+// Execute implements Executor.
+func (f ExecutorFunc) Execute(ctx context.Context, r *Request, v interface{}) error {
+	return f(ctx, r, v)
+}
+
+// Execute implements Executor, it executes a copy of the request with ctx like Do.
+// A nil ctx keeps the request's context.
+func (c *Client) Execute(ctx context.Context, r *Request, v interface{}) error {
+	if ctx != nil {
+		r = r.copy()
+		r.ctx = ctx
+	}
+	return c.Do(r, v)
+}
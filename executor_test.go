@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// tenantExecutor decorates an Executor with a tenant header like an SDK built on golark would.
+type tenantExecutor struct {
+	next   Executor
+	tenant string
+}
+
+func (e tenantExecutor) Execute(ctx context.Context, r *Request, v interface{}) error {
+	r = r.Clone().Finalize(func(req *http.Request) error {
+		req.Header.Set("X-Tenant", e.tenant)
+		return nil
+	})
+	return e.next.Execute(ctx, r, v)
+}
+
+func TestExecutor(t *testing.T) {
+	var tenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant = r.Header.Get("X-Tenant")
+		w.Write([]byte(`{"uid":"ep_1"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	var e Executor = tenantExecutor{next: c, tenant: "acme"}
+	var episode struct{ UID string }
+	if err := e.Execute(context.Background(), c.NewRequest("episodes", "ep_1"), &episode); err != nil {
+		t.Fatal(err)
+	}
+	if episode.UID != "ep_1" || tenant != "acme" {
+		t.Error("unexpected response", episode, tenant)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := c.NewRequest("episodes", "ep_1")
+	if err := c.Execute(ctx, r, nil); !errors.Is(err, context.Canceled) {
+		t.Error("expected context error, got", err)
+	}
+	if err := r.Execute(nil); err != nil {
+		t.Error("the request's own context was modified", err)
+	}
+}
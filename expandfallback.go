@@ -0,0 +1,95 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// WithExpansionFallback sends GET requests whose expansions the server rejects, for example because they are too deep
+// or not allowed, again without expansions and fetches the referenced objects with separate requests instead.
+// They are stitched into the response, so it decodes to the same shape. Expansions are considered rejected
+// if the server responds with 400 Bad Request or 403 Forbidden and the error mentions expansion.
+// Only expanded top level fields are fetched separately, the objects are returned with all their fields.
+func WithExpansionFallback() Option {
+	return func(s *settings) {
+		s.expandFallback = true
+	}
+}
+
+// rejectsExpansion reports whether err rejects the expansions of the request.
+func rejectsExpansion(r *Request, err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest && apiErr.StatusCode != http.StatusForbidden {
+		return false
+	}
+	return len(r.expandedFields()) > 0 && strings.Contains(strings.ToLower(apiErr.Message), "expan")
+}
+
+// expandedFields returns the names of the expanded top level fields.
+func (r *Request) expandedFields() []string {
+	var names []string
+	for name, f := range r.Fields {
+		if f.IsExpanded {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// withoutExpansions returns a copy of the request that returns the self URLs of its expanded fields instead.
+func (r *Request) withoutExpansions() *Request {
+	c := r.Clone()
+	selects := selectsFields(r.Fields)
+	for name, f := range c.Fields {
+		if !f.IsExpanded {
+			continue
+		}
+		c.Fields[name] = &Field{Name: f.Name, IsIncluded: selects, SubFields: make(map[string]*Field), filters: f.filters}
+	}
+	return c
+}
+
+// expandSeparately executes the request without expansions and resolves the expanded references with separate requests.
+func (c *Client) expandSeparately(r *Request, v interface{}, res *Result, err error) error {
+	s := c.current()
+	fields := r.expandedFields()
+	s.log(r.ctx, "expansion rejected, fetching references separately", "collection", r.Collection, "fields", fields, "error", err)
+	var raw json.RawMessage
+	if err := c.transmit(r.withoutExpansions(), http.MethodGet, nil, jsonContentType, &raw, res); err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+
+	h := c.newHydration(r.ctx, HydrateOptions{}, fields)
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return err
+	}
+	if envelope["objects"] == nil {
+		hydrated, err := h.hydrate(raw, 1)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(hydrated, v)
+	}
+	var objects []json.RawMessage
+	if err := json.Unmarshal(envelope["objects"], &objects); err != nil {
+		return err
+	}
+	for i, object := range objects {
+		if objects[i], err = h.hydrate(object, 1); err != nil {
+			return err
+		}
+	}
+	if envelope["objects"], err = json.Marshal(objects); err != nil {
+		return err
+	}
+	if raw, err = json.Marshal(envelope); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
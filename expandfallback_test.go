@@ -0,0 +1,56 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestExpansionFallback(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fields_to_expand") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "Expansion of image_url is not allowed"}`))
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/images/") {
+			atomic.AddInt32(&fetches, 1)
+			w.Write([]byte(`{"uid": "img_1", "self": "/api/images/img_1/", "url": "a.png"}`))
+			return
+		}
+		if fields := r.URL.Query().Get("fields"); fields != "" && !strings.Contains(fields, "image_url") || strings.Contains(fields, "__") {
+			t.Error("incorrect fields without expansion", fields)
+		}
+		w.Write([]byte(`{"objects": [{"title": "a", "image_url": "/api/images/img_1/"}, {"title": "b", "image_url": "/api/images/img_1/"}]}`))
+	}))
+	defer server.Close()
+
+	type set struct {
+		Title string
+		Image struct {
+			UID string
+			URL string
+		} `json:"image_url"`
+	}
+	c := NewClient(WithBaseURL(server.URL), WithExpansionFallback())
+	l, err := ExecuteList[set](c.NewRequest("sets", "").
+		AddField(NewField("title")).
+		AddField(NewField("image_url").WithSubField(NewField("url"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Objects) != 2 || l.Objects[1].Image.URL != "a.png" {
+		t.Error("references were not stitched into the response", l.Objects)
+	}
+	if fetches != 1 {
+		t.Error("expected the shared reference to be fetched once, got", fetches)
+	}
+
+	var s struct{ Title string }
+	if err := NewClient(WithBaseURL(server.URL)).NewRequest("sets", "").Expand(NewField("image_url")).Execute(&s); err == nil {
+		t.Error("expected error without fallback")
+	}
+}
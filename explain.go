@@ -0,0 +1,101 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Explanation describes how the client would execute a request.
+type Explanation struct {
+	Method string
+	// URLs are the masked URLs that would be requested, there is more than one if the request is split.
+	URLs []string
+	// Cached is set if the response may be served from a cache.
+	Cached bool
+	Retry  RetryPolicy
+	// Expansions are the expanded reference fields, they are resolved by the server and make responses larger.
+	Expansions []string
+	DryRun     bool
+	// RateLimit is the client's limit in requests per second, zero if it has none.
+	RateLimit float64
+	// Cost is the estimated cost of the request, see WithCostModel.
+	Cost float64
+	// Warnings are problems that don't prevent the request from being sent, like exceeding the object limit with a warning hook set.
+	Warnings []string
+}
+
+// String formats the explanation for reviews and logs.
+func (e *Explanation) String() string {
+	var b strings.Builder
+	for _, u := range e.URLs {
+		fmt.Fprintf(&b, "%s %s\n", e.Method, u)
+	}
+	fmt.Fprintf(&b, "cached: %t\n", e.Cached)
+	fmt.Fprintf(&b, "retry: %d attempts, %s backoff\n", e.Retry.MaxAttempts, e.Retry.Backoff)
+	if len(e.Expansions) > 0 {
+		fmt.Fprintf(&b, "expansions: %s\n", strings.Join(e.Expansions, ", "))
+	}
+	if e.RateLimit > 0 {
+		fmt.Fprintf(&b, "rate limit: %g requests per second\n", e.RateLimit)
+	}
+	if e.Cost > 0 {
+		fmt.Fprintf(&b, "estimated cost: %g\n", e.Cost)
+	}
+	if e.DryRun {
+		b.WriteString("dry run: request is not sent\n")
+	}
+	for _, warning := range e.Warnings {
+		fmt.Fprintf(&b, "warning: %s\n", warning)
+	}
+	return b.String()
+}
+
+// Explain reports what executing the request would do without sending it.
+// It returns the error executing the request would fail with before sending it, like invalid endpoints or exceeding the object limit.
+func (r *Request) Explain() (*Explanation, error) {
+	c := r.executor()
+	s := c.current()
+	if s.err != nil {
+		return nil, s.err
+	}
+	e := &Explanation{Method: http.MethodGet, Cached: s.cache != nil && !r.skipCache, Retry: s.retry, DryRun: s.dryRun || r.dryRun}
+	if s.limiter != nil {
+		e.RateLimit = s.limiter.rate
+	}
+
+	for _, w := range s.deprecations(r) {
+		e.Warnings = append(e.Warnings, w.String())
+	}
+
+	parts := c.splitIn(r)
+	if parts == nil {
+		parts = []*Request{r}
+	}
+	for _, part := range parts {
+		endpoint, _, err := s.endpointFor(part)
+		if err != nil {
+			return nil, err
+		}
+		u, err := s.buildURL(part, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.objectLimitError(part, e.Method, u); err != nil {
+			if s.objectLimitWarn == nil {
+				return nil, err
+			}
+			e.Warnings = append(e.Warnings, err.Error())
+		}
+		e.URLs = append(e.URLs, s.maskURL(u))
+		e.Cost += s.estimate(part, e.Method)
+
+		q := u.Query()
+		if expand := q.Get("fields_to_expand"); expand != "" && len(e.Expansions) == 0 {
+			e.Expansions = strings.Split(expand, ",")
+			sort.Strings(e.Expansions)
+		}
+	}
+	return e, nil
+}
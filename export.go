@@ -0,0 +1,85 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// ExportOptions configures Export.
+type ExportOptions struct {
+	// Fields limits the exported fields, all fields are exported if it is empty.
+	Fields []*Field
+	// Expand lists reference fields that are expanded in the exported objects.
+	Expand []*Field
+	// Filters limit the exported objects.
+	Filters map[string]*Filter
+	// PageSize is the number of objects fetched per request. Defaults to the client's page size.
+	PageSize int
+	// Offset is the number of objects to skip, used to resume an export from a checkpoint.
+	Offset int
+	// Deduplicator skips objects it has already seen, share one between exports of overlapping queries
+	// to write every object only once.
+	Deduplicator *Deduplicator
+	// Checkpoint is called after every page is written with the offset an export resumed later should start at.
+	Checkpoint func(offset int) error
+	// Progress is called after every page and once more when the export is done.
+	Progress ProgressFunc
+}
+
+// Export writes every object of the collection to w as newline delimited JSON.
+// Objects are ordered by uid so an interrupted export can be resumed by passing the last checkpoint as Offset.
+// It returns the number of objects written.
+// Checkpoints count skipped duplicates, so they can be used as Offset with the same query.
+func (c *Client) Export(ctx context.Context, collection string, opts ExportOptions, w io.Writer) (int, error) {
+	r := c.NewRequest(collection, "").WithContext(ctx)
+	for _, f := range opts.Fields {
+		r.AddField(f)
+	}
+	for _, f := range opts.Expand {
+		r.Expand(f)
+	}
+	for field, filter := range opts.Filters {
+		r.WithFilter(field, filter)
+	}
+	r.OrderBy(NewField("uid"))
+
+	buf := bufio.NewWriter(w)
+	var line bytes.Buffer
+	offset, n := opts.Offset, 0
+	progress := newProgressTracker(opts.Progress, c.current().now)
+	err := c.eachPage(r, opts.PageSize, opts.Offset, progress, func(objects []json.RawMessage) error {
+		if len(objects) == 0 {
+			return nil
+		}
+		for _, object := range objects {
+			if opts.Deduplicator != nil && opts.Deduplicator.SeenObject(object) {
+				continue
+			}
+			line.Reset()
+			if err := json.Compact(&line, object); err != nil {
+				return err
+			}
+			line.WriteByte('\n')
+			if _, err := line.WriteTo(buf); err != nil {
+				return err
+			}
+			n++
+		}
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+		offset += len(objects)
+		progress.page(len(objects))
+		if opts.Checkpoint != nil {
+			return opts.Checkpoint(offset)
+		}
+		return nil
+	})
+	if err == nil {
+		progress.done()
+	}
+	return n, err
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var objects []string
+		for i := offset; i < offset+limit && i < 3; i++ {
+			objects = append(objects, fmt.Sprintf("{\n  \"uid\": \"ep_%d\"\n}", i))
+		}
+		fmt.Fprintf(w, `{"objects": [%s]}`, bytes.Join(toBytes(objects), []byte(",")))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	var checkpoints []int
+	var progress []Progress
+	n, err := NewClient(WithBaseURL(server.URL)).Export(context.Background(), "episodes", ExportOptions{
+		PageSize:   2,
+		Offset:     1,
+		Checkpoint: func(offset int) error { checkpoints = append(checkpoints, offset); return nil },
+		Progress:   func(p Progress) { progress = append(progress, p) },
+	}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\"uid\":\"ep_1\"}\n{\"uid\":\"ep_2\"}\n"
+	if n != 2 || out.String() != expected {
+		t.Errorf("incorrect export\nexpected: %q\ngot:      %q", expected, out.String())
+	}
+	if len(checkpoints) != 1 || checkpoints[0] != 3 {
+		t.Error("incorrect checkpoints", checkpoints)
+	}
+	if len(progress) != 2 || progress[0].Pages != 1 || progress[0].Objects != 2 || !progress[1].Done || progress[1].Total != 2 {
+		t.Error("incorrect progress", progress)
+	}
+}
+
+func TestExportDeduplication(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"objects": [{"uid": "ep_1"}, {"uid": "ep_%s"}]}`, r.URL.Query().Get("season"))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	dedupe := NewDeduplicator()
+	var out bytes.Buffer
+	total := 0
+	for _, season := range []string{"2", "3"} {
+		n, err := c.Export(context.Background(), "episodes", ExportOptions{
+			Filters:      map[string]*Filter{"season": NewFilter(Equals, season)},
+			Deduplicator: dedupe,
+		}, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += n
+	}
+
+	if total != 3 || dedupe.Len() != 3 {
+		t.Errorf("duplicates were exported:\n%s", out.String())
+	}
+}
+
+func toBytes(s []string) [][]byte {
+	b := make([][]byte, len(s))
+	for i := range s {
+		b[i] = []byte(s[i])
+	}
+	return b
+}
+
+func TestExportFields(t *testing.T) {
+	var fields, order string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields, order = r.URL.Query().Get("fields"), r.URL.Query().Get("order")
+		fmt.Fprint(w, `{"objects": [{"uid": "ep_1", "title": "Race"}]}`)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	n, err := NewClient(WithBaseURL(server.URL)).Export(context.Background(), "episodes", ExportOptions{
+		Fields: []*Field{NewField("title")},
+	}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || order != "uid" {
+		t.Errorf("expected 1 object ordered by uid, got %d ordered by %q", n, order)
+	}
+	if fields != "title" {
+		t.Errorf("expected only the title field, got %q", fields)
+	}
+}
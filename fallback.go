@@ -0,0 +1,102 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// WithUnknownFieldFallback retries a GET request once without the fields a 400 Bad Request response rejected,
+// so newer clients keep working against older deployments that don't know all fields yet.
+// Rejected fields are read from an "unknown_fields" or "invalid_fields" list in the error body,
+// or are the requested fields quoted in its message. The dropped fields are logged, reported to fn if it is not nil
+// and set in Result.DroppedFields.
+func WithUnknownFieldFallback(fn func(r *Request, dropped []string)) Option {
+	return func(s *settings) {
+		s.fieldFallback = true
+		s.fieldsDropped = fn
+	}
+}
+
+// rejectedFields returns the fields of the request the error rejected, nil if it didn't reject any.
+func rejectedFields(r *Request, err error) []string {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return nil
+	}
+	requested := make(map[string]bool)
+	collectFieldNames(r.Fields, requested)
+
+	var listed []string
+	for _, key := range []string{"unknown_fields", "invalid_fields"} {
+		values, _ := apiErr.Payload[key].([]interface{})
+		for _, v := range values {
+			if name, ok := v.(string); ok {
+				listed = append(listed, name)
+			}
+		}
+	}
+	if len(listed) == 0 {
+		for name := range requested {
+			if strings.Contains(apiErr.Message, `'`+name+`'`) || strings.Contains(apiErr.Message, `"`+name+`"`) ||
+				strings.Contains(apiErr.Message, `\"`+name+`\"`) {
+				listed = append(listed, name)
+			}
+		}
+	}
+
+	var rejected []string
+	for _, name := range listed {
+		if requested[name] {
+			rejected = append(rejected, name)
+		}
+	}
+	sort.Strings(rejected)
+	return rejected
+}
+
+// collectFieldNames adds the names of the fields and all their sub fields.
+func collectFieldNames(fields map[string]*Field, names map[string]bool) {
+	for _, f := range fields {
+		names[f.Name] = true
+		collectFieldNames(f.SubFields, names)
+	}
+}
+
+// withoutFields returns a copy of the request without the fields, they can be sub fields.
+func (r *Request) withoutFields(names []string) *Request {
+	c := r.Clone()
+	for _, name := range names {
+		removeField(c.Fields, name)
+	}
+	return c
+}
+
+func removeField(fields map[string]*Field, name string) {
+	delete(fields, name)
+	for _, f := range fields {
+		removeField(f.SubFields, name)
+	}
+}
+
+// fallback retries the request without the fields rejected by err and returns the result of the retry,
+// or err if no fields were rejected.
+func (c *Client) fallback(r *Request, v interface{}, res *Result, err error) error {
+	s := c.current()
+	if !s.fieldFallback {
+		return err
+	}
+	dropped := rejectedFields(r, err)
+	if len(dropped) == 0 {
+		return err
+	}
+	s.log(r.ctx, "retrying request without rejected fields", "collection", r.Collection, "fields", dropped, "error", err)
+	if s.fieldsDropped != nil {
+		s.fieldsDropped(r, dropped)
+	}
+	if res != nil {
+		res.DroppedFields = dropped
+	}
+	return c.transmit(r.withoutFields(dropped), http.MethodGet, nil, jsonContentType, v, res)
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnknownFieldFallback(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields := r.URL.Query().Get("fields")
+		queries = append(queries, fields)
+		switch {
+		case strings.Contains(fields, "rating"):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"unknown_fields": ["rating", "other"]}`))
+		case strings.Contains(fields, "image_urls__alt"):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "Invalid field 'image_urls__alt'"}`))
+		default:
+			w.Write([]byte(`{"uid": "ep_1"}`))
+		}
+	}))
+	defer server.Close()
+
+	var reported []string
+	c := NewClient(WithBaseURL(server.URL), WithUnknownFieldFallback(func(r *Request, dropped []string) {
+		reported = dropped
+	}))
+	r := c.NewRequest("episodes", "ep_1").AddField(NewField("title")).AddField(NewField("rating"))
+	res, err := r.ExecuteResult(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 2 || strings.Contains(queries[1], "rating") || !strings.Contains(queries[1], "title") {
+		t.Error("request was not retried without the rejected field", queries)
+	}
+	if !reflect.DeepEqual(res.DroppedFields, []string{"rating"}) || !reflect.DeepEqual(reported, []string{"rating"}) {
+		t.Error("dropped fields were not reported", res.DroppedFields, reported)
+	}
+	if _, ok := r.Fields["rating"]; !ok {
+		t.Error("fallback modified the request")
+	}
+
+	queries = nil
+	r = c.NewRequest("episodes", "ep_1").AddField(NewField("image_urls").WithSubField(NewField("alt")))
+	if err := r.Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 2 || strings.Contains(queries[1], "alt") {
+		t.Error("request was not retried without the rejected sub field", queries)
+	}
+
+	// without the option and for other errors the request is not retried
+	queries = nil
+	c = NewClient(WithBaseURL(server.URL))
+	if err := c.NewRequest("episodes", "ep_1").AddField(NewField("rating")).Execute(nil); err == nil || len(queries) != 1 {
+		t.Error("request was retried without fallback", queries, err)
+	}
+}
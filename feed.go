@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CheckpointStore persists the position of a ChangeFeed.
+type CheckpointStore interface {
+	// Load returns the last saved checkpoint, or an empty string if there is none.
+	Load(ctx context.Context) (string, error)
+	// Save stores the checkpoint.
+	Save(ctx context.Context, checkpoint string) error
+}
+
+// MemoryCheckpointStore is a CheckpointStore that keeps the checkpoint in memory.
+type MemoryCheckpointStore struct {
+	mu         sync.Mutex
+	checkpoint string
+}
+
+// Load implements CheckpointStore.
+func (s *MemoryCheckpointStore) Load(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpoint, nil
+}
+
+// Save implements CheckpointStore.
+func (s *MemoryCheckpointStore) Save(ctx context.Context, checkpoint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoint = checkpoint
+	return nil
+}
+
+// FeedEvent is emitted by a ChangeFeed for every new or updated object, or when polling failed.
+type FeedEvent struct {
+	// Object is the raw JSON of the object, it is nil if Err is set.
+	Object json.RawMessage
+	Err    error
+}
+
+// ChangeFeed repeatedly queries a collection for objects modified since its checkpoint.
+type ChangeFeed struct {
+	// Request is the collection request that is polled, it may contain fields and filters but no order.
+	Request *Request
+	// Store persists the checkpoint. Defaults to a MemoryCheckpointStore.
+	Store CheckpointStore
+	// Interval is the time between polls that returned no changes, it must be positive.
+	Interval time.Duration
+	// Field is the timestamp field compared to the checkpoint. Defaults to "modified".
+	Field string
+	// PageSize is the number of objects per page, every poll reads all pages of changes.
+	// It defaults to the request's limit or the client's page size, see ExecuteAll.
+	PageSize int
+}
+
+// errFeedStopped stops paging when the channel of a change feed is no longer received from.
+var errFeedStopped = errors.New("change feed stopped")
+
+// Run starts polling and returns a channel of changed objects.
+// Objects are ordered by Field and uid, so more than a page of objects sharing a timestamp are all returned.
+// The checkpoint is saved after all objects of a poll have been received from the channel.
+// The channel is closed when ctx is done or the client was closed.
+func (f *ChangeFeed) Run(ctx context.Context) <-chan FeedEvent {
+	events := make(chan FeedEvent)
+	go func() {
+		defer close(events)
+		// the checkpoint store is called in this goroutine, its panics can't be recovered by the caller
+		defer func() {
+			if !f.Request.executor().current().noPanics {
+				return
+			}
+			if p := recover(); p != nil {
+				select {
+				case events <- FeedEvent{Err: fmt.Errorf("%w: %v", ErrPanic, p)}:
+				case <-ctx.Done():
+				}
+			}
+		}()
+
+		field := f.Field
+		if field == "" {
+			field = "modified"
+		}
+		store := f.Store
+		if store == nil {
+			store = &MemoryCheckpointStore{}
+		}
+
+		send := func(event FeedEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		wait := func() bool {
+			return f.Request.executor().current().sleep(ctx, f.Interval) == nil
+		}
+
+		if f.Interval <= 0 {
+			send(FeedEvent{Err: fmt.Errorf("invalid change feed interval %v", f.Interval)})
+			return
+		}
+		if f.Request.additionalFields["order"] != "" {
+			send(FeedEvent{Err: errors.New("change feed requests must not be ordered, the feed orders them by its field")})
+			return
+		}
+		checkpoint, err := store.Load(ctx)
+		if err != nil {
+			send(FeedEvent{Err: err})
+			return
+		}
+		// objects at exactly the checkpoint are returned again by the next poll
+		seen := make(map[string]bool)
+
+		for {
+			poll := f.Request.copy()
+			poll.ctx = ctx
+			if selectsFields(poll.Fields) {
+				poll.AddField(NewField(field)).AddField(NewField("uid"))
+			}
+			// uid breaks ties so the pages of objects sharing a timestamp don't overlap
+			poll.OrderBy(NewField(field)).OrderBy(NewField("uid"))
+			if checkpoint != "" {
+				poll.WithFilter(field, NewFilter(GreaterThanOrEqual, checkpoint))
+			}
+
+			next, changes := checkpoint, 0
+			err := poll.executor().eachPage(poll, f.PageSize, 0, nil, func(objects []json.RawMessage) error {
+				for _, object := range poll.dedupe.filter(objects) {
+					var meta map[string]interface{}
+					if err := json.Unmarshal(object, &meta); err != nil {
+						if !send(FeedEvent{Err: err}) {
+							return errFeedStopped
+						}
+						continue
+					}
+					modified, _ := meta[field].(string)
+					key := string(object)
+					if uid, ok := meta["uid"].(string); ok {
+						key = uid
+					}
+					if modified == checkpoint && seen[key] {
+						continue
+					}
+					if modified != next {
+						next = modified
+						seen = make(map[string]bool)
+					}
+					seen[key] = true
+					changes++
+					if !send(FeedEvent{Object: object}) {
+						return errFeedStopped
+					}
+				}
+				return nil
+			})
+			if errors.Is(err, errFeedStopped) {
+				return
+			}
+			if err != nil {
+				if ctx.Err() != nil || !send(FeedEvent{Err: err}) || errors.Is(err, ErrClientClosed) || !wait() {
+					return
+				}
+				continue
+			}
+
+			if next != checkpoint {
+				if err := store.Save(ctx, next); err != nil && !send(FeedEvent{Err: err}) {
+					return
+				}
+				checkpoint = next
+			}
+			if changes == 0 && !wait() {
+				return
+			}
+		}
+	}()
+	return events
+}
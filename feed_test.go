@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestChangeFeed(t *testing.T) {
+	objects := []map[string]string{
+		{"uid": "set_1", "modified": "2020-01-01T00:00:00Z"},
+		{"uid": "set_2", "modified": "2020-01-02T00:00:00Z"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var res struct {
+			Objects []map[string]string `json:"objects"`
+		}
+		for _, object := range objects {
+			if object["modified"] >= r.URL.Query().Get("modified__gte") {
+				res.Objects = append(res.Objects, object)
+			}
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	store := &MemoryCheckpointStore{}
+	feed := &ChangeFeed{Request: NewRequest(server.URL, "sets", ""), Store: store, Interval: 10 * time.Millisecond}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	events := feed.Run(ctx)
+
+	for _, expected := range []string{"set_1", "set_2"} {
+		event := <-events
+		if event.Err != nil {
+			t.Fatal("unexpected error:", event.Err)
+		}
+		var object map[string]string
+		json.Unmarshal(event.Object, &object)
+		if object["uid"] != expected {
+			t.Errorf("incorrect object\nexpected: %s\ngot:      %s", expected, object["uid"])
+		}
+	}
+
+	select {
+	case event := <-events:
+		t.Error("unexpected event", string(event.Object), event.Err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	cancel()
+	for range events {
+	}
+
+	checkpoint, _ := store.Load(context.Background())
+	if checkpoint != "2020-01-02T00:00:00Z" {
+		t.Error("incorrect checkpoint", checkpoint)
+	}
+}
+
+func TestChangeFeedSharedTimestamp(t *testing.T) {
+	objects := []map[string]string{
+		{"uid": "set_1", "modified": "2020-01-01T00:00:00Z"},
+		{"uid": "set_2", "modified": "2020-01-01T00:00:00Z"},
+		{"uid": "set_3", "modified": "2020-01-01T00:00:00Z"},
+	}
+	var orders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		orders = append(orders, q.Get("order"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		var matching []map[string]string
+		for _, object := range objects {
+			if object["modified"] >= q.Get("modified__gte") {
+				matching = append(matching, object)
+			}
+		}
+		res := struct {
+			Objects []map[string]string `json:"objects"`
+		}{Objects: []map[string]string{}}
+		for i := offset; i < len(matching) && i < offset+limit; i++ {
+			res.Objects = append(res.Objects, matching[i])
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	feed := &ChangeFeed{Request: NewRequest(server.URL, "sets", ""), Interval: 10 * time.Millisecond, PageSize: 2}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	events := feed.Run(ctx)
+	for _, expected := range []string{"set_1", "set_2", "set_3"} {
+		event := <-events
+		if event.Err != nil {
+			t.Fatal("unexpected error:", event.Err)
+		}
+		var object map[string]string
+		json.Unmarshal(event.Object, &object)
+		if object["uid"] != expected {
+			t.Errorf("incorrect object\nexpected: %s\ngot:      %s", expected, object["uid"])
+		}
+	}
+	select {
+	case event := <-events:
+		t.Error("unexpected event", string(event.Object), event.Err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	cancel()
+	for range events {
+	}
+	if orders[0] != "modified,uid" {
+		t.Error("incorrect order", orders[0])
+	}
+}
+
+func TestChangeFeedInvalidInterval(t *testing.T) {
+	feed := &ChangeFeed{Request: NewRequest("http://example.com", "episodes", "")}
+	events := feed.Run(context.Background())
+	event, ok := <-events
+	if !ok || event.Err == nil {
+		t.Fatal("expected an error for a zero interval")
+	}
+	if _, ok := <-events; ok {
+		t.Error("channel not closed after the error")
+	}
+}
+
+func TestChangeFeedSleeper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"objects": []}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var slept []time.Duration
+	c := NewClient(WithBaseURL(server.URL), WithSleeper(SleeperFunc(func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		if len(slept) == 2 {
+			cancel()
+		}
+		return ctx.Err()
+	})))
+	feed := &ChangeFeed{Request: c.NewRequest("sets", ""), Interval: time.Hour}
+	for event := range feed.Run(ctx) {
+		t.Error("unexpected event", string(event.Object), event.Err)
+	}
+	if len(slept) != 2 || slept[0] != time.Hour {
+		t.Error("polls did not wait with the client's sleeper", slept)
+	}
+}
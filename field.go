@@ -19,7 +19,7 @@ func NewField(name string) *Field {
 	return &Field{Name: name, SubFields: make(map[string]*Field), IsIncluded: true}
 }
 
-func (f *Field) apply(v url.Values) url.Values {
+func (f *Field) apply(v url.Values, enc TimeEncoder) url.Values {
 	if f.IsIncluded {
 		v = addValue(v, "fields", f.Name)
 	}
@@ -27,23 +27,31 @@ func (f *Field) apply(v url.Values) url.Values {
 		v = addValue(v, "fields_to_expand", f.Name)
 	}
 	for _, filter := range f.filters {
-		var key string
-		if filter.c == "" {
-			key = f.Name
-		} else {
-			key = fmt.Sprintf("%s__%s", f.Name, filter.c)
+		for _, term := range filter.terms() {
+			v.Add(term.key(f.Name), term.encode(enc))
 		}
-		v.Add(key, filter.value)
 	}
 	for _, field := range f.SubFields {
-		v = field.apply(v)
+		v = field.apply(v, enc)
 	}
 	return v
 }
 
+// clone returns a deep copy of the field and its sub fields.
+func (f *Field) clone() *Field {
+	c := *f
+	c.filters = append([]*Filter(nil), f.filters...)
+	c.SubFields = make(map[string]*Field, len(f.SubFields))
+	for name, sub := range f.SubFields {
+		c.SubFields[name] = sub.clone()
+	}
+	return &c
+}
+
 // WithSubField expands a field and adds the given field to the list of filds to be returned.
 // Only use this if the field is a reference to a different object!
 func (f *Field) WithSubField(subField *Field) *Field {
+	fieldChanged()
 	f.IsExpanded = true
 	subField.adjustName(f.Name)
 	f.SubFields[subField.Name] = subField
@@ -59,16 +67,49 @@ func (f *Field) adjustName(parentName string) {
 
 // WithFilter applies a fielter to the field.
 func (f *Field) WithFilter(filter *Filter) *Field {
+	fieldChanged()
 	f.filters = append(f.filters, filter)
 	return f
 }
 
+// ExpandChild expands the field and its child reference field, and returns both.
+// Use WithSubField on the child to select the fields of the nested object.
+//
+//	NewField("driver_urls").ExpandChild(NewField("team_url").WithSubField(NewField("name")))
+//
+// requests fields=driver_urls,driver_urls__team_url,driver_urls__team_url__name
+// and fields_to_expand=driver_urls,driver_urls__team_url.
+func (f *Field) ExpandChild(child *Field) *Field {
+	fieldChanged()
+	f.IsExpanded = true
+	child.IsExpanded = true
+	child.adjustName(f.Name)
+	f.SubFields[child.Name] = child
+	return f
+}
+
 // Expand expands a field without explicitly listing it as a field to return.
 // This is usefult if you want to return all fields.
 func (f *Field) Expand(subField *Field) *Field {
+	fieldChanged()
 	subField.IsExpanded = true
 	subField.IsIncluded = false
 	subField.adjustName(f.Name)
 	f.SubFields[subField.Name] = subField
 	return f
 }
+
+// filterErr returns the first error of the filters of the field and its sub fields.
+func (f *Field) filterErr() error {
+	for _, filter := range f.filters {
+		if err := filter.firstErr(); err != nil {
+			return err
+		}
+	}
+	for _, field := range f.SubFields {
+		if err := field.filterErr(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
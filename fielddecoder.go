@@ -0,0 +1,174 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldDecoder converts the decoded JSON value of a field, with numbers as json.Number, to the value models decode.
+// The returned value is encoded to JSON again, a time.Duration for example as nanoseconds.
+type FieldDecoder func(value interface{}) (interface{}, error)
+
+// WithFieldDecoder decodes the field of every object of the collection with dec before responses are decoded,
+// so models can use typed values like time.Duration instead of raw strings:
+//
+//	client.WithFieldDecoder("episodes", "duration", client.DurationDecoder)
+//
+//	type Episode struct {
+//		Duration time.Duration `json:"duration"`
+//	}
+//
+// Fields of nested objects are separated by "__". Null and missing values are not decoded.
+func WithFieldDecoder(collection, field string, dec FieldDecoder) Option {
+	return func(s *settings) {
+		if s.fieldDecoders == nil {
+			s.fieldDecoders = make(map[string]map[string]FieldDecoder)
+		}
+		if s.fieldDecoders[collection] == nil {
+			s.fieldDecoders[collection] = make(map[string]FieldDecoder)
+		}
+		s.fieldDecoders[collection][field] = dec
+	}
+}
+
+// DurationDecoder decodes ISO-8601 durations like PT1H30M5.5S to a time.Duration.
+var DurationDecoder FieldDecoder = func(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("duration %v is not a string", value)
+	}
+	return ParseISODuration(s)
+}
+
+// TimecodeDecoder decodes timecodes stored as a number of frames at the frame rate to a time.Duration.
+func TimecodeDecoder(fps float64) FieldDecoder {
+	return func(value interface{}) (interface{}, error) {
+		n, ok := value.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("timecode %v is not a number of frames", value)
+		}
+		frames, err := n.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return time.Duration(math.Round(frames / fps * float64(time.Second))), nil
+	}
+}
+
+// ParseISODuration parses an ISO-8601 duration with weeks, days, hours, minutes and seconds, like P1DT2H or PT0.5S.
+// Days are 24 hours long. Years and months are rejected since their length varies.
+func ParseISODuration(s string) (time.Duration, error) {
+	rest, negative := strings.TrimPrefix(s, "-"), strings.HasPrefix(s, "-")
+	if !strings.HasPrefix(rest, "P") || len(rest) < 3 || strings.HasSuffix(rest, "T") {
+		return 0, fmt.Errorf("invalid ISO-8601 duration %q", s)
+	}
+	rest = rest[1:]
+	var d float64
+	inTime := false
+	for rest != "" {
+		if rest[0] == 'T' {
+			if inTime {
+				return 0, fmt.Errorf("invalid ISO-8601 duration %q", s)
+			}
+			inTime, rest = true, rest[1:]
+			continue
+		}
+		i := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid ISO-8601 duration %q", s)
+		}
+		n, err := strconv.ParseFloat(strings.Replace(rest[:i], ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO-8601 duration %q", s)
+		}
+		var unit time.Duration
+		switch designator := rest[i]; {
+		case !inTime && designator == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && designator == 'D':
+			unit = 24 * time.Hour
+		case inTime && designator == 'H':
+			unit = time.Hour
+		case inTime && designator == 'M':
+			unit = time.Minute
+		case inTime && designator == 'S':
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("unsupported ISO-8601 duration %q", s)
+		}
+		d += n * float64(unit)
+		rest = rest[i+1:]
+	}
+	if negative {
+		d = -d
+	}
+	return time.Duration(math.Round(d)), nil
+}
+
+// withoutFieldDecoding returns a copy of the request that is sent without applying the client's field decoders.
+func (r *Request) withoutFieldDecoding() *Request {
+	c := *r
+	c.rawFields = true
+	return &c
+}
+
+// decodeFields applies the decoders to the object or the objects of the collection response and decodes it into v.
+func decodeFields(decoders map[string]FieldDecoder, data json.RawMessage, v interface{}) error {
+	var body interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&body); err != nil {
+		return err
+	}
+	object, ok := body.(map[string]interface{})
+	if !ok {
+		return json.Unmarshal(data, v)
+	}
+	targets := []interface{}{object}
+	if objects, ok := object["objects"].([]interface{}); ok {
+		targets = objects
+	}
+	for field, decoder := range decoders {
+		for _, target := range targets {
+			if err := decodeField(target, strings.Split(field, "__"), decoder); err != nil {
+				return fmt.Errorf("field %s: %w", field, err)
+			}
+		}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// decodeField replaces the value at the path with its decoded value, lists on the path are decoded element wise.
+func decodeField(value interface{}, path []string, decoder FieldDecoder) error {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if err := decodeField(item, path, decoder); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		field, ok := v[path[0]]
+		if !ok || field == nil {
+			return nil
+		}
+		if len(path) > 1 {
+			return decodeField(field, path[1:], decoder)
+		}
+		decoded, err := decoder(field)
+		if err != nil {
+			return err
+		}
+		v[path[0]] = decoded
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		in  string
+		out time.Duration
+	}{
+		{"PT1H30M5.5S", time.Hour + 30*time.Minute + 5500*time.Millisecond},
+		{"P1DT2H", 26 * time.Hour},
+		{"P1W", 7 * 24 * time.Hour},
+		{"PT0,25S", 250 * time.Millisecond},
+		{"-PT90S", -90 * time.Second},
+	}
+	for _, tt := range tests {
+		d, err := ParseISODuration(tt.in)
+		if err != nil || d != tt.out {
+			t.Errorf("%s: expected %v, got %v %v", tt.in, tt.out, d, err)
+		}
+	}
+	for _, invalid := range []string{"", "P", "PT", "P1Y", "P1M", "PT1D", "P1H", "1H", "PTXS", "P1DT"} {
+		if _, err := ParseISODuration(invalid); err == nil {
+			t.Errorf("%q: expected error", invalid)
+		}
+	}
+}
+
+func TestFieldDecoders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/episodes/ep_1/" {
+			w.Write([]byte(`{"duration": "PT1M", "chapters": [{"start": 50}, {"start": null}]}`))
+			return
+		}
+		w.Write([]byte(`{"objects": [{"duration": "PT2H"}, {"duration": null}], "meta": {"total_count": 2}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL),
+		WithFieldDecoder("episodes", "duration", DurationDecoder),
+		WithFieldDecoder("episodes", "chapters__start", TimecodeDecoder(25)))
+	type episode struct {
+		Duration time.Duration `json:"duration"`
+		Chapters []struct {
+			Start time.Duration `json:"start"`
+		} `json:"chapters"`
+	}
+	var ep episode
+	if err := c.NewRequest("episodes", "ep_1").Execute(&ep); err != nil {
+		t.Fatal(err)
+	}
+	if ep.Duration != time.Minute || len(ep.Chapters) != 2 || ep.Chapters[0].Start != 2*time.Second {
+		t.Error("incorrect decoded object", ep)
+	}
+
+	l, err := ExecuteList[episode](c.NewRequest("episodes", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Objects) != 2 || l.Objects[0].Duration != 2*time.Hour || l.Count != 2 {
+		t.Error("incorrect decoded list", l)
+	}
+
+	raw, err := c.NewRequest("episodes", "ep_1").ExecuteRaw()
+	if err != nil || string(raw) != `{"duration": "PT1M", "chapters": [{"start": 50}, {"start": null}]}` {
+		t.Error("raw response was decoded", string(raw), err)
+	}
+
+	c = NewClient(WithBaseURL(server.URL), WithFieldDecoder("episodes", "chapters", DurationDecoder))
+	if err := c.NewRequest("episodes", "ep_1").Execute(&ep); err == nil {
+		t.Error("expected error for a value the decoder can't decode")
+	}
+}
@@ -1,10 +1,26 @@
 package client
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
 // Filter represents a Skylark request filter
 // It is used to constrain a request by a field's value
 type Filter struct {
 	c     constraint
 	value string
+	// time is the filter's value if it was created from a time, it is encoded with the client's TimeEncoder.
+	time *time.Time
+	// items are the elements of a list value that contains times, each is encoded on its own.
+	items []*Filter
+	err   error
+	// placeholder is the name the value is bound to with Request.Bind.
+	placeholder string
+	// and holds further filters on the same field that are all applied, like the upper bound of a range.
+	and []*Filter
 }
 
 type constraint string
@@ -12,13 +28,102 @@ type constraint string
 const (
 	// GreaterThan contrains to fields that are greater than a given value
 	GreaterThan = constraint("gt")
+	// GreaterThanOrEqual contrains to fields that are greater than or equal to a given value
+	GreaterThanOrEqual = constraint("gte")
 	// LessThan contrains to fields that are less than a given value
 	LessThan = constraint("lt")
+	// LessThanOrEqual contrains to fields that are less than or equal to a given value
+	LessThanOrEqual = constraint("lte")
+	// Contains contrains to fields that contain a given value
+	Contains = constraint("contains")
+	// StartsWith contrains to fields that start with a given value
+	StartsWith = constraint("startswith")
 	// Equals contrains to fields that equal a given value
 	Equals = constraint("")
+	// In contrains to fields that equal one of the given comma separated values
+	In = constraint("in")
 )
 
 // NewFilter creates a new filter with a given constraint and value
+// An unknown constraint or a value it rejects makes requests using the filter fail, see RegisterComparator.
 func NewFilter(c constraint, value string) *Filter {
-	return &Filter{c: c, value: value}
+	return &Filter{c: c, value: value, err: checkComparator(c, value)}
+}
+
+// NewInFilter creates a filter matching fields that equal one of the given values.
+func NewInFilter(values ...interface{}) *Filter {
+	return NewFilterValue(In, values)
+}
+
+// NewRangeFilter creates a filter matching fields between min and max, both inclusive.
+// A nil bound leaves the range open on that side.
+func NewRangeFilter(min, max interface{}) *Filter {
+	switch {
+	case min == nil && max == nil:
+		return &Filter{err: errors.New("range filter without bounds")}
+	case min == nil:
+		return NewFilterValue(LessThanOrEqual, max)
+	case max == nil:
+		return NewFilterValue(GreaterThanOrEqual, min)
+	}
+	f := NewFilterValue(GreaterThanOrEqual, min)
+	f.and = []*Filter{NewFilterValue(LessThanOrEqual, max)}
+	return f
+}
+
+// NewNotFilter creates a filter matching fields that don't match f.
+// The constraint is prefixed with not, field__not for Equals and field__not_in for In.
+// Range filters can't be negated.
+func NewNotFilter(f *Filter) *Filter {
+	if len(f.and) > 0 {
+		return &Filter{err: errors.New("range filters can't be negated")}
+	}
+	not := *f
+	if f.c == Equals {
+		not.c = "not"
+	} else {
+		not.c = "not_" + f.c
+	}
+	return &not
+}
+
+// terms returns the filter and the further filters applied with it.
+func (f *Filter) terms() []*Filter {
+	return append([]*Filter{f}, f.and...)
+}
+
+// firstErr returns the first error of the filter's terms.
+func (f *Filter) firstErr() error {
+	for _, term := range f.terms() {
+		if term.err != nil {
+			return term.err
+		}
+	}
+	return nil
+}
+
+// key returns the query parameter name of the filter for a field.
+func (f *Filter) key(field string) string {
+	if f.c == Equals {
+		return field
+	}
+	return fmt.Sprintf("%s__%s", field, f.c)
+}
+
+// encode returns the filter's value, times are encoded with enc if it is set.
+func (f *Filter) encode(enc TimeEncoder) string {
+	if enc == nil {
+		return f.value
+	}
+	if f.time != nil {
+		return enc(*f.time)
+	}
+	if len(f.items) > 0 {
+		values := make([]string, len(f.items))
+		for i, item := range f.items {
+			values[i] = item.encode(enc)
+		}
+		return strings.Join(values, ",")
+	}
+	return f.value
 }
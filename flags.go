@@ -0,0 +1,37 @@
+package client
+
+// experimentalParam is a query parameter that is only sent if its flag is enabled on the client.
+type experimentalParam struct {
+	flag  string
+	key   string
+	value string
+}
+
+// WithFeatureFlags enables the named flags guarding experimental query parameters.
+func WithFeatureFlags(flags ...string) Option {
+	return func(s *settings) {
+		if s.flags == nil {
+			s.flags = make(map[string]bool)
+		}
+		for _, flag := range flags {
+			s.flags[flag] = true
+		}
+	}
+}
+
+// WithoutFeatureFlags disables the named flags.
+func WithoutFeatureFlags(flags ...string) Option {
+	return func(s *settings) {
+		for _, flag := range flags {
+			delete(s.flags, flag)
+		}
+	}
+}
+
+// WithExperimentalParam adds a query parameter that is only sent
+// if the client executing the request has the given flag enabled.
+// This allows using deployment specific parameters golark doesn't support yet.
+func (r *Request) WithExperimentalParam(flag, key, value string) *Request {
+	r.experimental = append(r.experimental, experimentalParam{flag: flag, key: key, value: value})
+	return r
+}
@@ -0,0 +1,54 @@
+package golarktest
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	client "github.com/SoMuchForSubtlety/golark"
+)
+
+// Executor is a client.Executor that records the executed requests and answers them without a server.
+type Executor struct {
+	// Respond returns the response to a request. It is encoded to JSON and decoded into the destination,
+	// an error fails the request. A nil Respond answers every request with an empty object.
+	Respond func(r *client.Request) (interface{}, error)
+
+	mu       sync.Mutex
+	requests []*client.Request
+}
+
+// Execute implements client.Executor. A nil ctx is treated as one that is never done.
+func (e *Executor) Execute(ctx context.Context, r *client.Request, v interface{}) error {
+	e.mu.Lock()
+	e.requests = append(e.requests, r.Clone())
+	e.mu.Unlock()
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	var res interface{} = struct{}{}
+	if e.Respond != nil {
+		var err error
+		if res, err = e.Respond(r); err != nil {
+			return err
+		}
+	}
+	if v == nil {
+		return nil
+	}
+	body, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// Requests returns copies of the requests executed so far.
+func (e *Executor) Requests() []*client.Request {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*client.Request(nil), e.requests...)
+}
@@ -0,0 +1,408 @@
+// Package golarktest provides an in-memory fake of the Skylark API for testing code that uses golark,
+// and an Executor mock for code that depends on client.Executor.
+//
+// The fake serves seeded fixtures and understands the fields, fields_to_expand, filter, order, limit and offset
+// query parameters, so tests can assert behavior without network access.
+//
+//	srv := golarktest.NewServer()
+//	defer srv.Close()
+//	srv.Seed("drivers", map[string]interface{}{"uid": "drv_1", "name": "Hamilton", "team": "/teams/team_1/"})
+//	c := client.NewClient(client.WithBaseURL(srv.URL))
+package golarktest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server is a fake Skylark API serving seeded objects.
+// Collections are served at /<collection>/ and objects at /<collection>/<uid>/, which is also their self URL.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.RWMutex
+	collections map[string][]map[string]interface{}
+}
+
+// NewServer starts a fake server without objects, close it when it is no longer needed.
+func NewServer() *Server {
+	s := &Server{collections: make(map[string][]map[string]interface{})}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Seed adds objects to a collection, they can be maps or structs that encode to JSON objects with a uid.
+// Objects without a self field get their self URL. Reference fields hold self URLs, they are replaced with the
+// referenced objects when expanded.
+func (s *Server) Seed(collection string, objects ...interface{}) error {
+	decoded := make([]map[string]interface{}, 0, len(objects))
+	for _, object := range objects {
+		data, err := json.Marshal(object)
+		if err != nil {
+			return err
+		}
+		var o map[string]interface{}
+		if err := json.Unmarshal(data, &o); err != nil {
+			return fmt.Errorf("fixture is not an object: %w", err)
+		}
+		uid, ok := o["uid"].(string)
+		if !ok || uid == "" {
+			return errors.New("fixture has no uid")
+		}
+		if _, ok := o["self"]; !ok {
+			o["self"] = selfURL(collection, uid)
+		}
+		decoded = append(decoded, o)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collections[collection] = append(s.collections[collection], decoded...)
+	return nil
+}
+
+// Reset removes all objects.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collections = make(map[string][]map[string]interface{})
+}
+
+func selfURL(collection, uid string) string {
+	return "/" + collection + "/" + uid + "/"
+}
+
+// reserved are the query parameters that are not filters.
+var reserved = map[string]bool{"fields": true, "fields_to_expand": true, "order": true, "limit": true, "offset": true}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) > 2 || parts[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	q := r.URL.Query()
+	fields := splitList(q.Get("fields"))
+	expand := make(map[string]bool)
+	for _, f := range splitList(q.Get("fields_to_expand")) {
+		expand[f] = true
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(parts) == 2 {
+		object := s.lookup(parts[0], parts[1])
+		if object == nil {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, s.project(object, fields, expand, ""))
+		return
+	}
+
+	var matches []map[string]interface{}
+	for _, object := range s.collections[parts[0]] {
+		ok, err := matchesFilters(object, q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if ok {
+			matches = append(matches, object)
+		}
+	}
+	if order := q.Get("order"); order != "" {
+		sortObjects(matches, splitList(order))
+	}
+
+	total := len(matches)
+	offset, err := intParam(q.Get("offset"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit, err := intParam(q.Get("limit"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+
+	objects := make([]map[string]interface{}, 0, end-offset)
+	for _, object := range matches[offset:end] {
+		objects = append(objects, s.project(object, fields, expand, ""))
+	}
+	meta := map[string]interface{}{"total_count": total, "limit": limit, "offset": offset, "next": "", "previous": ""}
+	if limit > 0 && end < total {
+		meta["next"] = pageURL(r, limit, end)
+	}
+	if limit > 0 && offset > 0 {
+		previous := offset - limit
+		if previous < 0 {
+			previous = 0
+		}
+		meta["previous"] = pageURL(r, limit, previous)
+	}
+	writeJSON(w, map[string]interface{}{"objects": objects, "meta": meta})
+}
+
+func (s *Server) lookup(collection, uid string) map[string]interface{} {
+	for _, object := range s.collections[collection] {
+		if object["uid"] == uid {
+			return object
+		}
+	}
+	return nil
+}
+
+// resolve returns the object a self URL refers to, or nil.
+func (s *Server) resolve(self string) map[string]interface{} {
+	parts := strings.Split(strings.Trim(self, "/"), "/")
+	if len(parts) < 2 {
+		return nil
+	}
+	return s.lookup(parts[len(parts)-2], parts[len(parts)-1])
+}
+
+// project returns the selected fields of the object, expanding references in expanded fields.
+// Fields of expanded objects are selected with their path, like team__name.
+func (s *Server) project(object map[string]interface{}, fields []string, expand map[string]bool, prefix string) map[string]interface{} {
+	selected := make(map[string][]string)
+	for _, f := range fields {
+		name, sub := f, ""
+		if i := strings.Index(f, "__"); i >= 0 {
+			name, sub = f[:i], f[i+2:]
+		}
+		if sub != "" {
+			selected[name] = append(selected[name], sub)
+		} else if _, ok := selected[name]; !ok {
+			selected[name] = nil
+		}
+	}
+
+	res := make(map[string]interface{})
+	for name, value := range object {
+		sub, ok := selected[name]
+		if len(fields) > 0 && !ok {
+			continue
+		}
+		if expand[prefix+name] {
+			value = s.expand(value, sub, expand, prefix+name+"__")
+		}
+		res[name] = value
+	}
+	return res
+}
+
+// expand replaces the self URLs in a field value with the referenced objects.
+func (s *Server) expand(value interface{}, fields []string, expand map[string]bool, prefix string) interface{} {
+	switch v := value.(type) {
+	case string:
+		if object := s.resolve(v); object != nil {
+			return s.project(object, fields, expand, prefix)
+		}
+	case []interface{}:
+		expanded := make([]interface{}, len(v))
+		for i, item := range v {
+			expanded[i] = s.expand(item, fields, expand, prefix)
+		}
+		return expanded
+	}
+	return value
+}
+
+// matchesFilters reports whether the object matches all filters of the query.
+func matchesFilters(object map[string]interface{}, q map[string][]string) (bool, error) {
+	for key, values := range q {
+		if reserved[key] {
+			continue
+		}
+		path, op := key, ""
+		if i := strings.LastIndex(key, "__"); i >= 0 && knownOp(key[i+2:]) {
+			path, op = key[:i], key[i+2:]
+		}
+		for _, value := range values {
+			ok, err := match(lookupPath(object, path), op, value)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+var ops = map[string]bool{"gt": true, "gte": true, "lt": true, "lte": true, "in": true, "contains": true, "startswith": true}
+
+func knownOp(op string) bool {
+	if op == "not" {
+		return true
+	}
+	return ops[strings.TrimPrefix(op, "not_")]
+}
+
+// match applies a lookup operator to a field value.
+func match(field interface{}, op, value string) (bool, error) {
+	if op == "not" {
+		ok, err := match(field, "", value)
+		return !ok, err
+	}
+	if strings.HasPrefix(op, "not_") {
+		ok, err := match(field, strings.TrimPrefix(op, "not_"), value)
+		return !ok, err
+	}
+	switch op {
+	case "":
+		return compare(field, value) == 0, nil
+	case "gt":
+		return field != nil && compare(field, value) > 0, nil
+	case "gte":
+		return field != nil && compare(field, value) >= 0, nil
+	case "lt":
+		return field != nil && compare(field, value) < 0, nil
+	case "lte":
+		return field != nil && compare(field, value) <= 0, nil
+	case "in":
+		for _, v := range strings.Split(value, ",") {
+			if compare(field, v) == 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "contains":
+		if list, ok := field.([]interface{}); ok {
+			for _, item := range list {
+				if compare(item, value) == 0 {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		s, ok := field.(string)
+		return ok && strings.Contains(s, value), nil
+	case "startswith":
+		s, ok := field.(string)
+		return ok && strings.HasPrefix(s, value), nil
+	}
+	return false, fmt.Errorf("unknown lookup %q", op)
+}
+
+// compare compares a decoded JSON value to a query parameter value.
+func compare(field interface{}, value string) int {
+	switch f := field.(type) {
+	case float64:
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			switch {
+			case f < v:
+				return -1
+			case f > v:
+				return 1
+			}
+			return 0
+		}
+	case bool:
+		if v, err := strconv.ParseBool(value); err == nil {
+			switch {
+			case f == v:
+				return 0
+			case !f:
+				return -1
+			}
+			return 1
+		}
+	case nil:
+		if value == "" || value == "null" {
+			return 0
+		}
+		return -1
+	}
+	return strings.Compare(fmt.Sprint(field), value)
+}
+
+// compareValues orders two decoded JSON values, missing values sort first.
+func compareValues(a, b interface{}) int {
+	if b == nil {
+		if a == nil {
+			return 0
+		}
+		return 1
+	}
+	if s, ok := b.(string); ok {
+		return compare(a, s)
+	}
+	data, _ := json.Marshal(b)
+	return compare(a, string(data))
+}
+
+// sortObjects sorts by the fields of an order parameter, fields prefixed with - are sorted descending.
+func sortObjects(objects []map[string]interface{}, fields []string) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		for _, field := range fields {
+			desc := strings.HasPrefix(field, "-")
+			path := strings.TrimPrefix(field, "-")
+			if c := compareValues(lookupPath(objects[i], path), lookupPath(objects[j], path)); c != 0 {
+				return c < 0 != desc
+			}
+		}
+		return false
+	})
+}
+
+// lookupPath returns a field value, fields of nested objects are separated by "__".
+func lookupPath(object map[string]interface{}, path string) interface{} {
+	var value interface{} = object
+	for _, key := range strings.Split(path, "__") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
+
+func pageURL(r *http.Request, limit, offset int) string {
+	q := r.URL.Query()
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("offset", strconv.Itoa(offset))
+	return r.URL.Path + "?" + q.Encode()
+}
+
+func intParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid integer %q", value)
+	}
+	return n, nil
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,107 @@
+package golarktest
+
+import (
+	"context"
+	"testing"
+
+	client "github.com/SoMuchForSubtlety/golark"
+)
+
+type driver struct {
+	UID    string  `json:"uid"`
+	Name   string  `json:"name"`
+	Points float64 `json:"points"`
+	Team   struct {
+		Name string `json:"name"`
+	} `json:"team"`
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	err := srv.Seed("teams",
+		map[string]interface{}{"uid": "team_1", "name": "Mercedes", "country": "DE"},
+		map[string]interface{}{"uid": "team_2", "name": "Red Bull", "country": "AT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = srv.Seed("drivers",
+		map[string]interface{}{"uid": "drv_1", "name": "Hamilton", "points": 347, "team": "/teams/team_1/"},
+		map[string]interface{}{"uid": "drv_2", "name": "Bottas", "points": 223, "team": "/teams/team_1/"},
+		map[string]interface{}{"uid": "drv_3", "name": "Verstappen", "points": 214, "team": "/teams/team_2/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+	name, team := client.NewField("name"), client.NewField("team")
+	var res struct {
+		Objects []driver `json:"objects"`
+	}
+	err = c.NewRequest("drivers", "").
+		AddField(name).
+		AddField(client.NewField("points")).
+		AddField(team.WithSubField(client.NewField("name"))).
+		WithFilter("points", client.NewFilter(client.GreaterThan, "215")).
+		OrderBy(name).
+		Execute(&res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Objects) != 2 || res.Objects[0].Name != "Bottas" || res.Objects[1].Team.Name != "Mercedes" {
+		t.Fatal("incorrect drivers", res.Objects)
+	}
+	if res.Objects[0].UID != "" {
+		t.Error("field was not projected", res.Objects[0].UID)
+	}
+
+	page, info, err := client.NewCollection[map[string]interface{}](c, "drivers").Page(context.Background(), client.ListOptions{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || info.Count != 3 || info.HasNext() || !info.HasPrevious() {
+		t.Error("incorrect page", page, info)
+	}
+
+	var d driver
+	if err := c.NewRequest("drivers", "drv_3").Expand(client.NewField("team")).Execute(&d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "Verstappen" || d.Team.Name != "Red Bull" {
+		t.Error("incorrect driver", d)
+	}
+
+	if err := c.NewRequest("drivers", "drv_4").Execute(&d); !client.IsNotFound(err) {
+		t.Error("expected not found", err)
+	}
+	if err := srv.Seed("drivers", map[string]interface{}{"name": "Unknown"}); err == nil {
+		t.Error("expected an error for a fixture without a uid")
+	}
+}
+
+func TestExecutor(t *testing.T) {
+	e := &Executor{Respond: func(r *client.Request) (interface{}, error) {
+		return map[string]interface{}{"uid": r.ID, "name": "Hamilton"}, nil
+	}}
+	var exec client.Executor = e
+	var res driver
+	if err := exec.Execute(context.Background(), client.NewRequest("https://example.com/", "drivers", "drv_1"), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.UID != "drv_1" || res.Name != "Hamilton" {
+		t.Error("unexpected response", res)
+	}
+	if requests := e.Requests(); len(requests) != 1 || requests[0].Collection != "drivers" {
+		t.Error("request was not recorded", requests)
+	}
+}
+
+func TestExecutorNilContext(t *testing.T) {
+	e := &Executor{}
+	if err := e.Execute(nil, client.NewRequest("https://example.com/", "drivers", "drv_1"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Requests()) != 1 {
+		t.Error("request was not recorded")
+	}
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ErrTooManyObjects is wrapped by the error returned for collection requests that could return more objects than allowed.
+var ErrTooManyObjects = errors.New("query could return too many objects")
+
+// ObjectLimitError is returned for collection requests without a limit, or with a limit above the client's maximum.
+type ObjectLimitError struct {
+	Collection string
+	// Requested is the request's limit, or 0 if it has none.
+	Requested int
+	Max       int
+}
+
+func (e *ObjectLimitError) Error() string {
+	if e.Requested == 0 {
+		return fmt.Sprintf("%s: unbounded listing of %s, add a limit of at most %d and paginate", ErrTooManyObjects, e.Collection, e.Max)
+	}
+	return fmt.Sprintf("%s: limit %d for %s exceeds %d, paginate instead", ErrTooManyObjects, e.Requested, e.Collection, e.Max)
+}
+
+func (e *ObjectLimitError) Unwrap() error {
+	return ErrTooManyObjects
+}
+
+// WithObjectLimit rejects collection requests that could return more than max objects with an *ObjectLimitError.
+// If warn is set it is called with the error instead and the request is sent anyway.
+// Requests get a limit from WithPageSize if they don't set one.
+func WithObjectLimit(max int, warn func(r *Request, err *ObjectLimitError)) Option {
+	return func(s *settings) {
+		s.maxObjects = max
+		s.objectLimitWarn = warn
+	}
+}
+
+// checkObjectLimit enforces the object limit for the request's URL.
+func (s *settings) checkObjectLimit(r *Request, method string, u *url.URL) error {
+	err := s.objectLimitError(r, method, u)
+	if err == nil {
+		return nil
+	}
+	if s.objectLimitWarn != nil {
+		s.objectLimitWarn(r, err)
+		return nil
+	}
+	return err
+}
+
+// objectLimitError returns the error for requests exceeding the object limit, or nil.
+func (s *settings) objectLimitError(r *Request, method string, u *url.URL) *ObjectLimitError {
+	if s.maxObjects <= 0 || r.ID != "" || method != http.MethodGet {
+		return nil
+	}
+	limit, _ := strconv.Atoi(u.Query().Get("limit"))
+	if limit > 0 && limit <= s.maxObjects {
+		return nil
+	}
+	return &ObjectLimitError{Collection: r.Collection, Requested: limit, Max: s.maxObjects}
+}
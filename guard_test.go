@@ -0,0 +1,38 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestObjectLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"objects": []}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithObjectLimit(50, nil))
+	var limitErr *ObjectLimitError
+	if err := c.NewRequest("episodes", "").Execute(nil); !errors.As(err, &limitErr) || limitErr.Requested != 0 {
+		t.Error("expected unbounded listing error, got", err)
+	}
+	if err := c.NewRequest("episodes", "").WithParam("limit", 100).Execute(nil); !errors.Is(err, ErrTooManyObjects) {
+		t.Error("expected limit error, got", err)
+	}
+	if err := c.NewRequest("episodes", "").WithParam("limit", 50).Execute(nil); err != nil {
+		t.Error(err)
+	}
+	if err := c.NewRequest("episodes", "ep_1").Execute(nil); err != nil {
+		t.Error(err)
+	}
+
+	var warnings int
+	c = NewClient(WithBaseURL(server.URL), WithPageSize(100), WithObjectLimit(50, func(r *Request, err *ObjectLimitError) {
+		warnings++
+	}))
+	if err := c.NewRequest("episodes", "").Execute(nil); err != nil || warnings != 1 {
+		t.Error("expected warning, got", err, warnings)
+	}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrNoImageURL is returned for images without a URL, usually because the image_urls field was not expanded.
+var ErrNoImageURL = errors.New("image has no URL")
+
+// Image is an expanded Skylark image object, use it in structs for expanded image fields:
+//
+//	type Set struct {
+//		Images []client.Image `golark:"image_urls,expand"`
+//	}
+type Image struct {
+	UID   string `json:"uid"`
+	Self  string `json:"self"`
+	Title string `json:"title"`
+	// URL is the original image.
+	URL string `json:"url"`
+	// URLTemplate renders resized images, it can contain {width}, {height} and {format} placeholders.
+	URLTemplate string `json:"url_template"`
+	// Width and Height are the dimensions of the original image.
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"`
+	// Sizes are prerendered renditions of the image.
+	Sizes []ImageSize `json:"sizes"`
+}
+
+// ImageSize is a prerendered rendition of an image.
+type ImageSize struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"`
+}
+
+// DecodeImages decodes an image field of a raw object, like image_urls, holding a single expanded image or a list of them.
+// Unexpanded self URLs are decoded as images with only Self set.
+func DecodeImages(field json.RawMessage) ([]Image, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(field, &items); err != nil {
+		items = []json.RawMessage{field}
+	}
+	images := make([]Image, 0, len(items))
+	for _, item := range items {
+		var image Image
+		if len(item) > 0 && item[0] == '"' {
+			if err := json.Unmarshal(item, &image.Self); err != nil {
+				return nil, err
+			}
+		} else if string(item) == "null" {
+			continue
+		} else if err := json.Unmarshal(item, &image); err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// URLFor returns the URL of the image at the given width and format, zero and empty values keep the original ones.
+// Images with a URL template are rendered at exactly that width, keeping the aspect ratio.
+// Otherwise the smallest prerendered size at least as wide in the format is used, or the widest if none is wide enough,
+// and the original URL if there are no sizes in the format.
+func (i Image) URLFor(width int, format string) (string, error) {
+	if i.URLTemplate != "" {
+		return i.render(width, format), nil
+	}
+	var sizes []ImageSize
+	for _, size := range i.Sizes {
+		if format == "" || strings.EqualFold(size.Format, format) {
+			sizes = append(sizes, size)
+		}
+	}
+	if len(sizes) > 0 && width > 0 {
+		sort.Slice(sizes, func(a, b int) bool { return sizes[a].Width < sizes[b].Width })
+		for _, size := range sizes {
+			if size.Width >= width {
+				return size.URL, nil
+			}
+		}
+		return sizes[len(sizes)-1].URL, nil
+	}
+	if format != "" && i.Format != "" && !strings.EqualFold(i.Format, format) {
+		if len(sizes) > 0 {
+			return sizes[len(sizes)-1].URL, nil
+		}
+		return "", fmt.Errorf("image %s is not available as %s", i.UID, format)
+	}
+	if i.URL == "" {
+		return "", ErrNoImageURL
+	}
+	return i.URL, nil
+}
+
+// render fills in the URL template.
+func (i Image) render(width int, format string) string {
+	height := i.Height
+	if width <= 0 {
+		width = i.Width
+	} else if i.Width > 0 {
+		height = (i.Height*width + i.Width/2) / i.Width
+	}
+	if format == "" {
+		format = i.Format
+	}
+	return strings.NewReplacer(
+		"{width}", strconv.Itoa(width),
+		"{height}", strconv.Itoa(height),
+		"{format}", format,
+	).Replace(i.URLTemplate)
+}
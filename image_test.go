@@ -0,0 +1,68 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestImageURL(t *testing.T) {
+	var images []Image
+	err := json.Unmarshal([]byte(`[
+		{"uid": "img_1", "url": "https://cdn/img_1.jpg", "width": 1920, "height": 1080, "format": "jpg",
+		 "url_template": "https://cdn/img_1/{width}x{height}.{format}"},
+		{"uid": "img_2", "url": "https://cdn/img_2.jpg", "format": "jpg", "sizes": [
+			{"url": "https://cdn/img_2_1280.jpg", "width": 1280, "format": "jpg"},
+			{"url": "https://cdn/img_2_320.jpg", "width": 320, "format": "jpg"},
+			{"url": "https://cdn/img_2_640.webp", "width": 640, "format": "webp"}
+		]},
+		{"uid": "img_3", "self": "/api/images/img_3/"}
+	]`), &images)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		image  Image
+		width  int
+		format string
+		url    string
+	}{
+		{images[0], 640, "webp", "https://cdn/img_1/640x360.webp"},
+		{images[0], 0, "", "https://cdn/img_1/1920x1080.jpg"},
+		{images[1], 300, "", "https://cdn/img_2_320.jpg"},
+		{images[1], 1000, "jpg", "https://cdn/img_2_1280.jpg"},
+		{images[1], 4000, "jpg", "https://cdn/img_2_1280.jpg"},
+		{images[1], 0, "webp", "https://cdn/img_2_640.webp"},
+		{images[1], 0, "", "https://cdn/img_2.jpg"},
+	}
+	for _, tt := range tests {
+		url, err := tt.image.URLFor(tt.width, tt.format)
+		if err != nil || url != tt.url {
+			t.Errorf("%s at %d %s: expected %s, got %s %v", tt.image.UID, tt.width, tt.format, tt.url, url, err)
+		}
+	}
+
+	if _, err := images[2].URLFor(640, ""); !errors.Is(err, ErrNoImageURL) {
+		t.Error("expected ErrNoImageURL, got", err)
+	}
+	if _, err := images[1].URLFor(0, "png"); err == nil {
+		t.Error("expected error for unavailable format")
+	}
+}
+
+func TestDecodeImages(t *testing.T) {
+	var set map[string]json.RawMessage
+	json.Unmarshal([]byte(`{"image_urls": [{"uid": "img_1", "url": "a.jpg"}, "/api/images/img_2/"], "logo_url": {"uid": "img_3"}}`), &set)
+	images, err := DecodeImages(set["image_urls"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 2 || images[0].URL != "a.jpg" || images[1].Self != "/api/images/img_2/" {
+		t.Error("incorrect images", images)
+	}
+	images, err = DecodeImages(set["logo_url"])
+	if err != nil || len(images) != 1 || images[0].UID != "img_3" {
+		t.Error("incorrect image", images, err)
+	}
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// Concurrency is the number of objects written in parallel, each with its own request.
+	// Defaults to the client's bulk concurrency.
+	Concurrency int
+	// Interval is the minimum time between two requests, zero disables rate limiting.
+	Interval time.Duration
+	// DryRun reports what would change without writing anything.
+	DryRun bool
+}
+
+// ImportResult is the outcome of importing a single object.
+type ImportResult struct {
+	// Line is the line of the object in the input, starting at 1.
+	Line int
+	// Created is true if the object has no uid and is created, otherwise the object with its uid is patched.
+	Created bool
+	UID     string
+	// Changes lists the fields that would change, it is only set in dry run mode.
+	Changes []FieldChange
+	Err     error
+}
+
+// Import reads newline delimited JSON objects from rd and writes them to the collection.
+// Objects with a uid are patched, objects without one are created, one request per object.
+// The returned error is only set if the input can't be read, failed writes are reported in the results.
+func (c *Client) Import(ctx context.Context, collection string, opts ImportOptions, rd io.Reader) ([]ImportResult, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = c.current().bulkConcurrency
+	}
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	// requests after the first wait for the interval with the client's sleeper
+	s := c.current()
+	started := false
+
+	var results []ImportResult
+	var objects []map[string]interface{}
+	var pending []int
+	flush := func() {
+		var wg sync.WaitGroup
+		for i, object := range objects {
+			result := &results[pending[i]]
+			if opts.Interval > 0 && started {
+				if err := s.sleep(ctx, opts.Interval); err != nil {
+					result.Err = err
+					continue
+				}
+			}
+			started = true
+			if err := ctx.Err(); err != nil {
+				result.Err = err
+				continue
+			}
+			wg.Add(1)
+			go func(object map[string]interface{}) {
+				defer wg.Done()
+				c.importObject(ctx, collection, object, opts.DryRun, result)
+			}(object)
+		}
+		wg.Wait()
+		objects, pending = objects[:0], pending[:0]
+	}
+
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		result := ImportResult{Line: line}
+		var object map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &object); err != nil {
+			result.Err = fmt.Errorf("invalid JSON on line %d: %w", line, err)
+			results = append(results, result)
+			continue
+		}
+		uid, _ := object["uid"].(string)
+		result.UID, result.Created = uid, uid == ""
+		pending = append(pending, len(results))
+		results = append(results, result)
+		objects = append(objects, object)
+		if len(objects) == concurrency {
+			flush()
+		}
+	}
+	flush()
+	return results, scanner.Err()
+}
+
+func (c *Client) importObject(ctx context.Context, collection string, object map[string]interface{}, dryRun bool, result *ImportResult) {
+	body := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		body[key] = value
+	}
+	for _, key := range readOnlyFields {
+		delete(body, key)
+	}
+
+	if !dryRun {
+		if result.Created {
+			result.Err = c.send(c.NewRequest(collection, "").WithContext(ctx), http.MethodPost, body, nil)
+		} else {
+			result.Err = c.send(c.NewRequest(collection, result.UID).WithContext(ctx), http.MethodPatch, body, nil)
+		}
+		return
+	}
+
+	existing := json.RawMessage("{}")
+	if !result.Created {
+		r := c.NewRequest(collection, result.UID).WithContext(ctx)
+		for key := range body {
+			r.AddField(NewField(key))
+		}
+		if result.Err = r.Execute(&existing); result.Err != nil {
+			return
+		}
+	}
+	proposed, err := json.Marshal(body)
+	if err != nil {
+		result.Err = err
+		return
+	}
+	result.Changes, result.Err = Diff(existing, proposed)
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestImport(t *testing.T) {
+	var mu sync.Mutex
+	var writes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"title": "Old"}`))
+			return
+		}
+		mu.Lock()
+		writes = append(writes, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	input := `{"uid": "ep_1", "title": "New"}
+not json
+{"title": "Created"}
+`
+	c := NewClient(WithBaseURL(server.URL))
+
+	results, err := c.Import(context.Background(), "episodes", ImportOptions{DryRun: true}, strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || len(writes) != 0 {
+		t.Fatalf("incorrect dry run: %d results, %d writes", len(results), len(writes))
+	}
+	if len(results[0].Changes) != 1 || results[0].Changes[0].Old != "Old" || results[0].Changes[0].New != "New" {
+		t.Error("incorrect changes for update", results[0].Changes)
+	}
+	if results[1].Err == nil {
+		t.Error("expected error for invalid line")
+	}
+	if !results[2].Created || len(results[2].Changes) != 1 {
+		t.Error("incorrect result for create", results[2])
+	}
+
+	results, err = c.Import(context.Background(), "episodes", ImportOptions{Concurrency: 1}, strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"PATCH /episodes/ep_1/", "POST /episodes/"}
+	if len(writes) != 2 || writes[0] != expected[0] || writes[1] != expected[1] {
+		t.Errorf("incorrect writes\nexpected: %v\ngot:      %v", expected, writes)
+	}
+}
+
+func TestImportInterval(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	c := NewClient(WithBaseURL(server.URL), WithSleeper(clock))
+	input := strings.Repeat(`{"title": "Created"}`+"\n", 3)
+	results, err := c.Import(context.Background(), "episodes", ImportOptions{Concurrency: 2, Interval: time.Hour}, strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Error("unexpected error:", result.Err)
+		}
+	}
+	if len(clock.sleeps) != 2 || clock.sleeps[0] != time.Hour || clock.sleeps[1] != time.Hour {
+		t.Error("requests did not wait for the interval with the client's sleeper", clock.sleeps)
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"strconv"
+)
+
+// Attribute is a key value pair describing a span or measurement.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Span is a traced operation started by an Instrumentor.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// End finishes the span, err is the error the operation failed with or nil.
+	End(err error)
+}
+
+// Instrumentor records traces and metrics, implement it with an adapter for a tracing library like OpenTelemetry.
+// Implementations must be safe for concurrent use.
+type Instrumentor interface {
+	// StartSpan starts a span and returns a context carrying it, HTTP requests of the span are sent with that context.
+	StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+	AddCounter(name string, delta int64, attrs ...Attribute)
+	RecordHistogram(name string, value float64, attrs ...Attribute)
+}
+
+// Names of the metrics recorded with an Instrumentor.
+const (
+	// MetricRequests counts executed requests by collection, method and status code.
+	MetricRequests = "golark.requests"
+	// MetricErrors counts failed requests by collection, method and status code.
+	MetricErrors = "golark.errors"
+	// MetricDuration is a histogram of request durations in seconds, including retries.
+	MetricDuration = "golark.request.duration"
+)
+
+// WithInstrumentor creates a span for every executed request and records request metrics.
+func WithInstrumentor(i Instrumentor) Option {
+	return func(s *settings) {
+		s.instrumentor = i
+		if s.tagValues == nil {
+			s.tagValues = &tagValues{}
+		}
+	}
+}
+
+// instrument starts the span for executing a request, the returned function ends it.
+func (s *settings) instrument(ctx context.Context, r *Request, method string) (context.Context, func(status, attempts int, err error)) {
+	i := s.instrumentor
+	if i == nil {
+		return ctx, func(int, int, error) {}
+	}
+	attrs := []Attribute{{"golark.collection", r.Collection}, {"http.method", method}}
+	if r.ID != "" {
+		attrs = append(attrs, Attribute{"golark.id", r.ID})
+	}
+	tags := r.tagAttributes()
+	start := s.now()
+	ctx, span := i.StartSpan(ctx, "golark "+method+" "+r.Collection, append(attrs, tags...)...)
+	return ctx, func(status, attempts int, err error) {
+		metricAttrs := append(attrs[:2:2], Attribute{"http.status_code", strconv.Itoa(status)})
+		for n, tag := range tags {
+			if n == maxMetricTags {
+				break
+			}
+			metricAttrs = append(metricAttrs, s.tagValues.label(tag))
+		}
+		span.SetAttributes(Attribute{"http.status_code", strconv.Itoa(status)}, Attribute{"golark.attempts", strconv.Itoa(attempts)})
+		span.End(err)
+		i.AddCounter(MetricRequests, 1, metricAttrs...)
+		if err != nil {
+			i.AddCounter(MetricErrors, 1, metricAttrs...)
+		}
+		i.RecordHistogram(MetricDuration, s.now().Sub(start).Seconds(), metricAttrs...)
+	}
+}
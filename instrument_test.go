@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type spanKey struct{}
+
+type testSpan struct {
+	name  string
+	attrs map[string]string
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *testSpan) End(err error) {
+	s.err, s.ended = err, true
+}
+
+type testInstrumentor struct {
+	mu         sync.Mutex
+	spans      []*testSpan
+	counters   map[string]int64
+	histograms map[string]int
+}
+
+func (i *testInstrumentor) StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	span := &testSpan{name: name, attrs: make(map[string]string)}
+	span.SetAttributes(attrs...)
+	i.spans = append(i.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (i *testInstrumentor) AddCounter(name string, delta int64, attrs ...Attribute) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.counters[name] += delta
+}
+
+func (i *testInstrumentor) RecordHistogram(name string, value float64, attrs ...Attribute) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.histograms[name]++
+}
+
+func TestInstrumentor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sets/missing/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	inst := &testInstrumentor{counters: make(map[string]int64), histograms: make(map[string]int)}
+	c := NewClient(WithBaseURL(server.URL), WithInstrumentor(inst))
+	var propagated bool
+	c.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			propagated = req.Context().Value(spanKey{}) != nil
+			return next(req)
+		}
+	})
+
+	if err := c.Do(c.NewRequest("sets", "set_1"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Do(c.NewRequest("sets", "missing"), nil); !IsNotFound(err) {
+		t.Fatal("expected not found", err)
+	}
+
+	if !propagated {
+		t.Error("span context was not propagated")
+	}
+	if len(inst.spans) != 2 {
+		t.Fatal("incorrect number of spans", len(inst.spans))
+	}
+	span := inst.spans[0]
+	if !span.ended || span.err != nil || span.attrs["golark.collection"] != "sets" || span.attrs["golark.id"] != "set_1" || span.attrs["http.status_code"] != "200" {
+		t.Error("incorrect span", span.name, span.attrs, span.err)
+	}
+	if inst.spans[1].err == nil || inst.spans[1].attrs["http.status_code"] != "404" {
+		t.Error("incorrect span for failed request", inst.spans[1].attrs)
+	}
+	if inst.counters[MetricRequests] != 2 || inst.counters[MetricErrors] != 1 || inst.histograms[MetricDuration] != 2 {
+		t.Error("incorrect metrics", inst.counters, inst.histograms)
+	}
+}
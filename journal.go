@@ -0,0 +1,161 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// Journal is an append-only file recording which objects and pages of a bulk job were processed,
+// so a job can resume after a crash without reprocessing objects. Unlike resume tokens it also records
+// objects of pages that were only partly processed. It is safe for concurrent use.
+type Journal struct {
+	mu   sync.Mutex
+	f    *os.File
+	done map[string]bool
+	// offset is the offset after the last completely processed page.
+	offset int
+}
+
+// journalEntry is a line of the journal, either an object or a completed page.
+type journalEntry struct {
+	Object string `json:"object,omitempty"`
+	// Next is the offset after a completed page.
+	Next *int `json:"next,omitempty"`
+}
+
+// OpenJournal opens the journal at path, creating it if necessary, and reads the progress it recorded.
+// A truncated last line, as left by a crash while writing it, is ignored and removed
+// so the next entry starts on a new line.
+func OpenJournal(path string) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	j := &Journal{f: f, done: make(map[string]bool)}
+	reader := bufio.NewReader(f)
+	// complete is the size of the complete lines read so far
+	var complete int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				if err := f.Truncate(complete); err != nil {
+					f.Close()
+					return nil, err
+				}
+			}
+			break
+		}
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		complete += int64(len(line))
+		var entry journalEntry
+		if json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		if entry.Object != "" {
+			j.done[entry.Object] = true
+		}
+		if entry.Next != nil {
+			j.offset = *entry.Next
+		}
+	}
+	return j, nil
+}
+
+// Done reports whether the object with the uid was processed.
+func (j *Journal) Done(uid string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.done[uid]
+}
+
+// Offset returns the offset after the last completely processed page, where a resumed job starts.
+func (j *Journal) Offset() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.offset
+}
+
+// MarkObject records that the object with the uid was processed.
+func (j *Journal) MarkObject(uid string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.append(journalEntry{Object: uid}); err != nil {
+		return err
+	}
+	j.done[uid] = true
+	return nil
+}
+
+// MarkPage records that all objects before the offset were processed and syncs the journal to disk.
+func (j *Journal) MarkPage(next int) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.append(journalEntry{Next: &next}); err != nil {
+		return err
+	}
+	j.offset = next
+	return j.f.Sync()
+}
+
+// append writes the entry, it must be called with mu held.
+func (j *Journal) append(entry journalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = j.f.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	return j.f.Close()
+}
+
+// ExecuteJournaled calls fn with every object of every page of the collection request, recording the progress in the journal.
+// It starts at the journal's offset and skips objects the journal recorded as processed, so calling it again with
+// the same journal after a crash or error continues where it stopped. Objects are identified by their uid,
+// objects without one are only skipped as part of completed pages.
+func (r *Request) ExecuteJournaled(j *Journal, fn func(object json.RawMessage) error) error {
+	limit, _ := strconv.Atoi(r.additionalFields["limit"])
+	offset := j.Offset()
+	if offset == 0 {
+		offset, _ = strconv.Atoi(r.additionalFields["offset"])
+	}
+	progress := newProgressTracker(r.progress, r.executor().current().now)
+	err := r.executor().eachPage(r, limit, offset, progress, func(objects []json.RawMessage) error {
+		for _, object := range objects {
+			var meta struct {
+				UID string `json:"uid"`
+			}
+			json.Unmarshal(object, &meta)
+			if meta.UID != "" && j.Done(meta.UID) || r.dedupe != nil && r.dedupe.Seen(meta.UID) {
+				continue
+			}
+			if err := fn(object); err != nil {
+				return err
+			}
+			if meta.UID != "" {
+				if err := j.MarkObject(meta.UID); err != nil {
+					return err
+				}
+			}
+		}
+		offset += len(objects)
+		progress.page(len(objects))
+		return j.MarkPage(offset)
+	})
+	if err != nil {
+		return err
+	}
+	progress.done()
+	return nil
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestJournal(t *testing.T) {
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		var objects []string
+		for i := offset; i < offset+2 && i < 5; i++ {
+			objects = append(objects, fmt.Sprintf(`{"uid": "ep_%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"objects": [%s]}`, strings.Join(objects, ","))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "job.journal")
+	c := NewClient(WithBaseURL(server.URL))
+	var processed []string
+	crash := errors.New("crash")
+	crashed := false
+	process := func(object json.RawMessage) error {
+		var meta struct{ UID string }
+		json.Unmarshal(object, &meta)
+		if meta.UID == "ep_3" && !crashed {
+			crashed = true
+			return crash
+		}
+		processed = append(processed, meta.UID)
+		return nil
+	}
+
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.NewRequest("episodes", "").Limit(2).ExecuteJournaled(j, process); !errors.Is(err, crash) {
+		t.Fatal("expected crash, got", err)
+	}
+	j.Close()
+
+	// simulate a crash while writing the last line
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	f.Write([]byte(`{"obj`))
+	f.Close()
+
+	offsets = nil
+	j, err = OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	if j.Offset() != 2 || !j.Done("ep_2") || j.Done("ep_3") {
+		t.Error("incorrect journal state", j.Offset(), j.done)
+	}
+	if err := c.NewRequest("episodes", "").Limit(2).ExecuteJournaled(j, process); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(processed) != "[ep_0 ep_1 ep_2 ep_3 ep_4]" {
+		t.Error("objects were skipped or processed twice", processed)
+	}
+	if offsets[0] != "2" {
+		t.Error("resumed job did not start at the journal's offset", offsets)
+	}
+}
+
+func TestJournalPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "job.journal")
+	if err := os.WriteFile(path, []byte(`{"object": "ep_0"}`+"\n"+`{"obj`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.MarkObject("ep_1"); err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+
+	j, err = OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	if !j.Done("ep_0") || !j.Done("ep_1") {
+		t.Error("entry appended after a partial line was lost", j.done)
+	}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger receives log messages with alternating keys and values.
+type Logger interface {
+	Log(msg string, keyvals ...interface{})
+}
+
+// LoggerFunc adapts a function to the Logger interface.
+type LoggerFunc func(msg string, keyvals ...interface{})
+
+// Log implements Logger.
+func (f LoggerFunc) Log(msg string, keyvals ...interface{}) {
+	f(msg, keyvals...)
+}
+
+// StdLogger adapts a standard library logger, key value pairs are written as key=value.
+func StdLogger(l *log.Logger) Logger {
+	return LoggerFunc(func(msg string, keyvals ...interface{}) {
+		var b strings.Builder
+		b.WriteString(msg)
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			fmt.Fprintf(&b, " %v=%v", keyvals[i], keyvals[i+1])
+		}
+		l.Print(b.String())
+	})
+}
+
+// WithLogger sets the logger used for requests whose context has no logger.
+func WithLogger(l Logger) Option {
+	return func(s *settings) {
+		s.logger = l
+	}
+}
+
+type loggerKey struct{}
+
+// ContextWithLogger returns a context whose logger is used for everything logged while executing requests with it.
+func ContextWithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+type metadataKey struct{}
+
+// ContextWithMetadata returns a context carrying the key value pairs in addition to the parent's metadata.
+// Metadata is added to all logs and audit records of requests executed with the context.
+func ContextWithMetadata(ctx context.Context, keyvals ...string) context.Context {
+	parent := MetadataFromContext(ctx)
+	metadata := make(map[string]string, len(parent)+len(keyvals)/2)
+	for key, value := range parent {
+		metadata[key] = value
+	}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		metadata[keyvals[i]] = keyvals[i+1]
+	}
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// MetadataFromContext returns the metadata set with ContextWithMetadata, it must not be modified.
+func MetadataFromContext(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return metadata
+}
+
+// log writes a message to the context's logger, or the client's logger if the context has none,
+// adding the context's metadata.
+func (s *settings) log(ctx context.Context, msg string, keyvals ...interface{}) {
+	l, ok := ctx.Value(loggerKey{}).(Logger)
+	if !ok {
+		l = s.logger
+	}
+	if l == nil {
+		return
+	}
+	for key, value := range MetadataFromContext(ctx) {
+		keyvals = append(keyvals, key, value)
+	}
+	l.Log(msg, keyvals...)
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContextLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var messages []string
+	var metadata map[string]interface{}
+	logger := LoggerFunc(func(msg string, keyvals ...interface{}) {
+		messages = append(messages, msg)
+		metadata = make(map[string]interface{})
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			metadata[keyvals[i].(string)] = keyvals[i+1]
+		}
+	})
+
+	c := NewClient(WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 2}))
+	ctx := ContextWithMetadata(ContextWithLogger(context.Background(), logger), "request_id", "req_123")
+	if err := c.NewRequest("sets", "").WithContext(ctx).Execute(&struct{}{}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if len(messages) != 2 || messages[0] != "retrying request" || messages[1] != "request failed" {
+		t.Error("incorrect log messages", messages)
+	}
+	if metadata["request_id"] != "req_123" || metadata["attempt"] != 2 {
+		t.Error("incorrect log metadata", metadata)
+	}
+}
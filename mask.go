@@ -0,0 +1,127 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MaskedValue replaces sensitive values in errors and logs.
+const MaskedValue = "***"
+
+// defaultMaskedHeaders are always masked.
+var defaultMaskedHeaders = []string{"Authorization", "Skylark-Api-Key"}
+
+// WithMaskedParams sets query parameters whose values are masked in errors and logs.
+func WithMaskedParams(names ...string) Option {
+	return func(s *settings) {
+		s.maskedParams = append(s.maskedParams, names...)
+	}
+}
+
+// WithMaskedHeaders sets headers whose values are masked in errors and logs.
+// The Authorization and Skylark-Api-Key headers are always masked.
+func WithMaskedHeaders(names ...string) Option {
+	return func(s *settings) {
+		s.maskedHeaders = append(s.maskedHeaders, names...)
+	}
+}
+
+// maskURL returns the URL with the values of masked query parameters replaced.
+func (s *settings) maskURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	if len(s.maskedParams) == 0 || u.RawQuery == "" {
+		return u.String()
+	}
+	masked := *u
+	q := masked.Query()
+	for _, name := range s.maskedParams {
+		if _, ok := q[name]; ok {
+			q.Set(name, MaskedValue)
+		}
+	}
+	masked.RawQuery = q.Encode()
+	return masked.String()
+}
+
+// sensitiveValues returns the values of masked parameters, masked headers and secret headers of the request.
+func (s *settings) sensitiveValues(req *http.Request) []string {
+	var values []string
+	q := req.URL.Query()
+	for _, name := range s.maskedParams {
+		values = append(values, q[name]...)
+	}
+	headers := append(append([]string(nil), defaultMaskedHeaders...), s.maskedHeaders...)
+	for _, h := range s.secretHeaders {
+		headers = append(headers, h.key)
+	}
+	for _, name := range headers {
+		for _, value := range req.Header[http.CanonicalHeaderKey(name)] {
+			values = append(values, value)
+			// also mask the credentials of "Bearer <token>" style values on their own
+			if i := strings.IndexByte(value, ' '); i >= 0 {
+				values = append(values, value[i+1:])
+			}
+		}
+	}
+	return values
+}
+
+// maskedError hides sensitive values in the message of the wrapped error.
+type maskedError struct {
+	err     error
+	message string
+}
+
+func (e *maskedError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the original error, which may contain sensitive values.
+func (e *maskedError) Unwrap() error {
+	return e.err
+}
+
+// mask replaces all sensitive values in the error's message.
+func mask(err error, values []string) error {
+	if err == nil {
+		return nil
+	}
+	message := err.Error()
+	masked := maskString(message, values)
+	if masked == message {
+		return err
+	}
+	return &maskedError{err: err, message: masked}
+}
+
+// maskString replaces all sensitive values in s, also if they are query escaped.
+func maskString(s string, values []string) string {
+	for _, value := range values {
+		if len(value) < 4 {
+			continue
+		}
+		s = strings.ReplaceAll(s, value, MaskedValue)
+		s = strings.ReplaceAll(s, url.QueryEscape(value), MaskedValue)
+	}
+	return s
+}
+
+// maskValue replaces all sensitive values in the strings of a decoded JSON value.
+func maskValue(v interface{}, values []string) interface{} {
+	switch v := v.(type) {
+	case string:
+		return maskString(v, values)
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = maskValue(value, values)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = maskValue(value, values)
+		}
+	}
+	return v
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaskedErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("invalid key " + r.URL.Query().Get("api_key") + " for token " + r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	var audit bytes.Buffer
+	c := NewClient(
+		WithBaseURL(server.URL),
+		WithHeader("Authorization", "Bearer token123"),
+		WithMaskedParams("api_key"),
+		WithAuditSink(NewJSONAuditSink(&audit)))
+
+	err := c.NewRequest("sets", "").WithFilter("api_key", NewFilter(Equals, "key123")).Execute(&struct{}{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, secret := range []string{"key123", "token123"} {
+		if strings.Contains(err.Error(), secret) {
+			t.Errorf("error contains %s: %v", secret, err)
+		}
+		if strings.Contains(audit.String(), secret) {
+			t.Errorf("audit record contains %s: %s", secret, audit.String())
+		}
+	}
+	if !strings.Contains(err.Error(), MaskedValue) {
+		t.Error("error should contain masked values:", err)
+	}
+}
+
+func TestMaskedAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"detail": "invalid key %s", "secret": %q, "password": "hunter22"}`,
+			r.URL.Query().Get("api_key"), r.Header.Get("X-Secret"))
+	}))
+	defer server.Close()
+
+	c := NewClient(
+		WithBaseURL(server.URL),
+		WithMaskedParams("api_key"),
+		WithRedactedFields("password"),
+		WithSecretHeader("X-Secret", "", &countingSecrets{}, "name"))
+	err := c.NewRequest("sets", "").WithFilter("api_key", NewFilter(Equals, "key123")).Execute(&struct{}{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("expected APIError, got", err)
+	}
+	for _, secret := range []string{"key123", "secret-name", "hunter22"} {
+		if strings.Contains(apiErr.Message, secret) {
+			t.Errorf("message contains %s: %s", secret, apiErr.Message)
+		}
+		if strings.Contains(fmt.Sprint(apiErr.Payload), secret) {
+			t.Errorf("payload contains %s: %v", secret, apiErr.Payload)
+		}
+	}
+}
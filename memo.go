@@ -0,0 +1,61 @@
+package client
+
+import (
+	"net/url"
+	"sync"
+	"sync/atomic"
+)
+
+// fieldGeneration counts the changes made with the methods of any Field,
+// so memoized URLs notice changes of fields that were already added to a request without encoding them.
+var fieldGeneration uint64
+
+// fieldChanged records a change of a field.
+func fieldChanged() {
+	atomic.AddUint64(&fieldGeneration, 1)
+}
+
+// urlMemo caches the URL computed by ToURL.
+// Builder methods invalidate it, the key catches direct changes of the request's exported fields
+// and changes of fields that were already added.
+type urlMemo struct {
+	mu  sync.Mutex
+	key urlMemoKey
+	u   *url.URL
+}
+
+type urlMemoKey struct {
+	endpoint, collection, id, path string
+	fields                         int
+	generation                     uint64
+}
+
+func (r *Request) memoKey() urlMemoKey {
+	return urlMemoKey{endpoint: r.Endpoint, collection: r.Collection, id: r.ID, path: r.path,
+		fields: len(r.Fields), generation: atomic.LoadUint64(&fieldGeneration)}
+}
+
+// invalidate drops the memoized URL after the request was changed.
+func (r *Request) invalidate() {
+	if r.memo == nil {
+		return
+	}
+	r.memo.mu.Lock()
+	r.memo.u = nil
+	r.memo.mu.Unlock()
+}
+
+// memoized returns a copy of the memoized URL, computing it with build if necessary.
+func (m *urlMemo) memoized(key urlMemoKey, build func() (*url.URL, error)) (*url.URL, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.u == nil || m.key != key {
+		u, err := build()
+		if err != nil {
+			return nil, err
+		}
+		m.u, m.key = u, key
+	}
+	u := *m.u
+	return &u, nil
+}
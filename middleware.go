@@ -0,0 +1,51 @@
+package client
+
+import "net/http"
+
+// RoundTripFunc sends an HTTP request and returns its response.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// Middleware wraps the function sending requests, it can modify requests and responses,
+// for example to log calls or add correlation IDs.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware adds middlewares, see Client.Use.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(s *settings) {
+		s.middleware = append(s.middleware, mw...)
+	}
+}
+
+// Use adds middlewares to the client. They are applied in registration order,
+// the first one sees requests first and responses last.
+// Middlewares run for every attempt, after headers and authentication were set.
+// Like UpdateConfig it returns the error of an invalid configuration, the middlewares are not added then.
+func (c *Client) Use(mw ...Middleware) error {
+	return c.UpdateConfig(WithMiddleware(mw...))
+}
+
+// roundTrip sends the request through the client's middlewares, cache and rate limiter.
+func (s *settings) roundTrip(req *http.Request) (*http.Response, error) {
+	rt := RoundTripFunc(s.httpClient.Do)
+	if s.redirect != nil {
+		hc := *s.httpClient
+		hc.CheckRedirect = s.checkRedirect
+		rt = hc.Do
+	}
+	if s.headerAllowlist != nil {
+		rt = s.allowlisted(rt)
+	}
+	if s.stats != nil {
+		rt = s.counted(rt)
+	}
+	if s.limiter != nil {
+		rt = s.limited(rt)
+	}
+	if s.cache != nil {
+		rt = s.cached(rt)
+	}
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		rt = s.middleware[i](rt)
+	}
+	return rt(req)
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Correlation-ID", r.Header.Get("X-Correlation-ID"))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+" request")
+				res, err := next(req)
+				order = append(order, name+" response")
+				return res, err
+			}
+		}
+	}
+	var correlation string
+	c := NewClient(WithBaseURL(server.URL))
+	c.Use(trace("first"), func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Correlation-ID", "abc")
+			res, err := next(req)
+			if err == nil {
+				correlation = res.Header.Get("X-Correlation-ID")
+			}
+			return res, err
+		}
+	})
+	c.Use(trace("second"))
+
+	if err := c.Do(c.NewRequest("sets", "set_1"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if correlation != "abc" {
+		t.Error("request was not modified", correlation)
+	}
+	expected := "first request,second request,second response,first response"
+	if got := strings.Join(order, ","); got != expected {
+		t.Errorf("incorrect order\nexpected: %s\ngot:      %s", expected, got)
+	}
+}
+
+func TestUseInvalidConfig(t *testing.T) {
+	hc := &http.Client{Transport: roundTripperFunc(nil)}
+	c := NewClient(WithHTTPClient(hc), WithTLSMinVersion(0))
+	if err := c.Use(func(next RoundTripFunc) RoundTripFunc { return next }); err == nil {
+		t.Error("expected the configuration error")
+	}
+	if len(c.current().middleware) != 0 {
+		t.Error("middleware was added to an invalid configuration")
+	}
+}
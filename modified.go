@@ -0,0 +1,29 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrNotModified matches the *APIError of a 304 Not Modified response, use errors.Is or IsNotModified.
+var ErrNotModified = errors.New("not modified")
+
+// IfModifiedSince sends the request with an If-Modified-Since header. If the object did not change since t
+// the server responds with 304 Not Modified, the value passed to Execute is left untouched and the error matches ErrNotModified.
+// ExecuteResult also sets Result.NotModified. Stats, logs, audit records and metrics count such responses as successful
+// requests. This works without a cache, see WithCache for ETag based caching.
+func (r *Request) IfModifiedSince(t time.Time) *Request {
+	r.ifModifiedSince = t
+	return r
+}
+
+// IsNotModified reports whether err is a 304 Not Modified response.
+func IsNotModified(err error) bool {
+	return hasStatus(err, http.StatusNotModified)
+}
+
+// Is makes errors.Is(err, ErrNotModified) report 304 responses.
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotModified && e.StatusCode == http.StatusNotModified
+}
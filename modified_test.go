@@ -0,0 +1,48 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIfModifiedSince(t *testing.T) {
+	modified := time.Date(2021, 3, 28, 15, 0, 0, 0, time.UTC)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+		if err == nil && !modified.After(since) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"uid": "ep_1"}`))
+	}))
+	defer server.Close()
+
+	var logged []string
+	c := NewClient(WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 3}),
+		WithLogger(LoggerFunc(func(msg string, keyvals ...interface{}) { logged = append(logged, msg) })))
+	var v struct{ UID string }
+	res, err := c.NewRequest("episodes", "ep_1").IfModifiedSince(modified.Add(time.Hour).In(time.Local)).ExecuteResult(&v)
+	if !errors.Is(err, ErrNotModified) || !IsNotModified(err) || !res.NotModified {
+		t.Fatal("expected not modified, got", err, res)
+	}
+	if v.UID != "" || requests != 1 {
+		t.Error("not modified response was decoded or retried", v, requests)
+	}
+	// a not modified response is a successful request
+	if stats := c.Stats(); len(stats.Errors) != 0 || stats.Requests["episodes"] != 1 {
+		t.Error("not modified response was counted as an error", stats)
+	}
+	if len(logged) != 0 {
+		t.Error("not modified response was logged as a failure", logged)
+	}
+
+	res, err = c.NewRequest("episodes", "ep_1").IfModifiedSince(modified.Add(-time.Hour)).ExecuteResult(&v)
+	if err != nil || res.NotModified || v.UID != "ep_1" {
+		t.Error("expected modified object, got", err, res, v)
+	}
+}
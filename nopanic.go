@@ -0,0 +1,46 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrPanic wraps panics that were recovered during the execution of a request, see WithoutPanics.
+	ErrPanic = errors.New("recovered panic")
+	// ErrNilContext is returned when executing a request that was given a nil context, see WithoutPanics.
+	ErrNilContext = errors.New("nil context")
+	// ErrNilClient is returned by ReplaceDefaultClient for a nil client.
+	ErrNilClient = errors.New("nil client")
+)
+
+// WithoutPanics guarantees that the client returns errors where it would otherwise panic.
+// Requests given a nil context fail with ErrNilContext, and panics in hooks, middlewares, decoders
+// and post processors that run while a request is executed are recovered and returned as errors wrapping ErrPanic.
+// Package level functions meant to be called during initialization have variants returning errors,
+// see DeclareComparator and ReplaceDefaultClient.
+func WithoutPanics() Option {
+	return func(s *settings) {
+		s.noPanics = true
+	}
+}
+
+// recoverPanic turns a panic into an error stored in err if the client was created WithoutPanics.
+// It must be deferred directly.
+func (c *Client) recoverPanic(err *error) {
+	if !c.current().noPanics {
+		return
+	}
+	if p := recover(); p != nil {
+		*err = fmt.Errorf("%w: %v", ErrPanic, p)
+	}
+}
+
+// ReplaceDefaultClient replaces the default client like SetDefaultClient, it returns ErrNilClient instead of panicking.
+func ReplaceDefaultClient(c *Client) error {
+	if c == nil {
+		return ErrNilClient
+	}
+	defaultClient.Store(c)
+	return nil
+}
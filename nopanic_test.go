@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithoutPanics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"uid":"ep_1"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithoutPanics())
+	if err := c.NewRequest("episodes", "").WithContext(nil).Execute(nil); !errors.Is(err, ErrNilContext) {
+		t.Error("expected ErrNilContext, got", err)
+	}
+
+	err := c.NewRequest("episodes", "").Finalize(func(*http.Request) error { panic("boom") }).Execute(nil)
+	if !errors.Is(err, ErrPanic) {
+		t.Error("expected ErrPanic, got", err)
+	}
+	err = c.NewRequest("episodes", "ep_1").Patch(map[string]string{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := c.NewRequest("episodes", "ep_1").Watch(context.Background(), 0)
+	if event := <-events; event.Err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+	if _, ok := <-events; ok {
+		t.Error("expected the watch to stop")
+	}
+
+	// split parts and streams run outside Do
+	boom := func(next RoundTripFunc) RoundTripFunc {
+		return func(*http.Request) (*http.Response, error) { panic("boom") }
+	}
+	c = NewClient(WithBaseURL(server.URL), WithMaxURLLength(200), WithoutPanics(), WithMiddleware(boom))
+	uids := make([]string, 100)
+	for i := range uids {
+		uids[i] = fmt.Sprintf("ep_%03d", i)
+	}
+	err = c.NewRequest("episodes", "").WithFilter("uid", NewFilterValue(In, uids)).Execute(&struct{}{})
+	if !errors.Is(err, ErrPanic) {
+		t.Error("expected ErrPanic from split request, got", err)
+	}
+	err = c.NewRequest("episodes", "").ExecuteStream(func(json.RawMessage) error { return nil })
+	if !errors.Is(err, ErrPanic) {
+		t.Error("expected ErrPanic from stream, got", err)
+	}
+	err = c.NewRequest("images", "").Upload(context.Background(), "file", "a.txt", strings.NewReader("a"), nil)
+	if !errors.Is(err, ErrPanic) {
+		t.Error("expected ErrPanic from upload, got", err)
+	}
+
+	if err := ReplaceDefaultClient(nil); !errors.Is(err, ErrNilClient) {
+		t.Error("expected ErrNilClient, got", err)
+	}
+	if _, err := DeclareComparator("Invalid", nil); err == nil {
+		t.Error("expected an error for an invalid comparator name")
+	}
+}
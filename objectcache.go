@@ -0,0 +1,328 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ObjectCache is an LRU cache of objects keyed by collection and uid.
+// It is safe for concurrent use.
+type ObjectCache struct {
+	size  int
+	ttl   time.Duration
+	clock Clock
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cachedObject struct {
+	key     string
+	object  json.RawMessage
+	expires time.Time
+}
+
+// NewObjectCache creates a cache holding up to size objects for ttl each, a ttl of zero never expires objects.
+func NewObjectCache(size int, ttl time.Duration) *ObjectCache {
+	return &ObjectCache{size: size, ttl: ttl, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// SetClock sets the clock used to expire objects, it defaults to the system clock.
+func (c *ObjectCache) SetClock(clock Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+func (c *ObjectCache) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// WithObjectCache sets the cache consulted by GetByUID and Hydrate.
+// Successful writes through the client evict the written object.
+func WithObjectCache(cache *ObjectCache) Option {
+	return func(s *settings) {
+		s.objectCache = cache
+	}
+}
+
+func objectKey(collection, uid string) string {
+	return collection + "/" + uid
+}
+
+// Get returns the cached object.
+func (c *ObjectCache) Get(collection, uid string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[objectKey(collection, uid)]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*cachedObject)
+	if c.ttl > 0 && c.now().After(entry.expires) {
+		c.order.Remove(e)
+		delete(c.entries, entry.key)
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return entry.object, true
+}
+
+// Add stores the object, evicting the least recently used object if the cache is full.
+func (c *ObjectCache) Add(collection, uid string, object json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := objectKey(collection, uid)
+	entry := &cachedObject{key: key, object: object, expires: c.now().Add(c.ttl)}
+	if e, ok := c.entries[key]; ok {
+		e.Value = entry
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.size > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedObject).key)
+	}
+}
+
+// Remove evicts the object.
+func (c *ObjectCache) Remove(collection, uid string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := objectKey(collection, uid)
+	if e, ok := c.entries[key]; ok {
+		c.order.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+// Purge evicts all objects.
+func (c *ObjectCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+// Len returns the number of cached objects, including expired ones that were not evicted yet.
+func (c *ObjectCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// GetByUID fetches the object with all its fields and writes it to the value pointed to by v,
+// serving it from the client's object cache if possible.
+func (c *Client) GetByUID(ctx context.Context, collection, uid string, v interface{}) error {
+	object, err := c.getObject(ctx, collection, uid)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(object, v)
+}
+
+func (c *Client) getObject(ctx context.Context, collection, uid string) (json.RawMessage, error) {
+	cache := c.current().objectCache
+	if cache != nil {
+		if object, ok := cache.Get(collection, uid); ok {
+			return object, nil
+		}
+	}
+	var object json.RawMessage
+	if err := c.NewRequest(collection, uid).WithContext(ctx).Execute(&object); err != nil {
+		return nil, err
+	}
+	if cache != nil {
+		cache.Add(collection, uid, object)
+	}
+	return object, nil
+}
+
+// HydrateOptions limits how far Hydrate follows references.
+type HydrateOptions struct {
+	// MaxDepth is the number of levels of references that are resolved. At level 2 the fields of the referenced objects
+	// are hydrated as well, and so on. Zero resolves only the references of the given object.
+	MaxDepth int
+	// MaxFetches is the number of objects that may be fetched, zero means no limit.
+	// Hydration fails with an error wrapping ErrFetchBudget if more are needed.
+	MaxFetches int
+	// Concurrency is the number of references of an object fetched in parallel,
+	// zero uses the client's bulk concurrency, see WithBulkConcurrency.
+	Concurrency int
+}
+
+// ErrFetchBudget is returned if hydrating an object needs more fetches than HydrateOptions.MaxFetches.
+var ErrFetchBudget = errors.New("hydration fetch budget exceeded")
+
+// Hydrate replaces the self URLs in the given reference fields of the object with the objects they refer to.
+// Fields can hold a single self URL or a list of them. Referenced objects are fetched with GetByUID.
+func (c *Client) Hydrate(ctx context.Context, object json.RawMessage, fields ...string) (json.RawMessage, error) {
+	return c.HydrateWith(ctx, object, HydrateOptions{}, fields...)
+}
+
+// HydrateWith hydrates the object like Hydrate, following references up to the depth set in the options.
+// The references of each object are fetched in parallel and every object is fetched at most once. References back to an object that is being hydrated, like a parent
+// referring to its child, are left as self URLs so cycles end.
+func (c *Client) HydrateWith(ctx context.Context, object json.RawMessage, opts HydrateOptions, fields ...string) (json.RawMessage, error) {
+	return c.newHydration(ctx, opts, fields).hydrate(object, 1)
+}
+
+// newHydration returns the state of hydrating the fields with the options, applying their defaults.
+func (c *Client) newHydration(ctx context.Context, opts HydrateOptions, fields []string) *hydration {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 1
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = c.current().bulkConcurrency
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultBulkConcurrency
+	}
+	return &hydration{c: c, ctx: ctx, opts: opts, fields: fields, fetched: make(map[string]json.RawMessage), path: make(map[string]bool)}
+}
+
+// hydration is the state of a single HydrateWith call.
+type hydration struct {
+	c      *Client
+	ctx    context.Context
+	opts   HydrateOptions
+	fields []string
+
+	// mu guards fetched, which is written by parallel fetches.
+	mu      sync.Mutex
+	fetched map[string]json.RawMessage
+	// path holds the self URLs of the objects being hydrated.
+	path map[string]bool
+}
+
+func (h *hydration) hydrate(object json.RawMessage, depth int) (json.RawMessage, error) {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(object, &decoded); err != nil {
+		return nil, err
+	}
+	if self, ok := decoded["self"].(string); ok && self != "" {
+		h.path[self] = true
+		defer delete(h.path, self)
+	}
+	var refs []string
+	for _, field := range h.fields {
+		switch ref := decoded[field].(type) {
+		case string:
+			refs = append(refs, ref)
+		case []interface{}:
+			for _, item := range ref {
+				if self, ok := item.(string); ok {
+					refs = append(refs, self)
+				}
+			}
+		}
+	}
+	if err := h.fetch(refs); err != nil {
+		return nil, err
+	}
+	for _, field := range h.fields {
+		switch ref := decoded[field].(type) {
+		case string:
+			resolved, err := h.resolve(ref, depth)
+			if err != nil {
+				return nil, err
+			}
+			decoded[field] = resolved
+		case []interface{}:
+			for i, item := range ref {
+				self, ok := item.(string)
+				if !ok {
+					continue
+				}
+				resolved, err := h.resolve(self, depth)
+				if err != nil {
+					return nil, err
+				}
+				ref[i] = resolved
+			}
+		}
+	}
+	return json.Marshal(decoded)
+}
+
+// resolve returns the object the self URL refers to, hydrated if depth allows it,
+// or the self URL itself if the object is being hydrated.
+func (h *hydration) resolve(self string, depth int) (interface{}, error) {
+	if h.path[self] {
+		return self, nil
+	}
+	h.mu.Lock()
+	object := h.fetched[self]
+	h.mu.Unlock()
+	if depth < h.opts.MaxDepth {
+		var err error
+		if object, err = h.hydrate(object, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return object, nil
+}
+
+// fetch fetches the referenced objects that weren't fetched yet in parallel.
+// References to objects being hydrated are skipped.
+func (h *hydration) fetch(refs []string) error {
+	h.mu.Lock()
+	var missing []string
+	for _, self := range refs {
+		if _, ok := h.fetched[self]; ok || h.path[self] {
+			continue
+		}
+		// fetched holds every reference that was fetched or reserved
+		if h.opts.MaxFetches > 0 && len(h.fetched) >= h.opts.MaxFetches {
+			h.mu.Unlock()
+			return fmt.Errorf("%w: resolving %s after %d fetches", ErrFetchBudget, self, len(h.fetched))
+		}
+		// reserve the reference so duplicates are fetched once
+		h.fetched[self] = nil
+		missing = append(missing, self)
+	}
+	h.mu.Unlock()
+
+	errs := make([]error, len(missing))
+	sem := make(chan struct{}, h.opts.Concurrency)
+	var wg sync.WaitGroup
+	for i, self := range missing {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, self string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			object, err := h.c.resolveRef(h.ctx, self)
+			errs[i] = err
+			h.mu.Lock()
+			h.fetched[self] = object
+			h.mu.Unlock()
+		}(i, self)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) resolveRef(ctx context.Context, self string) (json.RawMessage, error) {
+	collection, uid, ok := parseSelf(self)
+	if !ok {
+		return nil, fmt.Errorf("invalid reference %q", self)
+	}
+	return c.getObject(ctx, collection, uid)
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestObjectCacheEviction(t *testing.T) {
+	cache := NewObjectCache(2, 0)
+	cache.Add("images", "img_1", json.RawMessage(`{}`))
+	cache.Add("images", "img_2", json.RawMessage(`{}`))
+	cache.Get("images", "img_1")
+	cache.Add("images", "img_3", json.RawMessage(`{}`))
+
+	if _, ok := cache.Get("images", "img_2"); ok {
+		t.Error("least recently used object was not evicted")
+	}
+	if _, ok := cache.Get("images", "img_1"); !ok {
+		t.Error("recently used object was evicted")
+	}
+
+	cache = NewObjectCache(2, time.Nanosecond)
+	cache.Add("images", "img_1", json.RawMessage(`{}`))
+	time.Sleep(time.Millisecond)
+	if _, ok := cache.Get("images", "img_1"); ok {
+		t.Error("expired object was returned")
+	}
+}
+
+func TestHydrate(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		uid := strings.Split(strings.Trim(r.URL.Path, "/"), "/")[1]
+		w.Write([]byte(`{"uid": "` + uid + `"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithObjectCache(NewObjectCache(10, time.Minute)))
+	object := json.RawMessage(`{"image_urls": ["/api/images/img_1/", "/api/images/img_1/"], "person_url": "/api/people/pers_1/"}`)
+	hydrated, err := c.Hydrate(context.Background(), object, "image_urls", "person_url")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var res struct {
+		Images []struct {
+			UID string `json:"uid"`
+		} `json:"image_urls"`
+		Person struct {
+			UID string `json:"uid"`
+		} `json:"person_url"`
+	}
+	if err := json.Unmarshal(hydrated, &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Images) != 2 || res.Images[1].UID != "img_1" || res.Person.UID != "pers_1" {
+		t.Error("incorrect hydrated object", string(hydrated))
+	}
+	if fetches != 2 {
+		t.Error("shared references should be fetched once, got", fetches)
+	}
+}
+
+func TestHydrateCycles(t *testing.T) {
+	var fetches int32
+	next := map[string]string{"a": "b", "b": "c", "c": "a"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		uid := strings.Split(strings.Trim(r.URL.Path, "/"), "/")[1]
+		fmt.Fprintf(w, `{"self": "/api/nodes/%s/", "next": "/api/nodes/%s/", "root": "/api/nodes/b/"}`, uid, next[uid])
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	object := json.RawMessage(`{"self": "/api/nodes/a/", "next": "/api/nodes/b/", "root": "/api/nodes/b/"}`)
+	hydrated, err := c.HydrateWith(context.Background(), object, HydrateOptions{MaxDepth: 10}, "next", "root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res struct {
+		Next struct {
+			Next struct {
+				Self string
+				Next string
+			}
+		}
+	}
+	if err := json.Unmarshal(hydrated, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Next.Next.Self != "/api/nodes/c/" || res.Next.Next.Next != "/api/nodes/a/" {
+		t.Error("cycle was not stopped at the hydrated object", string(hydrated))
+	}
+	if fetches != 2 {
+		t.Error("expected every object to be fetched once, got", fetches)
+	}
+
+	_, err = c.HydrateWith(context.Background(), object, HydrateOptions{MaxDepth: 10, MaxFetches: 1}, "next")
+	if !errors.Is(err, ErrFetchBudget) {
+		t.Error("expected fetch budget error, got", err)
+	}
+	if _, err := c.HydrateWith(context.Background(), object, HydrateOptions{MaxDepth: 10, MaxFetches: 2}, "next", "root"); err != nil {
+		t.Error("a budget of every distinct reference should suffice, got", err)
+	}
+}
+
+func TestHydrateParallel(t *testing.T) {
+	var active, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		uid := strings.Split(strings.Trim(r.URL.Path, "/"), "/")[1]
+		w.Write([]byte(`{"uid": "` + uid + `"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	object := json.RawMessage(`{"image_urls": ["/api/images/img_1/", "/api/images/img_2/", "/api/images/img_3/", "/api/images/img_4/"]}`)
+	if _, err := c.HydrateWith(context.Background(), object, HydrateOptions{Concurrency: 2}, "image_urls"); err != nil {
+		t.Fatal(err)
+	}
+	if peak != 2 {
+		t.Error("expected 2 parallel fetches, got", peak)
+	}
+
+	_, err := c.HydrateWith(context.Background(), object, HydrateOptions{MaxFetches: 3}, "image_urls")
+	if !errors.Is(err, ErrFetchBudget) {
+		t.Error("expected ErrFetchBudget, got", err)
+	}
+	if _, err := c.HydrateWith(context.Background(), object, HydrateOptions{MaxFetches: 4}, "image_urls"); err != nil {
+		t.Error("a budget of every distinct reference should suffice, got", err)
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// basePace is the delay between pages after the first rate limited page.
+	basePace = 500 * time.Millisecond
+	// maxPace is the longest delay between pages, paging fails if it is rate limited at this pace.
+	maxPace = time.Minute
+)
+
+// pacer slows down paging when the server keeps responding with 429 Too Many Requests,
+// the delay doubles for every rate limited page and halves for every successful one.
+type pacer struct {
+	pace time.Duration
+}
+
+// wait sleeps for the current pace before the next page.
+func (p *pacer) wait(ctx context.Context, sleep func(context.Context, time.Duration) error) error {
+	if p.pace <= 0 {
+		return nil
+	}
+	return sleep(ctx, p.pace)
+}
+
+// throttled slows down after a failed page and reports whether the page should be fetched again.
+func (p *pacer) throttled(err error) bool {
+	if !IsRateLimited(err) || p.pace >= maxPace {
+		return false
+	}
+	next := 2 * p.pace
+	if next < basePace {
+		next = basePace
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > next {
+		next = apiErr.RetryAfter
+	}
+	if next > maxPace {
+		next = maxPace
+	}
+	p.pace = next
+	return true
+}
+
+// succeeded speeds up again after a successful page.
+func (p *pacer) succeeded() {
+	p.pace /= 2
+	if p.pace < basePace/4 {
+		p.pace = 0
+	}
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Limit sets the maximum number of objects returned by a collection request.
+// For ExecuteAll and Iterate it is the page size.
+func (r *Request) Limit(n int) *Request {
+	r.additionalFields["limit"] = strconv.Itoa(n)
+	r.invalidate()
+	return r
+}
+
+// Offset skips the first n objects of a collection request.
+func (r *Request) Offset(n int) *Request {
+	r.additionalFields["offset"] = strconv.Itoa(n)
+	r.invalidate()
+	return r
+}
+
+// WithProgress sets a function that ExecuteAll and Iterate call after every page.
+func (r *Request) WithProgress(fn ProgressFunc) *Request {
+	r.progress = fn
+	return r
+}
+
+// ExecuteAll fetches every page of the collection request and writes all objects to the slice pointed to by v.
+// Pages are fetched starting at the request's offset until the metadata has no next page or the total count is reached,
+// each one after the objects the server actually returned, so servers capping the limit are paged completely.
+func (r *Request) ExecuteAll(v interface{}) error {
+	limit, _ := strconv.Atoi(r.additionalFields["limit"])
+	progress := newProgressTracker(r.progress, r.executor().current().now)
+	all := []json.RawMessage{}
+	err := r.executor().eachPage(r, limit, 0, progress, func(objects []json.RawMessage) error {
+		all = append(all, r.dedupe.filter(objects)...)
+		progress.page(len(objects))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	progress.done()
+	data, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Iterate returns an iterator over the raw objects of every page of the collection request,
+// fetching pages as needed.
+//
+//	it := r.Iterate()
+//	for it.Next() {
+//		var episode Episode
+//		err := json.Unmarshal(it.Value(), &episode)
+//	}
+func (r *Request) Iterate() *Iterator[json.RawMessage] {
+	c := r.executor()
+	pageSize, _ := strconv.Atoi(r.additionalFields["limit"])
+	if pageSize <= 0 {
+		pageSize = c.current().pageSize
+	}
+	if pageSize <= 0 {
+		pageSize = defaultBulkPageSize
+	}
+	offset, _ := strconv.Atoi(r.additionalFields["offset"])
+	request := r.copy()
+	request.client = c
+	return &Iterator[json.RawMessage]{request: request, pageSize: pageSize, offset: offset, progress: newProgressTracker(r.progress, c.current().now)}
+}
+
+// eachPage executes the collection request page by page, starting at offset or the request's offset if it is 0,
+// and calls fn with the objects of every page until the last page.
+// Every page starts after the objects actually returned by the previous one.
+// The last page is the one without a next page link, the one reaching the total count, or for responses without
+// metadata the first one that is not full.
+// Pages are requested without the request's deduplicator, fn must apply it so pages keep their size.
+// If the request's context deadline would pass before the next page arrives, it stops with a *PartialResultError.
+// Rate limited pages are fetched again with a growing delay between pages, which is reported to progress.
+func (c *Client) eachPage(r *Request, pageSize, offset int, progress *progressTracker, fn func(objects []json.RawMessage) error) error {
+	if pageSize <= 0 {
+		pageSize = c.current().pageSize
+	}
+	if pageSize <= 0 {
+		pageSize = defaultBulkPageSize
+	}
+	if offset == 0 {
+		offset, _ = strconv.Atoi(r.additionalFields["offset"])
+	}
+	if progress == nil {
+		progress = newProgressTracker(nil, c.current().now)
+	}
+
+	var previous time.Duration
+	for objects := 0; ; {
+		if objects > 0 {
+			if err := checkPageDeadline(r.ctx, progress.now(), previous, objects, offset); err != nil {
+				return err
+			}
+		}
+		page := r.copy()
+		page.client = c
+		page.dedupe = nil
+		page.additionalFields["limit"] = strconv.Itoa(pageSize)
+		page.additionalFields["offset"] = strconv.Itoa(offset)
+
+		if err := progress.pacer.wait(r.ctx, c.current().sleep); err != nil {
+			return pageFailed(r.ctx, err, objects, offset)
+		}
+		start := progress.now()
+		pageObjects, info, err := page.executeList()
+		if err != nil {
+			if progress.pacer.throttled(err) {
+				continue
+			}
+			return pageFailed(r.ctx, err, objects, offset)
+		}
+		progress.pacer.succeeded()
+		previous = progress.now().Sub(start)
+		progress.counted(info, offset-objects)
+		if err := fn(pageObjects); err != nil {
+			return err
+		}
+		objects += len(pageObjects)
+		if !info.more(offset, len(pageObjects), pageSize) {
+			return nil
+		}
+		offset += len(pageObjects)
+	}
+}
@@ -0,0 +1,77 @@
+package client
+
+import "encoding/json"
+
+// PageInfo is the pagination metadata of a collection response.
+type PageInfo struct {
+	// Count is the total number of matching objects.
+	Count  int `json:"total_count"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	// Next and Previous are the paths of the adjacent pages, they are empty on the first and last page.
+	Next     string `json:"next"`
+	Previous string `json:"previous"`
+
+	// linked is set if the response has a next page key, Next is then only empty on the last page.
+	linked bool
+}
+
+// HasNext reports whether there is a page after this one.
+func (p PageInfo) HasNext() bool {
+	return p.Next != ""
+}
+
+// HasPrevious reports whether there is a page before this one.
+func (p PageInfo) HasPrevious() bool {
+	return p.Previous != ""
+}
+
+// more reports whether the page of n objects at offset is followed by another page.
+// It is decided by the next page link if the response has one, otherwise by the total count.
+// Without either, pages follow until one holds fewer objects than the limit.
+func (p PageInfo) more(offset, n, limit int) bool {
+	switch {
+	case n == 0:
+		return false
+	case p.Count > 0 && offset+n >= p.Count:
+		return false
+	case p.linked:
+		return p.Next != ""
+	case p.Count > 0:
+		return true
+	default:
+		return n >= limit
+	}
+}
+
+// List is a decoded collection response with its pagination metadata,
+// the metadata fields like Count are promoted from PageInfo.
+type List[T any] struct {
+	Objects  []T `json:"objects"`
+	PageInfo `json:"meta"`
+}
+
+// ExecuteList executes the collection request and decodes its objects and metadata, see WithEnvelope.
+func ExecuteList[T any](r *Request) (*List[T], error) {
+	var l List[T]
+	if r.executor().current().envelope == nil {
+		if err := r.Execute(&l); err != nil {
+			return nil, err
+		}
+		return &l, nil
+	}
+	// the envelope may not hold the objects in an objects list, so duplicates are removed after decoding it
+	objects, info, err := r.withoutDedupe().executeList()
+	if err != nil {
+		return nil, err
+	}
+	objects = r.dedupe.filter(objects)
+	l.PageInfo = info
+	l.Objects = make([]T, len(objects))
+	for i, object := range objects {
+		if err := json.Unmarshal(object, &l.Objects[i]); err != nil {
+			return nil, err
+		}
+	}
+	return &l, nil
+}
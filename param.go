@@ -0,0 +1,99 @@
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ParamEncoder is implemented by types that control how they are encoded as filter and query parameter values,
+// for example domain IDs or enums.
+type ParamEncoder interface {
+	EncodeParam() string
+}
+
+// encodeParam encodes a filter or query parameter value.
+// ParamEncoders encode themselves, times use RFC 3339, slices are joined with commas
+// and other values use their String method or default format.
+func encodeParam(value interface{}) string {
+	switch v := value.(type) {
+	case ParamEncoder:
+		return v.EncodeParam()
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		values := make([]string, rv.Len())
+		for i := range values {
+			values[i] = encodeParam(rv.Index(i).Interface())
+		}
+		return strings.Join(values, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// NewFilterValue creates a filter with a given constraint and a value that is encoded as a parameter,
+// see ParamEncoder.
+func NewFilterValue(c constraint, value interface{}) *Filter {
+	f := NewFilter(c, encodeParam(value))
+	switch v := value.(type) {
+	case ParamEncoder:
+	case time.Time:
+		f.time = &v
+	default:
+		f.items = timeItems(value)
+	}
+	return f
+}
+
+// timeItems returns a filter for every element of a slice or array that contains times,
+// so each time is encoded with the client's TimeEncoder. It returns nil for other values.
+func timeItems(value interface{}) []*Filter {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+	items := make([]*Filter, rv.Len())
+	times := false
+	for i := range items {
+		elem := rv.Index(i).Interface()
+		items[i] = &Filter{value: encodeParam(elem)}
+		if t, ok := elem.(time.Time); ok {
+			items[i].time = &t
+			times = true
+		}
+	}
+	if !times {
+		return nil
+	}
+	return items
+}
+
+// WithParam adds a query parameter, the value is encoded like filter values.
+func (r *Request) WithParam(key string, value interface{}) *Request {
+	var t *time.Time
+	if v, ok := value.(time.Time); ok {
+		t = &v
+	}
+	r.setParam(key, encodeParam(value), t)
+	return r
+}
+
+// setParam sets a query parameter, t is its value if it was set from a time.
+func (r *Request) setParam(key, value string, t *time.Time) {
+	r.additionalFields[key] = value
+	r.invalidate()
+	if t == nil {
+		delete(r.times, key)
+		return
+	}
+	if r.times == nil {
+		r.times = make(map[string]time.Time)
+	}
+	r.times[key] = *t
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+type season int
+
+func (s season) EncodeParam() string {
+	return "season_" + string(rune('0'+s))
+}
+
+func TestEncodeParam(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{"title", "title"},
+		{42, "42"},
+		{true, "true"},
+		{season(3), "season_3"},
+		{[]season{1, 2}, "season_1,season_2"},
+		{time.Date(2020, 3, 15, 5, 10, 0, 0, time.UTC), "2020-03-15T05:10:00Z"},
+		{90 * time.Second, "1m30s"},
+	}
+	for _, test := range tests {
+		if actual := encodeParam(test.value); actual != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, actual)
+		}
+	}
+
+	r := NewRequest("https://test.com", "episodes", "").
+		WithFilter("season", NewFilterValue(Equals, season(2))).
+		WithParam("limit", 10)
+	u, err := r.ToURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Query().Get("season") != "season_2" || u.Query().Get("limit") != "10" {
+		t.Error("incorrect query", u.RawQuery)
+	}
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PartialResultError is returned by paged operations that stopped early because their context's deadline
+// would pass before the next page arrives, or passed while fetching it.
+// The objects processed before stopping are complete and valid.
+type PartialResultError struct {
+	// Objects is the number of objects processed before stopping.
+	Objects int
+	// Offset is the offset of the first object that was not processed.
+	Offset int
+	// Token resumes the operation at Offset, see Request.Resume.
+	Token string
+	Err   error
+}
+
+func (e *PartialResultError) Error() string {
+	return fmt.Sprintf("partial result after %d objects: %v", e.Objects, e.Err)
+}
+
+func (e *PartialResultError) Unwrap() error {
+	return e.Err
+}
+
+func newPartialResultError(objects, offset int, err error) *PartialResultError {
+	token := base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+	return &PartialResultError{Objects: objects, Offset: offset, Token: token, Err: err}
+}
+
+// parseResumeToken returns the offset of a PartialResultError's token.
+func parseResumeToken(token string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil && strings.HasPrefix(string(data), "offset:") {
+		var offset int
+		if offset, err = strconv.Atoi(strings.TrimPrefix(string(data), "offset:")); err == nil && offset >= 0 {
+			return offset, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid resume token %q", token)
+}
+
+// Resume makes paged operations on the request continue where the one that returned token stopped.
+// An invalid token makes executing the request fail.
+func (r *Request) Resume(token string) *Request {
+	offset, err := parseResumeToken(token)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	r.additionalFields["offset"] = strconv.Itoa(offset)
+	r.invalidate()
+	return r
+}
+
+// checkPageDeadline returns a PartialResultError if ctx is done or its deadline is closer to now than
+// the time the previous page took.
+func checkPageDeadline(ctx context.Context, now time.Time, previous time.Duration, objects, offset int) error {
+	if err := ctx.Err(); err != nil {
+		return newPartialResultError(objects, offset, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Sub(now) < previous {
+		return newPartialResultError(objects, offset, context.DeadlineExceeded)
+	}
+	return nil
+}
+
+// pageFailed wraps the error of a failed page in a PartialResultError if it was caused by the context.
+func pageFailed(ctx context.Context, err error, objects, offset int) error {
+	if ctx.Err() != nil {
+		return newPartialResultError(objects, offset, err)
+	}
+	return err
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPartialResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprintf(w, `{"objects": [{"uid": "ep_%s"}]}`, r.URL.Query().Get("offset"))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 130*time.Millisecond)
+	defer cancel()
+	var out bytes.Buffer
+	n, err := c.Export(ctx, "episodes", ExportOptions{PageSize: 1}, &out)
+	var partial *PartialResultError
+	if !errors.As(err, &partial) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected partial result error, got", err)
+	}
+	if n != 2 || partial.Objects != 2 || partial.Offset != 2 {
+		t.Error("incorrect partial result", n, partial)
+	}
+
+	r := c.NewRequest("episodes", "").Resume(partial.Token)
+	if r.additionalFields["offset"] != "2" {
+		t.Error("incorrect resume offset", r.additionalFields["offset"])
+	}
+	if err := c.NewRequest("episodes", "").Resume("invalid").Execute(nil); err == nil {
+		t.Error("expected error for invalid token")
+	}
+}
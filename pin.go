@@ -0,0 +1,86 @@
+package client
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"net/http"
+)
+
+// ErrPinMismatch is returned when no certificate presented by the server matches a pin.
+var ErrPinMismatch = errors.New("server certificate does not match any pin")
+
+// PublicKeyHash returns the base64 encoded SHA-256 hash of the certificate's public key, as used by WithPinnedPublicKeys.
+func PublicKeyHash(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// CertificateHash returns the base64 encoded SHA-256 hash of the certificate, as used by WithPinnedCertificates.
+func CertificateHash(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.Raw)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// WithPinnedPublicKeys makes requests fail with ErrPinMismatch unless a certificate in the server's chain
+// has a public key with one of the given hashes, see PublicKeyHash.
+// Pinning public keys instead of certificates keeps working when certificates are renewed with the same key.
+func WithPinnedPublicKeys(hashes ...string) Option {
+	return func(s *settings) {
+		s.pins = s.pins.with(hashes, nil)
+		s.installPins()
+	}
+}
+
+// WithPinnedCertificates makes requests fail with ErrPinMismatch unless a certificate in the server's chain
+// has one of the given hashes, see CertificateHash.
+func WithPinnedCertificates(hashes ...string) Option {
+	return func(s *settings) {
+		s.pins = s.pins.with(nil, hashes)
+		s.installPins()
+	}
+}
+
+// pinSet holds the pins of a client, a connection is accepted if any pin matches.
+type pinSet struct {
+	publicKeys   map[string]bool
+	certificates map[string]bool
+}
+
+// with returns a copy of the set with the hashes added.
+func (p *pinSet) with(publicKeys, certificates []string) *pinSet {
+	n := &pinSet{publicKeys: make(map[string]bool), certificates: make(map[string]bool)}
+	if p != nil {
+		for hash := range p.publicKeys {
+			n.publicKeys[hash] = true
+		}
+		for hash := range p.certificates {
+			n.certificates[hash] = true
+		}
+	}
+	for _, hash := range publicKeys {
+		n.publicKeys[hash] = true
+	}
+	for _, hash := range certificates {
+		n.certificates[hash] = true
+	}
+	return n
+}
+
+func (p *pinSet) verify(cs tls.ConnectionState) error {
+	for _, cert := range cs.PeerCertificates {
+		if p.publicKeys[PublicKeyHash(cert)] || p.certificates[CertificateHash(cert)] {
+			return nil
+		}
+	}
+	return ErrPinMismatch
+}
+
+func (s *settings) installPins() {
+	pins := s.pins
+	s.modifyTransport(func(t *http.Transport) {
+		t.TLSClientConfig.VerifyConnection = pins.verify
+	})
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnboundPlaceholder is returned when executing a request whose placeholders were not bound, see Request.Bind.
+var ErrUnboundPlaceholder = errors.New("unbound placeholder")
+
+// NewPlaceholderFilter creates a filter whose value is bound later with Request.Bind.
+// Placeholders are names in braces like {season}, as in query definitions and path templates. The constraint is checked when the value is bound.
+func NewPlaceholderFilter(c constraint, placeholder string) *Filter {
+	f := &Filter{c: c, placeholder: placeholder}
+	if _, ok := placeholderName(placeholder); !ok {
+		f.err = fmt.Errorf("invalid placeholder %q", placeholder)
+	}
+	return f
+}
+
+// WithFilterTemplate filters the field by a value that is bound later with Bind.
+//
+//	prepared := c.NewRequest("races", "").WithFilterTemplate("season", "{season}")
+//	prepared.Bind(map[string]interface{}{"season": 2020}).Execute(&races)
+func (r *Request) WithFilterTemplate(fieldName, placeholder string) *Request {
+	return r.WithFilter(fieldName, NewPlaceholderFilter(Equals, placeholder))
+}
+
+// Bind returns a copy of the request with its placeholders replaced by the given values, keyed by placeholder name
+// without the braces. An ID like {uid} is a placeholder too, as are placeholder filters of fields and their sub fields.
+// Values are encoded like filter values, see ParamEncoder. The request itself is not modified so it can be bound again.
+// Executing a request with unbound placeholders fails with ErrUnboundPlaceholder.
+func (r *Request) Bind(values map[string]interface{}) *Request {
+	b := r.copy()
+	if name, ok := placeholderName(b.ID); ok {
+		if value, ok := values[name]; ok {
+			b.ID = encodeParam(value)
+		}
+	}
+	for i, f := range b.filters {
+		if bound := b.bindFilter(f.filter, values); bound != f.filter {
+			b.filters[i] = &filterParam{field: f.field, key: f.key, filter: bound}
+		}
+	}
+	for name, f := range b.Fields {
+		if f.placeholder() != "" {
+			// the field is shared with the request that is bound
+			f = f.clone()
+			b.bindFieldFilters(f, values)
+			b.Fields[name] = f
+		}
+	}
+	b.invalidate()
+	return b
+}
+
+// bindFieldFilters replaces the placeholder filters of the field and its sub fields, which must not be shared.
+func (r *Request) bindFieldFilters(f *Field, values map[string]interface{}) {
+	for i, filter := range f.filters {
+		f.filters[i] = r.bindFilter(filter, values)
+	}
+	for _, sub := range f.SubFields {
+		r.bindFieldFilters(sub, values)
+	}
+}
+
+// bindFilter returns the filter with its placeholder replaced by its value,
+// or the filter itself if it has no placeholder or no value was given for it.
+func (r *Request) bindFilter(filter *Filter, values map[string]interface{}) *Filter {
+	if filter.placeholder == "" {
+		return filter
+	}
+	name, _ := placeholderName(filter.placeholder)
+	value, ok := values[name]
+	if !ok {
+		return filter
+	}
+	bound := NewFilterValue(filter.c, value)
+	if bound.err != nil && r.err == nil {
+		r.err = bound.err
+	}
+	return bound
+}
+
+// unbound returns an error for the first placeholder of the request that was not bound.
+func (r *Request) unbound() error {
+	if _, ok := placeholderName(r.ID); ok {
+		return fmt.Errorf("%w %s", ErrUnboundPlaceholder, r.ID)
+	}
+	for _, f := range r.filters {
+		if f.filter.placeholder != "" {
+			return fmt.Errorf("%w %s", ErrUnboundPlaceholder, f.filter.placeholder)
+		}
+	}
+	for _, f := range r.Fields {
+		if placeholder := f.placeholder(); placeholder != "" {
+			return fmt.Errorf("%w %s", ErrUnboundPlaceholder, placeholder)
+		}
+	}
+	return nil
+}
+
+// placeholder returns the first placeholder of the filters of the field and its sub fields.
+func (f *Field) placeholder() string {
+	for _, filter := range f.filters {
+		if filter.placeholder != "" {
+			return filter.placeholder
+		}
+	}
+	for _, sub := range f.SubFields {
+		if placeholder := sub.placeholder(); placeholder != "" {
+			return placeholder
+		}
+	}
+	return ""
+}
+
+// placeholderName returns the name of a placeholder like {season}, and false if s is not a placeholder.
+func placeholderName(s string) (string, bool) {
+	if len(s) < 3 || s[0] != '{' || s[len(s)-1] != '}' {
+		return "", false
+	}
+	name := s[1 : len(s)-1]
+	return name, !strings.ContainsAny(name, "{}")
+}
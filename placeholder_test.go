@@ -0,0 +1,45 @@
+package client
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPlaceholders(t *testing.T) {
+	prepared := NewRequest("https://test.com/api/", "drivers", "{uid}").
+		AddField(NewField("name")).
+		WithFilterTemplate("season", "{season}").
+		WithFilter("year", NewPlaceholderFilter(GreaterThanOrEqual, "{since}"))
+
+	if _, err := prepared.ToURL(); !errors.Is(err, ErrUnboundPlaceholder) {
+		t.Error("expected ErrUnboundPlaceholder", err)
+	}
+
+	bound := prepared.Bind(map[string]interface{}{"uid": "drv_1", "season": 2020, "since": 2018})
+	testURL(bound, "https://test.com/api/drivers/drv_1/?fields=name&season=2020&year__gte=2018", t)
+
+	other := prepared.Bind(map[string]interface{}{"uid": "drv_2", "season": "2021&limit=1", "since": 2019})
+	testURL(other, "https://test.com/api/drivers/drv_2/?fields=name&season=2021%26limit%3D1&year__gte=2019", t)
+
+	if _, err := prepared.Bind(map[string]interface{}{"uid": "drv_1"}).ToURL(); !errors.Is(err, ErrUnboundPlaceholder) {
+		t.Error("expected ErrUnboundPlaceholder for a partially bound request", err)
+	}
+	if _, err := prepared.ToURL(); !errors.Is(err, ErrUnboundPlaceholder) {
+		t.Error("binding modified the prepared request", err)
+	}
+	fields := NewRequest("https://test.com/api/", "races", "").
+		AddField(NewField("season").WithFilter(NewPlaceholderFilter(Equals, "{season}"))).
+		AddField(NewField("circuit_url").WithSubField(NewField("country").WithFilter(NewPlaceholderFilter(Equals, "{country}"))))
+	if _, err := fields.Bind(map[string]interface{}{"season": 2020}).ToURL(); !errors.Is(err, ErrUnboundPlaceholder) {
+		t.Error("expected ErrUnboundPlaceholder for an unbound sub field filter", err)
+	}
+	testURL(fields.Bind(map[string]interface{}{"season": 2020, "country": "Monaco"}),
+		"https://test.com/api/races/?circuit_url__country=Monaco&fields=season,circuit_url,circuit_url__country&fields_to_expand=circuit_url&season=2020", t)
+	if _, err := fields.ToURL(); !errors.Is(err, ErrUnboundPlaceholder) {
+		t.Error("binding modified the fields of the prepared request", err)
+	}
+
+	if _, err := NewRequest("https://test.com/api/", "drivers", "").WithFilterTemplate("season", ":season").ToURL(); err == nil {
+		t.Error("expected an error for a placeholder without braces")
+	}
+}
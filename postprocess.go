@@ -0,0 +1,157 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PostProcessor transforms the objects of a collection response after it was received, for queries the server
+// can't express. Objects are decoded with numbers as json.Number.
+type PostProcessor func(objects []map[string]interface{}) []map[string]interface{}
+
+// PostProcess adds steps that are applied in order to the objects of every response before it is decoded.
+// Paginated requests apply them to each page, responses for a single object are not processed.
+func (r *Request) PostProcess(steps ...PostProcessor) *Request {
+	r.postProcessors = append(r.postProcessors, steps...)
+	return r
+}
+
+// SortObjects sorts objects by their fields, fields of nested objects are separated by "__".
+// Prefix a field with - to sort it in descending order. The sort is stable, so objects the fields don't order
+// keep the order of the server.
+func SortObjects(fields ...string) PostProcessor {
+	return func(objects []map[string]interface{}) []map[string]interface{} {
+		sort.SliceStable(objects, func(i, j int) bool {
+			for _, field := range fields {
+				desc := strings.HasPrefix(field, "-")
+				path := strings.TrimPrefix(field, "-")
+				c := compareJSON(lookupPath(objects[i], path), lookupPath(objects[j], path))
+				if c != 0 {
+					return c < 0 != desc
+				}
+			}
+			return false
+		})
+		return objects
+	}
+}
+
+// ProjectObjects removes all but the given top level fields from objects.
+func ProjectObjects(fields ...string) PostProcessor {
+	return func(objects []map[string]interface{}) []map[string]interface{} {
+		for i, object := range objects {
+			projected := make(map[string]interface{}, len(fields))
+			for _, field := range fields {
+				if value, ok := object[field]; ok {
+					projected[field] = value
+				}
+			}
+			objects[i] = projected
+		}
+		return objects
+	}
+}
+
+// FilterObjects keeps the objects for which keep returns true.
+func FilterObjects(keep func(object map[string]interface{}) bool) PostProcessor {
+	return func(objects []map[string]interface{}) []map[string]interface{} {
+		kept := objects[:0]
+		for _, object := range objects {
+			if keep(object) {
+				kept = append(kept, object)
+			}
+		}
+		return kept
+	}
+}
+
+// withoutPostProcessing returns a copy of the request that sends it without applying its post processors.
+func (r *Request) withoutPostProcessing() *Request {
+	c := *r
+	c.postProcessors = nil
+	return &c
+}
+
+// postProcess applies the steps to the objects of the response and decodes it into v.
+func (s *settings) postProcess(steps []PostProcessor, data json.RawMessage, v interface{}) error {
+	raw, info, err := s.decodeEnvelope(data)
+	if err != nil || raw == nil {
+		return json.Unmarshal(data, v)
+	}
+	objects := make([]map[string]interface{}, len(raw))
+	for i, object := range raw {
+		dec := json.NewDecoder(bytes.NewReader(object))
+		dec.UseNumber()
+		if err := dec.Decode(&objects[i]); err != nil {
+			return err
+		}
+	}
+	for _, step := range steps {
+		objects = step(objects)
+	}
+	raw = make([]json.RawMessage, len(objects))
+	for i, object := range objects {
+		if raw[i], err = json.Marshal(object); err != nil {
+			return err
+		}
+	}
+	if data, err = s.replaceObjects(data, raw, info); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// lookupPath returns the value of a field, fields of nested objects are separated by "__".
+func lookupPath(object map[string]interface{}, path string) interface{} {
+	var value interface{} = object
+	for _, key := range strings.Split(path, "__") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
+
+// compareJSON orders decoded JSON values, missing values and null sort first.
+func compareJSON(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if x, ok := a.(json.Number); ok {
+		if y, ok := b.(json.Number); ok {
+			fx, errX := x.Float64()
+			fy, errY := y.Float64()
+			if errX == nil && errY == nil {
+				switch {
+				case fx < fy:
+					return -1
+				case fx > fy:
+					return 1
+				}
+				return 0
+			}
+		}
+	}
+	if x, ok := a.(bool); ok {
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case !x:
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
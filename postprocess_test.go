@@ -0,0 +1,51 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPostProcess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"objects": [
+			{"name": "Hamilton", "team": {"name": "Mercedes"}, "points": 347, "retired": false},
+			{"name": "Verstappen", "team": {"name": "Red Bull"}, "points": 214, "retired": false},
+			{"name": "Bottas", "team": {"name": "Mercedes"}, "points": 223, "retired": false},
+			{"name": "Raikkonen", "team": {"name": "Alfa Romeo"}, "points": 4, "retired": true}
+		], "total_count": 4}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	var res struct {
+		Objects []map[string]interface{} `json:"objects"`
+		Count   int                      `json:"total_count"`
+	}
+	err := c.NewRequest("drivers", "").
+		PostProcess(
+			FilterObjects(func(o map[string]interface{}) bool { return o["retired"] == false }),
+			SortObjects("team__name", "-points"),
+			ProjectObjects("name", "points"),
+		).
+		Execute(&res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, o := range res.Objects {
+		names = append(names, o["name"].(string))
+		if _, ok := o["team"]; ok {
+			t.Error("field was not projected", o)
+		}
+	}
+	data, _ := json.Marshal(names)
+	if string(data) != `["Hamilton","Bottas","Verstappen"]` {
+		t.Error("incorrect objects", string(data))
+	}
+	if res.Count != 4 {
+		t.Error("envelope was not kept", res.Count)
+	}
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Preloader executes a manifest of requests, like the queries needed to render a homepage, to populate
+// the client's caches, for example right after a deploy. Responses fill the caches set with WithCache and
+// WithStaleIfError, and their objects are added to the cache set with WithObjectCache.
+type Preloader struct {
+	// Client executes the requests.
+	Client *Client
+	// Requests is the manifest of requests to preload.
+	Requests []*Request
+	// Concurrency is the number of requests executed in parallel, see Client.Batch.
+	Concurrency int
+	// Interval is the time between preloads when running on a schedule with Run.
+	Interval time.Duration
+	// OnError is called for every request that failed, it may be nil.
+	OnError func(r *Request, err error)
+}
+
+// Preload executes the requests once and returns their errors in the order of the manifest,
+// nil for requests that succeeded.
+func (p *Preloader) Preload(ctx context.Context) []error {
+	calls := make([]BatchCall, len(p.Requests))
+	bodies := make([]json.RawMessage, len(p.Requests))
+	for i, r := range p.Requests {
+		calls[i] = BatchCall{Request: r, Dest: &bodies[i]}
+	}
+	errs := p.Client.Batch(ctx, calls, p.Concurrency)
+	cache := p.Client.current().objectCache
+	for i, err := range errs {
+		if err != nil {
+			if p.OnError != nil {
+				p.OnError(p.Requests[i], err)
+			}
+			continue
+		}
+		if cache != nil {
+			cacheObjects(cache, p.Requests[i], bodies[i])
+		}
+	}
+	return errs
+}
+
+// Run preloads the requests and repeats it every Interval until ctx is done or the client was closed.
+// It returns ctx's error or ErrClientClosed. A zero Interval preloads once and returns nil.
+func (p *Preloader) Run(ctx context.Context) error {
+	for {
+		for _, err := range p.Preload(ctx) {
+			if errors.Is(err, ErrClientClosed) {
+				return err
+			}
+		}
+		if p.Interval <= 0 {
+			return nil
+		}
+		if err := p.Client.current().sleep(ctx, p.Interval); err != nil {
+			return err
+		}
+	}
+}
+
+// cacheObjects adds the object or the objects of the list in the response body of r to the cache.
+func cacheObjects(cache *ObjectCache, r *Request, body json.RawMessage) {
+	var objects []json.RawMessage
+	if r.ID != "" {
+		objects = []json.RawMessage{body}
+	} else {
+		var list struct {
+			Objects []json.RawMessage `json:"objects"`
+		}
+		if json.Unmarshal(body, &list) != nil {
+			return
+		}
+		objects = list.Objects
+	}
+	for _, object := range objects {
+		var meta struct {
+			UID string `json:"uid"`
+		}
+		if json.Unmarshal(object, &meta) == nil && meta.UID != "" {
+			cache.Add(r.Collection, meta.UID, object)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPreloader(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/episodes/":
+			w.Write([]byte(`{"objects":[{"uid":"ep_1"},{"uid":"ep_2"}]}`))
+		case "/sets/set_1/":
+			w.Write([]byte(`{"uid":"set_1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cache := NewObjectCache(10, 0)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	c := NewClient(WithBaseURL(server.URL), WithObjectCache(cache), WithSleeper(clock))
+	failed := 0
+	p := &Preloader{
+		Client:   c,
+		Requests: []*Request{c.NewRequest("episodes", ""), c.NewRequest("sets", "set_1"), c.NewRequest("missing", "")},
+		OnError:  func(r *Request, err error) { failed++ },
+	}
+	errs := p.Preload(context.Background())
+	if errs[0] != nil || errs[1] != nil || !IsNotFound(errs[2]) || failed != 1 {
+		t.Fatal("unexpected errors", errs)
+	}
+	if cache.Len() != 3 {
+		t.Error("expected 3 cached objects, got", cache.Len())
+	}
+	if _, ok := cache.Get("sets", "set_1"); !ok {
+		t.Error("single object was not cached")
+	}
+
+	// Run repeats until the client is closed
+	atomic.StoreInt32(&requests, 0)
+	p.Interval = time.Minute
+	c.UpdateConfig(WithSleeper(SleeperFunc(func(ctx context.Context, d time.Duration) error {
+		if atomic.LoadInt32(&requests) >= 6 {
+			c.Close(ctx)
+		}
+		return nil
+	})))
+	if err := p.Run(context.Background()); err != ErrClientClosed {
+		t.Error("expected ErrClientClosed, got", err)
+	}
+}
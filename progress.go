@@ -0,0 +1,68 @@
+package client
+
+import "time"
+
+// Progress describes how far a multi page operation has come.
+type Progress struct {
+	// Pages is the number of pages fetched so far.
+	Pages int
+	// Objects is the number of objects processed so far.
+	Objects int
+	// Total is the number of objects to process, taken from the total count of the first page,
+	// or 0 if the server didn't report it. It is exact once Done is set.
+	Total int
+	// Elapsed is the time since the operation started.
+	Elapsed time.Duration
+	// Done is set for the last call, after the final page was processed.
+	Done bool
+	// Pace is the current delay between pages, it grows while the server responds with 429 Too Many Requests.
+	Pace time.Duration
+}
+
+// ProgressFunc is called after every page of a multi page operation.
+type ProgressFunc func(Progress)
+
+// progressTracker reports progress of a paged operation to an optional ProgressFunc.
+type progressTracker struct {
+	fn    ProgressFunc
+	now   func() time.Time
+	start time.Time
+	p     Progress
+	pacer pacer
+}
+
+// newProgressTracker starts tracking, elapsed time is measured with now, usually the client's clock.
+func newProgressTracker(fn ProgressFunc, now func() time.Time) *progressTracker {
+	return &progressTracker{fn: fn, now: now, start: now()}
+}
+
+// page records a fetched page with n objects.
+func (t *progressTracker) page(n int) {
+	t.p.Pages++
+	t.p.Objects += n
+	t.report()
+}
+
+// counted records the total count of the first page, of which the objects before offset are skipped.
+func (t *progressTracker) counted(info PageInfo, offset int) {
+	if t.p.Pages > 0 || info.Count <= offset {
+		return
+	}
+	t.p.Total = info.Count - offset
+}
+
+// done reports that the operation finished after processing every object.
+func (t *progressTracker) done() {
+	t.p.Done = true
+	t.p.Total = t.p.Objects
+	t.report()
+}
+
+func (t *progressTracker) report() {
+	if t.fn == nil {
+		return
+	}
+	t.p.Elapsed = t.now().Sub(t.start)
+	t.p.Pace = t.pacer.pace
+	t.fn(t.p)
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// ErrProxyNotEnabled is returned when a request should use a proxy but the client was created without WithContextProxy.
+var ErrProxyNotEnabled = errors.New("per-request proxies require WithContextProxy")
+
+type proxyKey struct{}
+
+// ContextWithProxy returns a context that routes requests executed with it through the given proxy.
+func ContextWithProxy(ctx context.Context, proxy *url.URL) context.Context {
+	return context.WithValue(ctx, proxyKey{}, proxy)
+}
+
+func proxyFromContext(ctx context.Context) *url.URL {
+	proxy, _ := ctx.Value(proxyKey{}).(*url.URL)
+	return proxy
+}
+
+// WithProxy routes the request through the given proxy, overriding a proxy set on its context.
+// The proxy is kept when the request's context is replaced with WithContext.
+func (r *Request) WithProxy(proxy *url.URL) *Request {
+	r.proxy = proxy
+	return r
+}
+
+// WithContextProxy enables choosing the proxy per request with ContextWithProxy or Request.WithProxy.
+// Requests without a proxy use fallback, or the proxy from the environment if fallback is nil.
+func WithContextProxy(fallback func(*http.Request) (*url.URL, error)) Option {
+	if fallback == nil {
+		fallback = http.ProxyFromEnvironment
+	}
+	return func(s *settings) {
+		s.contextProxy = true
+		s.modifyTransport(func(t *http.Transport) {
+			t.Proxy = func(req *http.Request) (*url.URL, error) {
+				if proxy := proxyFromContext(req.Context()); proxy != nil {
+					return proxy, nil
+				}
+				return fallback(req)
+			}
+		})
+	}
+}
+
+// checkProxy makes sure requests that must use a proxy are not sent directly.
+func (s *settings) checkProxy(r *Request) error {
+	if !s.contextProxy && (r.proxy != nil || proxyFromContext(r.ctx) != nil) {
+		return ErrProxyNotEnabled
+	}
+	return nil
+}
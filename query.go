@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownQuery is returned when executing a query that wasn't defined.
+var ErrUnknownQuery = errors.New("unknown query")
+
+// QueryDefinition describes a named query. The ID and filter values can contain placeholders in braces
+// that are replaced with parameters when the query is executed. Filters are keyed like query parameters,
+// for example year__gte.
+type QueryDefinition struct {
+	Collection string            `yaml:"collection"`
+	ID         string            `yaml:"id"`
+	Fields     []string          `yaml:"fields"`
+	Expand     []string          `yaml:"expand"`
+	Filters    map[string]string `yaml:"filters"`
+	OrderBy    string            `yaml:"order_by"`
+	Limit      int               `yaml:"limit"`
+}
+
+// Queries are query definitions by name.
+type Queries map[string]QueryDefinition
+
+// LoadQueries reads named queries from a YAML or JSON file.
+//
+//	race_results:
+//	  collection: results
+//	  fields: [driver, position]
+//	  expand: [driver]
+//	  filters:
+//	    season: "{season}"
+//	    position__lte: "{top}"
+//	  order_by: position
+func LoadQueries(path string) (Queries, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var queries Queries
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&queries); err != nil {
+		return nil, fmt.Errorf("invalid query file %s: %w", path, err)
+	}
+	for name, q := range queries {
+		if q.Collection == "" {
+			return nil, fmt.Errorf("invalid query file %s: query %s has no collection", path, name)
+		}
+	}
+	return queries, nil
+}
+
+// WithQueries adds named queries to the client, see Client.Query.
+func WithQueries(queries Queries) Option {
+	return func(s *settings) {
+		if s.queries == nil {
+			s.queries = make(Queries)
+		}
+		for name, q := range queries {
+			s.queries[name] = q
+		}
+	}
+}
+
+// Query returns a request for the named query with its placeholders replaced by params.
+// It fails if a placeholder has no parameter.
+func (c *Client) Query(name string, params map[string]string) (*Request, error) {
+	q, ok := c.current().queries[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownQuery, name)
+	}
+	id, err := substitute(q.ID, params)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", name, err)
+	}
+
+	r := c.NewRequest(q.Collection, id)
+	for _, field := range q.Fields {
+		r.AddField(NewField(field))
+	}
+	for _, field := range q.Expand {
+		if f, ok := r.Fields[field]; ok {
+			f.IsExpanded = true
+			r.AddField(f)
+			continue
+		}
+		r.Expand(NewField(field))
+	}
+	keys := make([]string, 0, len(q.Filters))
+	for key := range q.Filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, err := substitute(q.Filters[key], params)
+		if err != nil {
+			return nil, fmt.Errorf("query %s: %w", name, err)
+		}
+		field, op := key, ""
+		if i := strings.LastIndex(key, "__"); i >= 0 && isComparator(constraint(key[i+2:])) {
+			field, op = key[:i], key[i+2:]
+		}
+		r.WithFilter(field, NewFilter(constraint(op), value))
+	}
+	if q.OrderBy != "" {
+		r.OrderBy(NewField(q.OrderBy))
+	}
+	if q.Limit > 0 {
+		r.Limit(q.Limit)
+	}
+	return r, r.err
+}
+
+// ExecuteQuery executes the named query with ctx and decodes the response into v, see Query.
+func (c *Client) ExecuteQuery(ctx context.Context, name string, params map[string]string, v interface{}) error {
+	r, err := c.Query(name, params)
+	if err != nil {
+		return err
+	}
+	return c.Do(r.WithContext(ctx), v)
+}
+
+// substitute replaces the placeholders in s with params.
+func substitute(s string, params map[string]string) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(s, '{')
+		if start < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder in %q", s)
+		}
+		name := s[start+1 : start+end]
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("missing parameter %q", name)
+		}
+		b.WriteString(s[:start])
+		b.WriteString(value)
+		s = s[start+end+1:]
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNamedQueries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queries.yaml")
+	queries := `
+results:
+  collection: results
+  fields: [driver, position]
+  expand: [driver]
+  filters:
+    season: "{season}"
+    position__lte: "{top}"
+  order_by: position
+driver:
+  collection: drivers
+  id: "{uid}"
+`
+	if err := ioutil.WriteFile(path, []byte(queries), 0600); err != nil {
+		t.Fatal(err)
+	}
+	q, err := LoadQueries(path)
+	if err != nil {
+		t.Fatal("Error loading queries:", err)
+	}
+
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"objects":[]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithQueries(q))
+	r, err := c.Query("results", map[string]string{"season": "2020", "top": "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := c.url(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := u.Query()
+	if params.Get("season") != "2020" || params.Get("position__lte") != "3" || params.Get("order") != "position" || params.Get("fields_to_expand") != "driver" || !strings.Contains(params.Get("fields"), "driver") {
+		t.Error("incorrect query", u.RawQuery)
+	}
+
+	if err := c.ExecuteQuery(context.Background(), "results", map[string]string{"season": "2021", "top": "1"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if query == "" {
+		t.Error("query was not sent")
+	}
+
+	r, err = c.Query("driver", map[string]string{"uid": "drv/1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, err := c.url(r); err != nil || u.EscapedPath() != "/drivers/drv%2F1/" {
+		t.Error("incorrect path", u, err)
+	}
+
+	if _, err := c.Query("results", map[string]string{"season": "2020"}); err == nil {
+		t.Error("expected an error for a missing parameter")
+	}
+	if _, err := c.Query("missing", nil); !errors.Is(err, ErrUnknownQuery) {
+		t.Error("expected ErrUnknownQuery", err)
+	}
+}
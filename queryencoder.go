@@ -0,0 +1,124 @@
+package client
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// QueryFilter is a filter of a request passed to a QueryEncoder.
+type QueryFilter struct {
+	// Path is the filtered field split at its relations, like [team name] for team__name.
+	Path []string
+	// Constraint is the filter's constraint like gte, it is empty for Equals.
+	Constraint string
+	Value      string
+}
+
+// QueryEncoder encodes the query string of requests from their filters and their other parameters,
+// like fields or limit, that are given with Skylark's parameter names.
+type QueryEncoder func(params url.Values, filters []QueryFilter) string
+
+var (
+	// SkylarkQuery encodes filters like season__gte=2020, it is the default.
+	SkylarkQuery QueryEncoder = func(params url.Values, filters []QueryFilter) string {
+		v := url.Values{}
+		for key, values := range params {
+			v[key] = append([]string(nil), values...)
+		}
+		for _, f := range filters {
+			key := strings.Join(f.Path, "__")
+			if f.Constraint != "" {
+				key += "__" + f.Constraint
+			}
+			v.Add(key, f.Value)
+		}
+		return v.Encode()
+	}
+	// BracketQuery encodes filters as bracketed nested parameters like filter[season][gte]=2020,
+	// as expected by some gateways in front of Skylark. Other parameters are encoded unchanged.
+	BracketQuery QueryEncoder = func(params url.Values, filters []QueryFilter) string {
+		var pairs []string
+		for key, values := range params {
+			for _, value := range values {
+				pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(value))
+			}
+		}
+		for _, f := range filters {
+			var key strings.Builder
+			key.WriteString("filter")
+			for _, segment := range f.Path {
+				key.WriteString("[" + url.QueryEscape(segment) + "]")
+			}
+			if f.Constraint != "" {
+				key.WriteString("[" + url.QueryEscape(f.Constraint) + "]")
+			}
+			pairs = append(pairs, key.String()+"="+url.QueryEscape(f.Value))
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, "&")
+	}
+)
+
+// WithQueryEncoder sets how the client encodes the query string of requests, so the same requests can target
+// gateways that expect a different format than Skylark. Requests converted with ToURL use SkylarkQuery.
+func WithQueryEncoder(enc QueryEncoder) Option {
+	return func(s *settings) {
+		s.queryEncoder = enc
+	}
+}
+
+// encodeQuery encodes the query of a request whose Skylark parameters are q with the client's QueryEncoder.
+func (s *settings) encodeQuery(r *Request, q url.Values) string {
+	if s.queryEncoder == nil {
+		return q.Encode()
+	}
+	filters := r.queryFilters()
+	params := url.Values{}
+	for key, values := range q {
+		params[key] = values
+	}
+	for _, f := range filters {
+		key := strings.Join(f.Path, "__")
+		if f.Constraint != "" {
+			key += "__" + f.Constraint
+		}
+		delete(params, key)
+	}
+	return s.queryEncoder(params, filters)
+}
+
+// queryFilters returns the filters of the request and its fields.
+func (r *Request) queryFilters() []QueryFilter {
+	var filters []QueryFilter
+	add := func(field string, f *Filter) {
+		filters = append(filters, QueryFilter{
+			Path:       strings.Split(field, "__"),
+			Constraint: string(f.c),
+			Value:      f.encode(r.timeEncoder),
+		})
+	}
+	var walk func(*Field)
+	walk = func(field *Field) {
+		for _, filter := range field.filters {
+			for _, term := range filter.terms() {
+				add(field.Name, term)
+			}
+		}
+		for _, sub := range field.SubFields {
+			walk(sub)
+		}
+	}
+	names := make([]string, 0, len(r.Fields))
+	for name := range r.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		walk(r.Fields[name])
+	}
+	for _, f := range r.filters {
+		add(f.field, f.filter)
+	}
+	return filters
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBracketQuery(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithQueryEncoder(BracketQuery))
+	team := NewField("team").WithSubField(NewField("name").WithFilter(NewFilter(Equals, "Red Bull")))
+	err := c.NewRequest("races", "").
+		AddField(team).
+		WithFilter("season", NewRangeFilter(2020, 2021)).
+		Limit(5).
+		Execute(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "fields=team%2Cteam__name&fields_to_expand=team&filter[season][gte]=2020&filter[season][lte]=2021&filter[team][name]=Red+Bull&limit=5"
+	if query != expected {
+		t.Errorf("expected query %s, got %s", expected, query)
+	}
+
+	c = NewClient(WithBaseURL(server.URL), WithQueryEncoder(SkylarkQuery))
+	if err := c.NewRequest("races", "").WithFilter("season", NewFilter(GreaterThanOrEqual, "2020")).Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if query != "season__gte=2020" {
+		t.Error("unexpected query", query)
+	}
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the client to qps requests per second on average, allowing bursts of up to burst requests.
+// Every HTTP request, including retries, waits for the limiter or until its context is done.
+// A 429 Too Many Requests response empties the bucket and pauses all requests for its Retry-After delay,
+// or for one request interval if it has none. A qps of zero or less removes the limit.
+func WithRateLimit(qps float64, burst int) Option {
+	return func(s *settings) {
+		if qps <= 0 {
+			s.limiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		s.limiter = &rateLimiter{rate: qps, burst: float64(burst), tokens: float64(burst)}
+	}
+}
+
+// rateLimiter is a token bucket shared by all configurations of a client.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	// paused is when requests may be sent again after a 429 response.
+	paused time.Time
+}
+
+// refill adds the tokens accrued since the last call, it must be called with mu held.
+func (l *rateLimiter) refill(now time.Time) {
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+}
+
+// reserve takes a token and returns how long to wait before using it.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(now)
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if pause := l.paused.Sub(now); pause > wait {
+		wait = pause
+	}
+	return wait
+}
+
+// cancel returns a reserved token that was not used.
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = math.Min(l.burst, l.tokens+1)
+}
+
+// throttled empties the bucket and pauses requests for the delay after a 429 response.
+func (l *rateLimiter) throttled(now time.Time, delay time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(now)
+	if delay <= 0 {
+		delay = time.Duration(float64(time.Second) / l.rate)
+	}
+	if until := now.Add(delay); until.After(l.paused) {
+		l.paused = until
+	}
+	if l.tokens > 0 {
+		l.tokens = 0
+	}
+}
+
+// limited waits for the rate limiter before sending each request and feeds 429 responses back into it.
+func (s *settings) limited(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if err := s.waitForLimiter(req.Context()); err != nil {
+			return nil, err
+		}
+		res, err := next(req)
+		if err == nil && res.StatusCode == http.StatusTooManyRequests {
+			now := s.now()
+			s.limiter.throttled(now, parseRetryAfter(res.Header.Get("Retry-After"), now))
+		}
+		return res, err
+	}
+}
+
+func (s *settings) waitForLimiter(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	wait := s.limiter.reserve(s.now())
+	if wait <= 0 {
+		return nil
+	}
+	if err := s.sleep(ctx, wait); err != nil {
+		s.limiter.cancel()
+		return err
+	}
+	return nil
+}
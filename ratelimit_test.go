@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	limited := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if limited {
+			limited = false
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	c := NewClient(WithBaseURL(server.URL), WithClock(clock), WithSleeper(clock), WithRateLimit(10, 2))
+	for i := 0; i < 5; i++ {
+		if err := c.NewRequest("episodes", "").Execute(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(clock.sleeps) != 3 {
+		t.Fatal("expected the requests after the burst to wait, got", clock.sleeps)
+	}
+	for _, d := range clock.sleeps {
+		if d != 100*time.Millisecond {
+			t.Error("unexpected wait", d)
+		}
+	}
+
+	// a 429 pauses the limiter for its Retry-After delay
+	clock.sleeps = nil
+	limited = true
+	if err := c.NewRequest("episodes", "").Execute(nil); !IsRateLimited(err) {
+		t.Fatal("expected rate limit error, got", err)
+	}
+	if err := c.NewRequest("episodes", "").Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(clock.sleeps) != 2 || clock.sleeps[1] != 3*time.Second {
+		t.Error("limiter did not pause after 429", clock.sleeps)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.NewRequest("episodes", "").WithContext(ctx).Execute(nil); !errors.Is(err, context.Canceled) {
+		t.Error("expected context error, got", err)
+	}
+
+	e, err := c.NewRequest("episodes", "").Explain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.RateLimit != 10 {
+		t.Error("explanation does not report the rate limit", e.RateLimit)
+	}
+}
@@ -0,0 +1,63 @@
+package client
+
+import "encoding/json"
+
+// RedactedValue replaces the values of redacted fields.
+const RedactedValue = "[REDACTED]"
+
+// WithRedactedFields sets JSON fields whose values are replaced with RedactedValue
+// in response bodies before they end up in errors, logs or dumps.
+// Fields are matched by name at any depth.
+func WithRedactedFields(fields ...string) Option {
+	return func(s *settings) {
+		if s.redacted == nil {
+			s.redacted = make(map[string]bool)
+		}
+		for _, field := range fields {
+			s.redacted[field] = true
+		}
+	}
+}
+
+// Redact returns the JSON body with the values of the given fields replaced by RedactedValue.
+// Bodies that are not valid JSON are returned unchanged.
+func Redact(body []byte, fields ...string) []byte {
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[field] = true
+	}
+	return redact(body, set)
+}
+
+func redact(body []byte, fields map[string]bool) []byte {
+	if len(fields) == 0 {
+		return body
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	redacted, err := json.Marshal(redactValue(v, fields))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func redactValue(v interface{}, fields map[string]bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if fields[key] {
+				v[key] = RedactedValue
+			} else {
+				v[key] = redactValue(value, fields)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value, fields)
+		}
+	}
+	return v
+}
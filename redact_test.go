@@ -0,0 +1,39 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	body := []byte(`{"email": "a@b.c", "user": {"token": "secret", "name": "A"}, "items": [{"email": "d@e.f"}]}`)
+
+	var actual, expected interface{}
+	json.Unmarshal(Redact(body, "email", "token"), &actual)
+	json.Unmarshal([]byte(`{"email": "[REDACTED]", "user": {"token": "[REDACTED]", "name": "A"}, "items": [{"email": "[REDACTED]"}]}`), &expected)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("incorrect redaction\nexpected: %v\ngot:      %v", expected, actual)
+	}
+
+	if string(Redact([]byte("not json"), "email")) != "not json" {
+		t.Error("invalid JSON should be returned unchanged")
+	}
+}
+
+func TestRedactedErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"detail": "invalid", "token": "secret"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithRedactedFields("token"))
+	err := c.NewRequest("sets", "").Execute(&struct{}{})
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Error("error should not contain redacted value:", err)
+	}
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrTooManyRedirects is returned when a request is redirected more often than the redirect policy allows.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// defaultMaxRedirects is the limit of the net/http client.
+const defaultMaxRedirects = 10
+
+// RedirectPolicy controls how redirect responses are followed.
+type RedirectPolicy struct {
+	// Disabled returns redirect responses instead of following them, they fail with an APIError.
+	Disabled bool
+	// MaxRedirects is the number of redirects followed per request, it defaults to 10.
+	MaxRedirects int
+	// StripCredentials removes the authentication, masked and secret headers and masked query parameters
+	// when a redirect leads to a different host.
+	StripCredentials bool
+}
+
+// WithRedirectPolicy sets how redirects are followed, by default they are followed like by http.DefaultClient.
+func WithRedirectPolicy(p RedirectPolicy) Option {
+	return func(s *settings) {
+		s.redirect = &p
+	}
+}
+
+// checkRedirect applies the client's redirect policy, see http.Client.CheckRedirect.
+func (s *settings) checkRedirect(req *http.Request, via []*http.Request) error {
+	p := s.redirect
+	if p.Disabled {
+		return http.ErrUseLastResponse
+	}
+	max := p.MaxRedirects
+	if max <= 0 {
+		max = defaultMaxRedirects
+	}
+	if len(via) > max {
+		return fmt.Errorf("%w: stopped after %d", ErrTooManyRedirects, max)
+	}
+	if p.StripCredentials && !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		s.stripCredentials(req)
+	}
+	return nil
+}
+
+// stripCredentials removes credentials from a request that is redirected to another host.
+func (s *settings) stripCredentials(req *http.Request) {
+	headers := append([]string{"Cookie", "Proxy-Authorization"}, defaultMaskedHeaders...)
+	headers = append(headers, s.maskedHeaders...)
+	for _, h := range s.secretHeaders {
+		headers = append(headers, h.key)
+	}
+	for _, name := range headers {
+		req.Header.Del(name)
+	}
+	if len(s.maskedParams) > 0 && req.URL.RawQuery != "" {
+		q := req.URL.Query()
+		for _, name := range s.maskedParams {
+			q.Del(name)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedirectPolicy(t *testing.T) {
+	var leaked string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = r.Header.Get("Authorization") + r.Header.Get("X-Secret")
+		w.Write([]byte(`{}`))
+	}))
+	defer other.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/loop/":
+			http.Redirect(w, r, "/loop/", http.StatusFound)
+		case strings.HasPrefix(r.URL.Path, "/moved/"):
+			http.Redirect(w, r, other.URL+"/sets/", http.StatusFound)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithTokenAuth("secret"), WithHeader("X-Secret", "value"), WithMaskedHeaders("X-Secret"))
+	if err := c.Do(c.NewRequest("moved", ""), nil); err != nil {
+		t.Fatal(err)
+	}
+	if leaked == "" {
+		t.Fatal("expected the default client to forward the custom header")
+	}
+
+	c.UpdateConfig(WithRedirectPolicy(RedirectPolicy{StripCredentials: true, MaxRedirects: 3}))
+	leaked = ""
+	if err := c.Do(c.NewRequest("moved", ""), nil); err != nil {
+		t.Fatal(err)
+	}
+	if leaked != "" {
+		t.Error("credentials were sent to another host", leaked)
+	}
+	if err := c.Do(c.NewRequest("loop", ""), nil); !errors.Is(err, ErrTooManyRedirects) {
+		t.Error("expected ErrTooManyRedirects", err)
+	}
+
+	c.UpdateConfig(WithRedirectPolicy(RedirectPolicy{Disabled: true}))
+	var apiErr *APIError
+	if err := c.Do(c.NewRequest("moved", ""), nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusFound {
+		t.Error("expected the redirect response", err)
+	}
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Ref is a typed reference to another object that is fetched on first use.
+// It decodes from a self URL, or from an expanded object, in which case no fetch is necessary.
+// Copies of a decoded reference or one created with NewRef share the fetched object,
+// so structs holding references can be passed by value. Literals like Ref{Self: url} have no shared state,
+// they fetch the object on every call to Resolve.
+//
+//	type Episode struct {
+//		Image client.Ref[Image] `json:"image_url"`
+//	}
+type Ref[T any] struct {
+	// Self is the self URL of the referenced object.
+	Self string
+
+	state *refState[T]
+}
+
+// refState is the fetched object of a reference.
+type refState[T any] struct {
+	mu sync.Mutex
+	// self is the URL value was fetched for, the reference is unresolved if its Self was changed since.
+	self     string
+	resolved bool
+	value    T
+}
+
+// NewRef returns a reference to the object with the given self URL.
+func NewRef[T any](self string) Ref[T] {
+	return Ref[T]{Self: self, state: &refState[T]{}}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *Ref[T]) UnmarshalJSON(data []byte) error {
+	state := &refState[T]{}
+	if bytes.Equal(data, []byte("null")) {
+		r.Self, r.state = "", state
+		return nil
+	}
+	if len(data) > 0 && data[0] == '{' {
+		var meta struct {
+			Self string `json:"self"`
+		}
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &state.value); err != nil {
+			return err
+		}
+		state.self, state.resolved = meta.Self, true
+		r.Self, r.state = meta.Self, state
+		return nil
+	}
+	if err := json.Unmarshal(data, &r.Self); err != nil {
+		return err
+	}
+	r.state = state
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, references are encoded as their self URL.
+func (r Ref[T]) MarshalJSON() ([]byte, error) {
+	if r.Self == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(r.Self)
+}
+
+// Resolve returns the referenced object, fetching it with the default client on first use.
+func (r *Ref[T]) Resolve(ctx context.Context) (T, error) {
+	return r.ResolveWith(ctx, DefaultClient())
+}
+
+// ResolveWith returns the referenced object, fetching it with the given client on first use.
+// Fetches go through the client's object cache if it has one.
+func (r *Ref[T]) ResolveWith(ctx context.Context, c *Client) (T, error) {
+	state := r.state
+	if state == nil {
+		// the fetched object can't be kept without state
+		state = &refState[T]{}
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.resolved && state.self == r.Self {
+		return state.value, nil
+	}
+	var zero T
+	if r.Self == "" {
+		return zero, fmt.Errorf("unable to resolve empty reference")
+	}
+	object, err := c.resolveRef(ctx, r.Self)
+	if err != nil {
+		return zero, err
+	}
+	var value T
+	if err := json.Unmarshal(object, &value); err != nil {
+		return zero, err
+	}
+	state.self, state.resolved, state.value = r.Self, true, value
+	return value, nil
+}
+
+// Resolved reports whether the referenced object was already fetched or expanded.
+func (r *Ref[T]) Resolved() bool {
+	if r.state == nil {
+		return false
+	}
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	return r.state.resolved && r.state.self == r.Self
+}
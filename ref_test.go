@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRef(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		w.Write([]byte(`{"uid": "img_1", "url": "a.png"}`))
+	}))
+	defer server.Close()
+
+	type image struct {
+		URL string `json:"url"`
+	}
+	var ep struct {
+		Image    Ref[image] `json:"image_url"`
+		Expanded Ref[image] `json:"expanded_url"`
+	}
+	data := `{"image_url": "/api/images/img_1/", "expanded_url": {"self": "/api/images/img_2/", "url": "b.png"}}`
+	if err := json.Unmarshal([]byte(data), &ep); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(WithBaseURL(server.URL))
+	for i := 0; i < 2; i++ {
+		img, err := ep.Image.ResolveWith(context.Background(), c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.URL != "a.png" {
+			t.Error("incorrect image", img)
+		}
+	}
+	if fetches != 1 {
+		t.Error("reference should be fetched once, got", fetches)
+	}
+
+	if !ep.Expanded.Resolved() || ep.Expanded.Self != "/api/images/img_2/" {
+		t.Error("expanded reference should be resolved")
+	}
+
+	encoded, _ := json.Marshal(&ep.Expanded)
+	if string(encoded) != `"/api/images/img_2/"` {
+		t.Error("incorrect encoding", string(encoded))
+	}
+	// structs holding references encode the same by value, and copies share fetched objects
+	copied := ep
+	encoded, _ = json.Marshal(copied)
+	expected := `{"image_url":"/api/images/img_1/","expanded_url":"/api/images/img_2/"}`
+	if string(encoded) != expected {
+		t.Error("incorrect encoding by value", string(encoded))
+	}
+	if !copied.Image.Resolved() {
+		t.Error("copy should share the fetched object")
+	}
+
+	literal := Ref[image]{Self: "/api/images/img_3/"}
+	if img, err := literal.ResolveWith(context.Background(), c); err != nil || img.URL != "a.png" {
+		t.Error("unable to resolve reference that was not decoded", img, err)
+	}
+
+	created := NewRef[image]("/api/images/img_3/")
+	shared := created
+	if img, err := created.ResolveWith(context.Background(), c); err != nil || img.URL != "a.png" {
+		t.Error("unable to resolve created reference", img, err)
+	}
+	if !shared.Resolved() {
+		t.Error("copy made before the first use should share the fetched object")
+	}
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrConflict is returned when an object keeps being modified by someone else while it is being updated.
+var ErrConflict = errors.New("object was modified concurrently")
+
+// relationshipAttempts is how often the fetch-modify-write cycle is repeated on conflicts.
+const relationshipAttempts = 3
+
+// UpdateRelationship fetches the relationship field of an object, passes its self URLs to modify
+// and patches the object with the result.
+// The patch is sent with an If-Unmodified-Since precondition for the fetched modified timestamp.
+// If the server rejects the precondition with 412 Precondition Failed, the cycle is repeated
+// and ErrConflict is returned once it keeps failing.
+func (c *Client) UpdateRelationship(ctx context.Context, collection, id, field string, modify func(urls []string) ([]string, error)) error {
+	for attempt := 0; attempt < relationshipAttempts; attempt++ {
+		var object map[string]json.RawMessage
+		err := c.NewRequest(collection, id).
+			WithContext(ctx).
+			AddField(NewField(field)).
+			AddField(NewField("modified")).
+			Execute(&object)
+		if err != nil {
+			return err
+		}
+
+		var urls []string
+		if raw, ok := object[field]; ok {
+			if err := json.Unmarshal(raw, &urls); err != nil {
+				return fmt.Errorf("%s is not a relationship field: %w", field, err)
+			}
+		}
+		urls, err = modify(urls)
+		if err != nil {
+			return err
+		}
+
+		patch := c.NewRequest(collection, id).WithContext(ctx)
+		var modified string
+		if json.Unmarshal(object["modified"], &modified) == nil {
+			if t, err := time.Parse(time.RFC3339Nano, modified); err == nil {
+				patch.Finalize(func(req *http.Request) error {
+					req.Header.Set("If-Unmodified-Since", t.UTC().Format(http.TimeFormat))
+					return nil
+				})
+			}
+		}
+		err = c.send(patch, http.MethodPatch, map[string][]string{field: urls}, nil)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusPreconditionFailed {
+			continue
+		}
+		return err
+	}
+	return ErrConflict
+}
+
+// AddToRelationship appends the self URLs to the relationship field, skipping ones that are already present.
+func (c *Client) AddToRelationship(ctx context.Context, collection, id, field string, urls ...string) error {
+	return c.UpdateRelationship(ctx, collection, id, field, func(current []string) ([]string, error) {
+		present := make(map[string]bool, len(current))
+		for _, u := range current {
+			present[u] = true
+		}
+		for _, u := range urls {
+			if !present[u] {
+				current = append(current, u)
+				present[u] = true
+			}
+		}
+		return current, nil
+	})
+}
+
+// RemoveFromRelationship removes the self URLs from the relationship field.
+func (c *Client) RemoveFromRelationship(ctx context.Context, collection, id, field string, urls ...string) error {
+	return c.UpdateRelationship(ctx, collection, id, field, func(current []string) ([]string, error) {
+		remove := make(map[string]bool, len(urls))
+		for _, u := range urls {
+			remove[u] = true
+		}
+		kept := current[:0]
+		for _, u := range current {
+			if !remove[u] {
+				kept = append(kept, u)
+			}
+		}
+		return kept, nil
+	})
+}
+
+// ReorderRelationship sets the order of the relationship field.
+// The given self URLs must be exactly the ones currently in the field.
+func (c *Client) ReorderRelationship(ctx context.Context, collection, id, field string, urls []string) error {
+	return c.UpdateRelationship(ctx, collection, id, field, func(current []string) ([]string, error) {
+		counts := make(map[string]int, len(current))
+		for _, u := range current {
+			counts[u]++
+		}
+		for _, u := range urls {
+			counts[u]--
+		}
+		for u, count := range counts {
+			if count != 0 {
+				return nil, fmt.Errorf("reorder of %s does not match its current items: %s", field, u)
+			}
+		}
+		return urls, nil
+	})
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRelationshipHelpers(t *testing.T) {
+	items := []string{"/api/episodes/ep_1/", "/api/episodes/ep_2/"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			var body map[string][]string
+			json.NewDecoder(r.Body).Decode(&body)
+			items = body["item_urls"]
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"item_urls": items, "modified": "2020-01-01T00:00:00Z"})
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	if err := c.AddToRelationship(ctx, "sets", "set_123", "item_urls", "/api/episodes/ep_3/", "/api/episodes/ep_1/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RemoveFromRelationship(ctx, "sets", "set_123", "item_urls", "/api/episodes/ep_2/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReorderRelationship(ctx, "sets", "set_123", "item_urls", []string{"/api/episodes/ep_3/", "/api/episodes/ep_1/"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"/api/episodes/ep_3/", "/api/episodes/ep_1/"}
+	if !reflect.DeepEqual(items, expected) {
+		t.Errorf("incorrect relationship\nexpected: %v\ngot:      %v", expected, items)
+	}
+
+	if err := c.ReorderRelationship(ctx, "sets", "set_123", "item_urls", []string{"/api/episodes/ep_3/"}); err == nil {
+		t.Error("expected error when reorder drops items")
+	}
+}
+
+func TestUpdateRelationshipConflict(t *testing.T) {
+	modified := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	items := []string{"/api/episodes/ep_1/"}
+	// concurrent is the number of writes by someone else, each one happens right after a read
+	var reads, patches, concurrent int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			patches++
+			since, err := http.ParseTime(r.Header.Get("If-Unmodified-Since"))
+			if err != nil || modified.After(since) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			var body map[string][]string
+			json.NewDecoder(r.Body).Decode(&body)
+			items = body["item_urls"]
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		reads++
+		json.NewEncoder(w).Encode(map[string]interface{}{"item_urls": items, "modified": modified.Format(time.RFC3339Nano)})
+		if concurrent > 0 {
+			concurrent--
+			modified = modified.Add(time.Minute)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+	concurrent = 1
+	if err := c.AddToRelationship(ctx, "sets", "set_123", "item_urls", "/api/episodes/ep_2/"); err != nil {
+		t.Fatal(err)
+	}
+	if patches != 2 || reads != 2 || len(items) != 2 {
+		t.Error("expected the patch to be retried after the precondition failed", patches, reads, items)
+	}
+
+	concurrent = relationshipAttempts
+	if err := c.AddToRelationship(ctx, "sets", "set_123", "item_urls", "/api/episodes/ep_3/"); !errors.Is(err, ErrConflict) {
+		t.Error("expected ErrConflict, got", err)
+	}
+}
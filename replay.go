@@ -0,0 +1,155 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RecordedRequest is a request, and optionally its response, captured for replay.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	// URL is the absolute URL the request was sent to.
+	URL  string          `json:"url"`
+	Body json.RawMessage `json:"body,omitempty"`
+	// Status and Response are compared to the replayed response if they are set.
+	Status   int             `json:"status,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// ReplayOptions configures Replay.
+type ReplayOptions struct {
+	// SourceEndpoint is the endpoint the requests were recorded against.
+	// It is replaced with the endpoint of the replaying client.
+	SourceEndpoint string
+	// AllowWrites replays requests with methods other than GET and HEAD, which are skipped by default.
+	AllowWrites bool
+}
+
+// ReplayResult is the outcome of replaying a single request.
+type ReplayResult struct {
+	Recorded RecordedRequest
+	Status   int
+	// Response is the raw body of the replayed response, also for error statuses.
+	Response json.RawMessage
+	// Changes lists the differences to the recorded response.
+	Changes []FieldChange
+	// Skipped is true for writes that were not replayed.
+	Skipped bool
+	// Err is set if the request failed or the responses could not be compared.
+	Err error
+}
+
+// Mismatch reports whether the replayed response differs from the recorded one.
+func (r ReplayResult) Mismatch() bool {
+	return r.Err == nil && !r.Skipped &&
+		((r.Recorded.Status != 0 && r.Recorded.Status != r.Status) || len(r.Changes) > 0)
+}
+
+// ReadRecordedRequests reads newline delimited JSON records, for example an audit log written by a JSONAuditSink.
+func ReadRecordedRequests(rd io.Reader) ([]RecordedRequest, error) {
+	var records []RecordedRequest
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record RecordedRequest
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid record on line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// Replay re-executes recorded requests with the client, for example against a staging environment
+// to reproduce an incident, and compares the responses to the recorded ones.
+func (c *Client) Replay(ctx context.Context, records []RecordedRequest, opts ReplayOptions) []ReplayResult {
+	results := make([]ReplayResult, len(records))
+	for i, record := range records {
+		results[i] = c.replay(ctx, record, opts)
+	}
+	return results
+}
+
+func (c *Client) replay(ctx context.Context, record RecordedRequest, opts ReplayOptions) ReplayResult {
+	result := ReplayResult{Recorded: record}
+	method := record.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	if method != http.MethodGet && method != http.MethodHead && !opts.AllowWrites {
+		result.Skipped = true
+		return result
+	}
+
+	s := c.current()
+	if s.err != nil {
+		result.Err = s.err
+		return result
+	}
+	target := record.URL
+	if opts.SourceEndpoint != "" {
+		source, err := normalizeEndpoint(opts.SourceEndpoint)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		if !strings.HasPrefix(target, source) {
+			result.Err = fmt.Errorf("%s was not recorded against %s", record.URL, source)
+			return result
+		}
+		if s.endpoint == "" {
+			result.Err = fmt.Errorf("%w: no endpoint configured", ErrInvalidEndpoint)
+			return result
+		}
+		target = s.endpoint + strings.TrimPrefix(target, source)
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	var payload []byte
+	if len(record.Body) > 0 {
+		payload = record.Body
+	}
+	// the raw response is compared, errors are expected outcomes and their bodies are not redacted or masked
+	req, err := s.newHTTPRequest(&call{ctx: ctx, method: method, url: u, payload: payload, contentType: jsonContentType})
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	res, err := s.roundTrip(req)
+	if err != nil {
+		result.Err = mask(err, s.sensitiveValues(req))
+		return result
+	}
+	defer res.Body.Close()
+	body, err := s.responseBody(res)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if result.Response, err = ioutil.ReadAll(body); err != nil {
+		result.Err = err
+		return result
+	}
+	result.Status = res.StatusCode
+	if len(record.Response) > 0 && len(result.Response) > 0 && !bytes.Equal(record.Response, result.Response) {
+		if result.Changes, err = Diff(record.Response, result.Response); err != nil {
+			result.Err = fmt.Errorf("comparing responses: %w", err)
+		}
+	}
+	return result
+}
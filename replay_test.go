@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/sets/set_2/":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case "/api/sets/set_3/":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "set_3 not found"}`))
+			return
+		case "/api/sets/set_4/":
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`bad gateway`))
+			return
+		}
+		w.Write([]byte(`{"title": "Staging"}`))
+	}))
+	defer server.Close()
+
+	log := `{"method": "GET", "url": "https://prod.test/api/sets/set_1/?fields=title", "status": 200, "response": {"title": "Prod"}}
+{"method": "GET", "url": "https://prod.test/api/sets/set_2/", "status": 200}
+{"method": "DELETE", "url": "https://prod.test/api/sets/set_1/"}
+{"method": "GET", "url": "https://prod.test/api/sets/set_3/", "status": 404, "response": {"error": "set_3 not found"}}
+{"method": "GET", "url": "https://prod.test/api/sets/set_4/", "status": 502, "response": {"error": "timeout"}}
+`
+	records, err := ReadRecordedRequests(strings.NewReader(log))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(WithBaseURL(server.URL + "/api/"))
+	results := c.Replay(context.Background(), records, ReplayOptions{SourceEndpoint: "https://prod.test/api/"})
+	if len(results) != 5 {
+		t.Fatal("incorrect number of results", len(results))
+	}
+
+	if !results[0].Mismatch() || len(results[0].Changes) != 1 || results[0].Changes[0].New != "Staging" {
+		t.Error("expected changed title", results[0].Changes, results[0].Err)
+	}
+	if !results[1].Mismatch() || results[1].Status != http.StatusNotFound {
+		t.Error("expected status mismatch", results[1].Status, results[1].Err)
+	}
+	if !results[2].Skipped {
+		t.Error("writes should be skipped by default")
+	}
+	if results[3].Mismatch() || results[3].Err != nil || string(results[3].Response) != `{"error": "set_3 not found"}` {
+		t.Error("expected matching error response", string(results[3].Response), results[3].Changes, results[3].Err)
+	}
+	if results[4].Err == nil {
+		t.Error("expected an error comparing a response that is not JSON")
+	}
+}
@@ -2,12 +2,12 @@ package client
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 // Request represents a Skylark API request
@@ -18,18 +18,105 @@ type Request struct {
 	Fields           map[string]*Field
 	ctx              context.Context
 	additionalFields map[string]string
+	// filters are the filters added with WithFilter in order, a field can have several.
+	filters        []*filterParam
+	client         *Client
+	err            error
+	experimental   []experimentalParam
+	finalizers     []func(*http.Request) error
+	pathParams     map[string]string
+	dryRun         bool
+	uploadProgress func(sent, total int64)
+	progress       ProgressFunc
+	skipCache      bool
+	rawFields      bool
+	debug          io.Writer
+	tags           map[string]string
+	// ifModifiedSince is sent as If-Modified-Since header if it is set.
+	ifModifiedSince time.Time
+	postProcessors  []PostProcessor
+	// dedupe skips objects of collection responses it has seen, see Deduplicate.
+	dedupe *Deduplicator
+	// times holds the parameters in additionalFields that were set from times, keyed by parameter.
+	times map[string]time.Time
+	// timeEncoder encodes times, the default format is used if it is nil.
+	timeEncoder TimeEncoder
+	// memo is the URL memoized by ToURL, it is nil for requests that are not memoized.
+	memo *urlMemo
+	// path replaces the <collection>/<id>/ part of the URL if set
+	path string
+	// proxy is the proxy set with WithProxy, it overrides the proxy of the context.
+	proxy *url.URL
 }
 
 // NewRequest returns a simple request with the given
+// The endpoint must be an absolute http(s) URL, a missing trailing slash is added.
+// If the endpoint is invalid ToURL and Execute return an error wrapping ErrInvalidEndpoint.
 func NewRequest(endpoint, collection, id string) *Request {
-	return &Request{
-		Collection: collection, Endpoint: endpoint, Fields: make(map[string]*Field), additionalFields: make(map[string]string), ID: id, ctx: context.Background()}
+	r := &Request{
+		Collection: collection, Fields: make(map[string]*Field), additionalFields: make(map[string]string), ID: id, ctx: context.Background(), memo: &urlMemo{}}
+	if endpoint != "" {
+		r.Endpoint, r.err = normalizeEndpoint(endpoint)
+	}
+	return r
+}
+
+// copy returns a shallow copy of the request whose filters and parameters can be changed without affecting r.
+func (r *Request) copy() *Request {
+	c := *r
+	c.memo = &urlMemo{}
+	c.Fields = make(map[string]*Field, len(r.Fields))
+	for name, f := range r.Fields {
+		c.Fields[name] = f
+	}
+	c.additionalFields = make(map[string]string, len(r.additionalFields))
+	for key, value := range r.additionalFields {
+		c.additionalFields[key] = value
+	}
+	c.filters = append([]*filterParam(nil), r.filters...)
+	c.experimental = append([]experimentalParam(nil), r.experimental...)
+	c.finalizers = append([]func(*http.Request) error(nil), r.finalizers...)
+	c.postProcessors = append([]PostProcessor(nil), r.postProcessors...)
+	c.pathParams = make(map[string]string, len(r.pathParams))
+	for name, value := range r.pathParams {
+		c.pathParams[name] = value
+	}
+	if r.tags != nil {
+		c.tags = make(map[string]string, len(r.tags))
+		for key, value := range r.tags {
+			c.tags[key] = value
+		}
+	}
+	c.times = make(map[string]time.Time, len(r.times))
+	for key, t := range r.times {
+		c.times[key] = t
+	}
+	return &c
+}
+
+// Clone returns a deep copy of the request including its fields and filters.
+// Builder methods called on the copy never affect the original and the other way around,
+// so a base request can be cloned and extended concurrently, for example once per goroutine.
+// Builder methods must not be called concurrently on the same request.
+func (r *Request) Clone() *Request {
+	c := r.copy()
+	for name, f := range c.Fields {
+		c.Fields[name] = f.clone()
+	}
+	return c
 }
 
 // AddField adds a field to the request.
 // If a request has fields specified it will only return those fields.
 func (r *Request) AddField(f *Field) *Request {
+	if err := fieldConflict(r.Fields[f.Name], f); err != nil && r.err == nil {
+		r.err = err
+	}
 	r.Fields[f.Name] = f
+	r.invalidate()
+	if err := f.filterErr(); err != nil && r.err == nil {
+		r.err = err
+	}
 	return r
 }
 
@@ -37,84 +124,215 @@ func (r *Request) AddField(f *Field) *Request {
 func (r *Request) QueryParams() url.Values {
 	v := url.Values{}
 	for _, field := range r.Fields {
-		v = field.apply(v)
+		v = field.apply(v, r.timeEncoder)
 	}
 	for key, value := range r.additionalFields {
+		if t, ok := r.times[key]; ok && r.timeEncoder != nil {
+			value = r.timeEncoder(t)
+		}
 		v.Add(key, value)
 	}
+	for _, f := range r.filters {
+		v.Add(f.key, f.filter.encode(r.timeEncoder))
+	}
 	return v
 }
 
 // ToURL converts the request into a url.URL
+// The URL is memoized until the request or one of its fields is changed, changes of a field's exported
+// attributes after it was added need another call of AddField.
+// It is safe to call ToURL concurrently.
 func (r *Request) ToURL() (*url.URL, error) {
-	temp := r.Endpoint + r.Collection + "/"
-	if r.ID != "" {
-		temp += r.ID + "/"
+	if r.err != nil {
+		return nil, r.err
 	}
-	queryParams := r.QueryParams().Encode()
-	if queryParams != "" {
-		temp += "?" + queryParams
+	if err := r.unbound(); err != nil {
+		return nil, err
 	}
-	return url.Parse(temp)
+	if err := r.orderConflict(); err != nil {
+		return nil, err
+	}
+	if r.memo != nil {
+		return r.memo.memoized(r.memoKey(), r.buildURL)
+	}
+	return r.buildURL()
+}
+
+func (r *Request) buildURL() (*url.URL, error) {
+	path, err := r.escapedPath()
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(r.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidEndpoint, r.Endpoint, err)
+	}
+	path = u.EscapedPath() + path
+	if u.Path, err = url.PathUnescape(path); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidPath, path, err)
+	}
+	u.RawPath = path
+	u.RawQuery = r.QueryParams().Encode()
+	return u, nil
+}
+
+// escapedPath returns the escaped path of the request relative to its endpoint.
+func (r *Request) escapedPath() (string, error) {
+	if r.path != "" {
+		// the ID may not be part of the template, but must not be able to change the path if it is
+		if r.ID != "" {
+			if err := checkSegment("ID", r.ID); err != nil {
+				return "", err
+			}
+		}
+		return r.path, nil
+	}
+	if err := checkSegment("collection", r.Collection); err != nil {
+		return "", err
+	}
+	path := url.PathEscape(r.Collection) + "/"
+	if r.ID != "" {
+		if err := checkSegment("ID", r.ID); err != nil {
+			return "", err
+		}
+		path += url.PathEscape(r.ID) + "/"
+	}
+	return path, nil
+}
+
+// WithEndpoint overrides the client's endpoint for this request, for example to query a different environment.
+// The request is still executed with the client's headers, authentication and other settings.
+// If the endpoint is invalid Execute returns an error wrapping ErrInvalidEndpoint.
+func (r *Request) WithEndpoint(endpoint string) *Request {
+	r.Endpoint, r.err = normalizeEndpoint(endpoint)
+	r.invalidate()
+	return r
 }
 
 // WithContext set's the context the request will be executed with.
-// Panics on nil context
+// Panics on nil context, unless the request's client was created WithoutPanics.
 func (r *Request) WithContext(ctx context.Context) *Request {
 	if ctx == nil {
-		panic("nil context")
+		if !r.executor().current().noPanics {
+			panic("nil context")
+		}
+		if r.err == nil {
+			r.err = ErrNilContext
+		}
+		return r
 	}
 	r.ctx = ctx
 	return r
 }
 
-// OrderBy sorts the response by the given field
+// OrderBy sorts the response by the given field in ascending order.
+// Calling it again adds further fields that order objects the previous ones consider equal,
+// ordering by a field that is already ordered by changes its direction.
 func (r *Request) OrderBy(f *Field) *Request {
-	r.additionalFields["order"] = f.Name
+	return r.addOrder(f.Name)
+}
+
+// OrderByDesc sorts the response by the given field in descending order, see OrderBy.
+func (r *Request) OrderByDesc(f *Field) *Request {
+	return r.addOrder("-" + f.Name)
+}
+
+func (r *Request) addOrder(key string) *Request {
+	name := strings.TrimPrefix(key, "-")
+	var order []string
+	replaced := false
+	if current := r.additionalFields["order"]; current != "" {
+		order = strings.Split(current, ",")
+	}
+	for i, existing := range order {
+		if strings.TrimPrefix(existing, "-") == name {
+			order[i] = key
+			replaced = true
+		}
+	}
+	if !replaced {
+		order = append(order, key)
+	}
+	r.additionalFields["order"] = strings.Join(order, ",")
+	r.invalidate()
 	return r
 }
 
 // WithFilter allows to filter by a field that is not in the requested response
+// Filters are combined, a field can be filtered several times like with a range.
 func (r *Request) WithFilter(fieldName string, filter *Filter) *Request {
-	if filter.c != "" {
-		fieldName = fmt.Sprintf("%s__%s", fieldName, filter.c)
+	for _, term := range filter.terms() {
+		r.filters = append(r.filters, &filterParam{field: fieldName, key: term.key(fieldName), filter: term})
+	}
+	r.invalidate()
+	if err := filter.firstErr(); err != nil && r.err == nil {
+		r.err = err
 	}
-	r.additionalFields[fieldName] = filter.value
 	return r
 }
 
+// filterParam is a filter applied to a request with its query parameter name.
+type filterParam struct {
+	field  string
+	key    string
+	filter *Filter
+}
+
 // Expand expands a field without explicitly listing it as a field to return.
 // This is usefult if you want to return all fields.
+// A copy of the field is added, f is not modified.
 func (r *Request) Expand(f *Field) *Request {
-	f.IsExpanded = true
-	f.IsIncluded = false
-	r.AddField(f)
+	expanded := f.clone()
+	expanded.IsExpanded = true
+	expanded.IsIncluded = false
+	r.AddField(expanded)
 	return r
 }
 
 // Execute executes the request and writes it's results to the value pointed to by v.
 func (r *Request) Execute(v interface{}) error {
-	url, err := r.ToURL()
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequestWithContext(r.ctx, "GET", url.String(), nil)
-	if err != nil {
-		return err
-	}
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
+	return r.executor().Do(r, v)
+}
 
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		message, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			return fmt.Errorf("Unable to read error message from server: %w", err)
-		}
-		return errors.New(string(message))
+// Create sends body as JSON in a POST request to the request's collection and writes the response,
+// usually the created object including its uid, to the value pointed to by v.
+func (r *Request) Create(body, v interface{}) error {
+	return r.executor().send(r, http.MethodPost, body, v)
+}
+
+// Update replaces the object with body using a PUT request and writes the response to the value pointed to by v.
+func (r *Request) Update(body, v interface{}) error {
+	return r.executor().send(r, http.MethodPut, body, v)
+}
+
+// Patch partially updates the object with the fields in body and writes the response to the value pointed to by v.
+func (r *Request) Patch(body, v interface{}) error {
+	return r.executor().send(r, http.MethodPatch, body, v)
+}
+
+// Delete deletes the object and writes the response, if there is one, to the value pointed to by v.
+func (r *Request) Delete(v interface{}) error {
+	return r.executor().send(r, http.MethodDelete, nil, v)
+}
+
+// executor returns the client the request is executed with.
+func (r *Request) executor() *Client {
+	if r.client != nil {
+		return r.client
 	}
+	return DefaultClient()
+}
 
-	return json.NewDecoder(res.Body).Decode(v)
+// Finalize adds a hook that can modify the HTTP request for anything golark can't express.
+// Hooks run in the order they were added, after all headers and parameters are set and before the request is signed.
+// They run again for every retry. An error aborts the request.
+func (r *Request) Finalize(fn func(*http.Request) error) *Request {
+	r.finalizers = append(r.finalizers, fn)
+	return r
+}
+
+// WithClient sets the client the request will be executed with, overriding the default client.
+func (r *Request) WithClient(c *Client) *Request {
+	r.client = c
+	return r
 }
@@ -0,0 +1,266 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriteMethods(t *testing.T) {
+	type call struct {
+		method, path, body string
+	}
+	var calls []call
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, call{r.Method, r.URL.Path, string(body)})
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"uid": "ep_1", "title": "Race"}`))
+	}))
+	defer server.Close()
+	c := NewClient(WithBaseURL(server.URL))
+
+	var created episode
+	if err := c.NewRequest("episodes", "").Create(map[string]string{"title": "Race"}, &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.UID != "ep_1" {
+		t.Error("incorrect created object", created)
+	}
+	if err := c.NewRequest("episodes", "ep_1").Update(episode{Title: "Race"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.NewRequest("episodes", "ep_1").Patch(map[string]string{"title": "Race"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.NewRequest("episodes", "ep_1").Delete(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []call{
+		{http.MethodPost, "/episodes/", `{"title":"Race"}`},
+		{http.MethodPut, "/episodes/ep_1/", `{"uid":"","title":"Race"}`},
+		{http.MethodPatch, "/episodes/ep_1/", `{"title":"Race"}`},
+		{http.MethodDelete, "/episodes/ep_1/", ""},
+	}
+	if len(calls) != len(expected) {
+		t.Fatal("incorrect calls", calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], calls[i])
+		}
+	}
+}
+
+func TestPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var objects []string
+		for i := offset; i < offset+limit && i < 5; i++ {
+			objects = append(objects, fmt.Sprintf(`{"uid": "ep_%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"objects": [%s], "meta": {"total_count": 5}}`, strings.Join(objects, ","))
+	}))
+	defer server.Close()
+	c := NewClient(WithBaseURL(server.URL))
+
+	var pages int
+	var totals []int
+	progress := func(p Progress) {
+		pages = p.Pages
+		totals = append(totals, p.Total)
+	}
+	var all []episode
+	if err := c.NewRequest("episodes", "").Limit(2).Offset(1).WithProgress(progress).ExecuteAll(&all); err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 || all[0].UID != "ep_1" || all[3].UID != "ep_4" || pages != 2 {
+		t.Error("incorrect objects", all, pages)
+	}
+	for _, total := range totals {
+		if total != 4 {
+			t.Error("incorrect progress totals", totals)
+			break
+		}
+	}
+
+	it := c.NewRequest("episodes", "").Limit(3).Iterate()
+	var uids []string
+	for it.Next() {
+		var ep episode
+		if err := json.Unmarshal(it.Value(), &ep); err != nil {
+			t.Fatal(err)
+		}
+		uids = append(uids, ep.UID)
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if strings.Join(uids, ",") != "ep_0,ep_1,ep_2,ep_3,ep_4" {
+		t.Error("incorrect iteration", uids)
+	}
+}
+
+func TestPaginationCappedLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the server returns at most 2 objects like a Tastypie max_limit
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var objects []string
+		for i := offset; i < offset+2 && i < 5; i++ {
+			objects = append(objects, fmt.Sprintf(`{"uid": "ep_%d"}`, i))
+		}
+		next := "null"
+		if offset+2 < 5 {
+			next = fmt.Sprintf(`"/api/episodes/?limit=2&offset=%d"`, offset+2)
+		}
+		fmt.Fprintf(w, `{"objects": [%s], "meta": {"total_count": 5, "limit": 2, "next": %s}}`, strings.Join(objects, ","), next)
+	}))
+	defer server.Close()
+	c := NewClient(WithBaseURL(server.URL))
+
+	var all []episode
+	if err := c.NewRequest("episodes", "").Limit(10).ExecuteAll(&all); err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 || all[4].UID != "ep_4" {
+		t.Error("incorrect objects", all)
+	}
+
+	it := c.NewRequest("episodes", "").Limit(10).Iterate()
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if it.Err() != nil || n != 5 {
+		t.Errorf("expected 5 objects, got %d: %v", n, it.Err())
+	}
+}
+
+func TestExplain(t *testing.T) {
+	c := NewClient(
+		WithBaseURL("https://test.com"),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3}),
+		WithObjectLimit(10, func(*Request, *ObjectLimitError) {}),
+		WithMaskedParams("token"),
+	)
+	e, err := c.NewRequest("episodes", "").
+		Expand(NewField("image_urls")).
+		WithParam("token", "secret").
+		Explain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.URLs) != 1 || strings.Contains(e.URLs[0], "secret") || !strings.Contains(e.URLs[0], "fields_to_expand=image_urls") {
+		t.Error("incorrect URLs", e.URLs)
+	}
+	if e.Retry.MaxAttempts != 3 || len(e.Expansions) != 1 || len(e.Warnings) != 1 {
+		t.Error("incorrect explanation", e)
+	}
+	if !strings.Contains(e.String(), "GET https://test.com/episodes/") {
+		t.Error("incorrect explanation string", e.String())
+	}
+}
+
+func TestMemoizedURL(t *testing.T) {
+	r := NewRequest("https://test.com", "episodes", "").Limit(2)
+	first, err := r.ToURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.RawQuery = ""
+	if u, _ := r.ToURL(); u.Query().Get("limit") != "2" {
+		t.Error("memoized URL was modified through a returned copy", u)
+	}
+
+	r.Limit(3)
+	if u, _ := r.ToURL(); u.Query().Get("limit") != "3" {
+		t.Error("memoized URL was not invalidated", u)
+	}
+	r.ID = "ep_1"
+	if u, _ := r.ToURL(); u.Path != "/episodes/ep_1/" {
+		t.Error("memoized URL ignored changed ID", u)
+	}
+	title := NewField("title")
+	r.AddField(title)
+	r.ToURL()
+	title.WithFilter(NewFilter(Equals, "Monaco"))
+	if u, _ := r.ToURL(); u.Query().Get("title") != "Monaco" {
+		t.Error("memoized URL ignored changed field", u)
+	}
+	image := NewField("image_urls")
+	r.AddField(image)
+	r.ToURL()
+	image.WithSubField(NewField("url"))
+	if u, _ := r.ToURL(); !strings.Contains(u.Query().Get("fields"), "image_urls__url") {
+		t.Error("memoized URL ignored added sub field", u)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		clone := r.copy().Offset(i)
+		wg.Add(2)
+		for j := 0; j < 2; j++ {
+			go func(i int) {
+				defer wg.Done()
+				u, err := clone.ToURL()
+				if err != nil || u.Query().Get("offset") != strconv.Itoa(i) {
+					t.Error("incorrect URL", u, err)
+				}
+			}(i)
+		}
+	}
+	wg.Wait()
+}
+
+func TestClone(t *testing.T) {
+	image := NewField("image_urls").WithSubField(NewField("url"))
+	base := NewRequest("https://test.com/api/", "sets", "").AddField(NewField("title")).AddField(image)
+	want, err := base.ToURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r := base.Clone().
+				AddField(NewField(fmt.Sprint("field_", i))).
+				WithFilter("year", NewFilter(Equals, strconv.Itoa(2000+i))).
+				Expand(NewField("driver_urls"))
+			r.Fields["image_urls"].WithSubField(NewField("width"))
+			if _, err := r.ToURL(); err != nil {
+				t.Error(err)
+			}
+			if _, err := base.ToURL(); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	testURL(base, want.String(), t)
+	base.invalidate()
+	testURL(base, want.String(), t)
+	if len(image.SubFields) != 1 {
+		t.Error("clones modified the original field", image.SubFields)
+	}
+
+	expanded := NewField("driver_urls")
+	base.Clone().Expand(expanded)
+	if expanded.IsExpanded || !expanded.IsIncluded {
+		t.Error("Expand modified the passed field")
+	}
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"sort"
+	"sync"
+)
+
+const (
+	// maxMetricTags is the number of tags of a request that become metric labels, further tags only appear on spans.
+	maxMetricTags = 4
+	// maxTagValues is the number of distinct values per tag that become metric labels,
+	// later values are reported as OtherTagValue.
+	maxTagValues = 64
+)
+
+// OtherTagValue replaces tag values in metric labels once a tag has had too many distinct values.
+const OtherTagValue = "other"
+
+// WithTag tags the request, for example with the feature it is made for.
+// Tags are added to the metadata of logs and audit records and to spans as "golark.tag.<key>" attributes.
+// The first few tags in key order also become metric labels, with a bounded number of values per tag.
+func (r *Request) WithTag(key, value string) *Request {
+	if r.tags == nil {
+		r.tags = make(map[string]string)
+	}
+	r.tags[key] = value
+	return r
+}
+
+// tagged returns a copy of the request whose context carries its tags as metadata.
+func (r *Request) tagged() *Request {
+	if len(r.tags) == 0 {
+		return r
+	}
+	keyvals := make([]string, 0, 2*len(r.tags))
+	for key, value := range r.tags {
+		keyvals = append(keyvals, key, value)
+	}
+	c := *r
+	c.ctx = ContextWithMetadata(r.ctx, keyvals...)
+	return &c
+}
+
+// tagAttributes returns the tags of the request as attributes in key order.
+func (r *Request) tagAttributes() []Attribute {
+	keys := make([]string, 0, len(r.tags))
+	for key := range r.tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	attrs := make([]Attribute, len(keys))
+	for i, key := range keys {
+		attrs[i] = Attribute{"golark.tag." + key, r.tags[key]}
+	}
+	return attrs
+}
+
+// tagValues bounds the cardinality of tag values in metric labels.
+type tagValues struct {
+	mu     sync.Mutex
+	values map[string]map[string]bool
+}
+
+// label returns the value used in metric labels for the tag attribute.
+func (t *tagValues) label(attr Attribute) Attribute {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.values == nil {
+		t.values = make(map[string]map[string]bool)
+	}
+	seen := t.values[attr.Key]
+	if seen == nil {
+		seen = make(map[string]bool)
+		t.values[attr.Key] = seen
+	}
+	if !seen[attr.Value] {
+		if len(seen) >= maxTagValues {
+			return Attribute{attr.Key, OtherTagValue}
+		}
+		seen[attr.Value] = true
+	}
+	return attr
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type labelInstrumentor struct {
+	testInstrumentor
+	labels []map[string]string
+}
+
+func (i *labelInstrumentor) AddCounter(name string, delta int64, attrs ...Attribute) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if name != MetricRequests {
+		return
+	}
+	labels := make(map[string]string)
+	for _, a := range attrs {
+		labels[a.Key] = a.Value
+	}
+	i.labels = append(i.labels, labels)
+}
+
+type auditRecords struct {
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+func (a *auditRecords) Audit(record AuditRecord) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.records = append(a.records, record)
+}
+
+func TestWithTag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	inst := &labelInstrumentor{testInstrumentor: testInstrumentor{counters: make(map[string]int64), histograms: make(map[string]int)}}
+	audit := &auditRecords{}
+	var logged []interface{}
+	c := NewClient(WithBaseURL(server.URL), WithInstrumentor(inst), WithAuditSink(audit),
+		WithLogger(LoggerFunc(func(msg string, keyvals ...interface{}) { logged = keyvals })))
+
+	r := c.NewRequest("episodes", "").WithTag("feature", "schedule").WithContext(context.Background())
+	r.Execute(nil)
+
+	if inst.spans[0].attrs["golark.tag.feature"] != "schedule" {
+		t.Error("tag is missing from span", inst.spans[0].attrs)
+	}
+	if inst.labels[0]["golark.tag.feature"] != "schedule" {
+		t.Error("tag is missing from metric labels", inst.labels[0])
+	}
+	if audit.records[0].Metadata["feature"] != "schedule" {
+		t.Error("tag is missing from audit record", audit.records[0])
+	}
+	if fmt.Sprint(logged[len(logged)-2:]) != "[feature schedule]" {
+		t.Error("tag is missing from logs", logged)
+	}
+
+	for n := 0; n < maxTagValues+1; n++ {
+		c.NewRequest("episodes", "").WithTag("feature", fmt.Sprint(n)).Execute(nil)
+	}
+	if last := inst.labels[len(inst.labels)-1]["golark.tag.feature"]; last != OtherTagValue {
+		t.Errorf("expected unbounded tag value to be reported as %q, got %q", OtherTagValue, last)
+	}
+	if inst.labels[1]["golark.tag.feature"] != "0" {
+		t.Error("known tag value was replaced", inst.labels[1])
+	}
+}
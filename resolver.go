@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// EndpointResolver picks the endpoint for requests that don't specify one.
+// It takes precedence over the endpoint set with WithBaseURL.
+type EndpointResolver interface {
+	// Endpoint returns the endpoint to use for a request executed with ctx.
+	Endpoint(ctx context.Context) (string, error)
+	// Report is called with the outcome of every attempt made against an endpoint returned by Endpoint,
+	// except attempts that failed because the request's context was done.
+	Report(endpoint string, err error)
+}
+
+// WithEndpointResolver sets the resolver used to pick the endpoint per request.
+func WithEndpointResolver(resolver EndpointResolver) Option {
+	return func(s *settings) {
+		s.resolver = resolver
+		s.shareClock(resolver)
+	}
+}
+
+type regionKey struct{}
+
+// WithRegion returns a context that makes a RegionalResolver prefer the given region.
+func WithRegion(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, regionKey{}, region)
+}
+
+// RegionFromContext returns the region set with WithRegion.
+func RegionFromContext(ctx context.Context) (string, bool) {
+	region, ok := ctx.Value(regionKey{}).(string)
+	return region, ok
+}
+
+// ErrNoEndpoint is returned by a RegionalResolver that has no endpoint for a request.
+var ErrNoEndpoint = errors.New("no endpoint available")
+
+// RegionalResolver picks the endpoint by the region set on the request context.
+// When an endpoint fails with a network error or 5xx response, requests for its region
+// stick to the next region in Fallbacks until Cooldown has passed.
+type RegionalResolver struct {
+	// Endpoints maps regions to their endpoints.
+	Endpoints map[string]string
+	// Fallbacks lists the regions tried in order when the requested region is unavailable.
+	// The first entry is used for requests without a region.
+	Fallbacks []string
+	// Cooldown is how long a failed endpoint is avoided. Defaults to 30 seconds.
+	Cooldown time.Duration
+
+	mu          sync.Mutex
+	clock       Clock
+	failedUntil map[string]time.Time
+}
+
+// SetClock sets the clock used for cooldowns, it defaults to the system clock.
+// Clients set it to their clock, see WithClock.
+func (r *RegionalResolver) SetClock(clock Clock) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = clock
+}
+
+func (r *RegionalResolver) now() time.Time {
+	if r.clock == nil {
+		return time.Now()
+	}
+	return r.clock.Now()
+}
+
+// Endpoint implements EndpointResolver.
+func (r *RegionalResolver) Endpoint(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	var candidates []string
+	if region, ok := RegionFromContext(ctx); ok {
+		candidates = append(candidates, region)
+	}
+	candidates = append(candidates, r.Fallbacks...)
+
+	for _, region := range candidates {
+		endpoint, ok := r.Endpoints[region]
+		if !ok {
+			continue
+		}
+		if now.Before(r.failedUntil[endpoint]) {
+			continue
+		}
+		return endpoint, nil
+	}
+	return "", ErrNoEndpoint
+}
+
+// Report implements EndpointResolver.
+func (r *RegionalResolver) Report(endpoint string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err == nil {
+		delete(r.failedUntil, endpoint)
+		return
+	}
+	if !isTransient(err) {
+		return
+	}
+	if r.failedUntil == nil {
+		r.failedUntil = make(map[string]time.Time)
+	}
+	cooldown := r.Cooldown
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	r.failedUntil[endpoint] = r.now().Add(cooldown)
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRegionalResolverFallback(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "healthy"}`))
+	}))
+	defer healthy.Close()
+
+	resolver := &RegionalResolver{
+		Endpoints: map[string]string{"eu": broken.URL, "us": healthy.URL},
+		Fallbacks: []string{"us"},
+	}
+	c := NewClient(WithEndpointResolver(resolver))
+	ctx := WithRegion(context.Background(), "eu")
+
+	var res struct {
+		Name string `json:"name"`
+	}
+	if err := c.NewRequest("set", "set_123").WithContext(ctx).Execute(&res); err == nil {
+		t.Fatal("expected error from broken region")
+	}
+
+	if err := c.NewRequest("set", "set_123").WithContext(ctx).Execute(&res); err != nil {
+		t.Fatal("expected fallback to healthy region, got", err)
+	}
+	if res.Name != "healthy" {
+		t.Error("incorrect response", res.Name)
+	}
+}
+
+func TestRegionalResolverCallerDeadline(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	resolver := &RegionalResolver{
+		Endpoints: map[string]string{"eu": slow.URL, "us": "https://us.test.com"},
+		Fallbacks: []string{"us"},
+	}
+	c := NewClient(WithEndpointResolver(resolver))
+	ctx, cancel := context.WithTimeout(WithRegion(context.Background(), "eu"), 50*time.Millisecond)
+	defer cancel()
+
+	err := c.NewRequest("set", "set_123").WithContext(ctx).Execute(nil)
+	if err != context.DeadlineExceeded {
+		t.Fatal("expected the context's error, got", err)
+	}
+	// the caller's deadline is not the endpoint's failure
+	if endpoint, _ := resolver.Endpoint(WithRegion(context.Background(), "eu")); endpoint != slow.URL {
+		t.Error("endpoint was marked as failed, got", endpoint)
+	}
+}
@@ -0,0 +1,37 @@
+package client
+
+import "time"
+
+// Result describes how a request was executed.
+type Result struct {
+	// StatusCode is the status of the last response, it is 0 if no response was received.
+	StatusCode int
+	// Attempts is the number of HTTP requests that were sent, including retries.
+	Attempts int
+	// AttemptDurations are the durations of the individual attempts.
+	AttemptDurations []time.Duration
+	// Duration is the total wall time, including backoff between attempts.
+	Duration time.Duration
+	// Stale is set if the request failed and a kept response was served instead, see WithStaleIfError.
+	Stale bool
+	// DroppedFields are the fields the request was retried without, see WithUnknownFieldFallback.
+	DroppedFields []string
+	// NotModified is set if the server responded with 304 Not Modified, see Request.IfModifiedSince.
+	NotModified bool
+	// Cost is the estimated cost of the request, see WithCostModel.
+	Cost float64
+}
+
+// RetryOverhead returns the part of Duration not spent in the final attempt,
+// the latency caused by failed attempts and backoff.
+func (r *Result) RetryOverhead() time.Duration {
+	if len(r.AttemptDurations) == 0 {
+		return r.Duration
+	}
+	return r.Duration - r.AttemptDurations[len(r.AttemptDurations)-1]
+}
+
+// ExecuteResult executes the request like Execute and returns how it was executed.
+func (r *Request) ExecuteResult(v interface{}) (*Result, error) {
+	return r.executor().DoResult(r, v)
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+// Requests are retried on network errors, 5xx and 429 Too Many Requests responses.
+// The delay before retrying a 429 response is taken from its Retry-After header if it has one.
+// No retry is made if the delay would exceed the request context's deadline.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int `yaml:"max_attempts"`
+	// Backoff is the time to wait before the first retry.
+	Backoff time.Duration `yaml:"backoff"`
+	// Multiplier increases the backoff exponentially for every further retry, values up to 1 keep it constant.
+	Multiplier float64 `yaml:"multiplier"`
+	// MaxBackoff caps the backoff, zero means no cap.
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	// Jitter randomizes each backoff by up to the given fraction in either direction, for example 0.2 for ±20%.
+	Jitter float64 `yaml:"jitter"`
+	// SplitDeadline gives each attempt an equal share of the time left until the request context's deadline,
+	// instead of letting the first attempt use all of it.
+	SplitDeadline bool `yaml:"split_deadline"`
+}
+
+// attemptContext returns the context for the given attempt, starting at 1.
+func (p RetryPolicy) attemptContext(ctx context.Context, attempt int) (context.Context, context.CancelFunc) {
+	if !p.SplitDeadline || p.MaxAttempts < 2 {
+		return ctx, func() {}
+	}
+	return WithAttemptTimeout(ctx, p.MaxAttempts-attempt+1)
+}
+
+// shouldRetry reports whether another attempt should be made for a request executed with ctx.
+func (p RetryPolicy) shouldRetry(ctx context.Context, attempt int, err error) bool {
+	return attempt < p.MaxAttempts && ctx.Err() == nil && isTransient(err)
+}
+
+// isTransient reports whether err is a network error, timeout, 429 or 5xx response that might not occur again.
+// Any other error, like a failed decode or a rejected request, is returned without retrying.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || isStreamError(err) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	// every error of http.Client.Do is a *url.Error, so its cause decides
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// delay returns how long to wait after the given failed attempt, starting at 1.
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	d := float64(p.Backoff)
+	if p.Multiplier > 1 {
+		d *= math.Pow(p.Multiplier, float64(attempt-1))
+	}
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// fitsDeadline reports whether waiting for d from now leaves time before ctx's deadline.
+func fitsDeadline(ctx context.Context, d time.Duration, now time.Time) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || deadline.Sub(now) > d
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or as an HTTP date relative to now.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
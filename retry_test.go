@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		if len(times) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+	if err := c.NewRequest("episodes", "").Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(times) != 2 || times[1].Sub(times[0]) < time.Second {
+		t.Error("Retry-After was not honored", times)
+	}
+
+	// the deadline passes before the server allows the next attempt
+	times = nil
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	var apiErr *APIError
+	if err := c.NewRequest("episodes", "").WithContext(ctx).Execute(nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Error("expected error without retry, got", err)
+	}
+	if len(times) != 1 {
+		t.Error("request was retried past its deadline")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: 100 * time.Millisecond, Multiplier: 2, MaxBackoff: 300 * time.Millisecond}
+	for attempt, expected := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond} {
+		if d := p.delay(attempt+1, errors.New("network")); d != expected {
+			t.Errorf("attempt %d: expected %s, got %s", attempt+1, expected, d)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d := p.delay(1, errors.New("network")); d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatal("jitter out of range:", d)
+		}
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{"connection refused", &url.Error{Op: "Get", URL: "https://test.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, true},
+		{"connection closed", &url.Error{Op: "Get", URL: "https://test.com", Err: io.EOF}, true},
+		{"server error", &APIError{StatusCode: http.StatusBadGateway}, true},
+		{"too many requests", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"not found", &APIError{StatusCode: http.StatusNotFound}, false},
+		{"canceled", &url.Error{Op: "Get", URL: "https://test.com", Err: context.Canceled}, false},
+		{"rejected by transport", &url.Error{Op: "Get", URL: "https://test.com", Err: errors.New("signing failed")}, false},
+		{"decode error", &json.SyntaxError{}, false},
+		{"invalid request", errors.New("invalid filter"), false},
+	}
+	for _, tt := range tests {
+		if got := isTransient(tt.err); got != tt.transient {
+			t.Errorf("%s: expected transient %v, got %v", tt.name, tt.transient, got)
+		}
+	}
+}
+
+func TestExecuteResult(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: 20 * time.Millisecond}))
+	res, err := c.NewRequest("episodes", "").ExecuteResult(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK || res.Attempts != 2 || len(res.AttemptDurations) != 2 {
+		t.Error("incorrect result", res)
+	}
+	if res.RetryOverhead() < 20*time.Millisecond || res.Duration < res.RetryOverhead() {
+		t.Error("incorrect durations", res.Duration, res.RetryOverhead())
+	}
+}
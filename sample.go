@@ -0,0 +1,79 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// ResponseSample is a successful response captured by WithSampling.
+type ResponseSample struct {
+	Time       time.Time
+	Method     string
+	URL        string
+	StatusCode int
+	Header     http.Header
+	// Body is the response body with redacted fields, see WithRedactedFields.
+	Body []byte
+}
+
+// SampleSink receives sampled responses. Sample is called synchronously while the response is decoded
+// and must be safe for concurrent use.
+type SampleSink interface {
+	Sample(sample ResponseSample)
+}
+
+// SampleSinkFunc adapts a function to the SampleSink interface.
+type SampleSinkFunc func(sample ResponseSample)
+
+// Sample implements SampleSink.
+func (f SampleSinkFunc) Sample(sample ResponseSample) {
+	f(sample)
+}
+
+// Sampling configures WithSampling.
+type Sampling struct {
+	// Fraction is the fraction of successful responses that are captured, between 0 and 1.
+	Fraction float64
+	Sink     SampleSink
+	// Rand returns random numbers in [0, 1), it defaults to math/rand.
+	Rand func() float64
+}
+
+// WithSampling captures a fraction of successful responses in full, to detect changes of the response schema
+// in production without logging every response. URLs and headers are masked and bodies redacted.
+func WithSampling(sampling Sampling) Option {
+	return func(s *settings) {
+		if sampling.Sink == nil || sampling.Fraction <= 0 {
+			s.sampling = nil
+			return
+		}
+		if sampling.Rand == nil {
+			sampling.Rand = rand.Float64
+		}
+		s.sampling = &sampling
+	}
+}
+
+// sample passes the response to the sampling sink if it is sampled and returns a reader for the consumed body.
+func (s *settings) sample(req *http.Request, res *http.Response, body io.Reader) (io.Reader, error) {
+	if s.sampling == nil || res.StatusCode < 200 || res.StatusCode >= 300 || s.sampling.Rand() >= s.sampling.Fraction {
+		return body, nil
+	}
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	s.sampling.Sink.Sample(ResponseSample{
+		Time:       s.now(),
+		Method:     req.Method,
+		URL:        s.maskURL(req.URL),
+		StatusCode: res.StatusCode,
+		Header:     s.maskHeader(res.Header),
+		Body:       redact(data, s.redacted),
+	})
+	return bytes.NewReader(data), nil
+}
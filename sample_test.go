@@ -0,0 +1,54 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestSampling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"uid": "ep_1", "token": "secret"}`))
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var samples []ResponseSample
+	random := []float64{0.05, 0.5, 0.01}
+	c := NewClient(WithBaseURL(server.URL), WithRedactedFields("token"), WithSampling(Sampling{
+		Fraction: 0.1,
+		Sink: SampleSinkFunc(func(sample ResponseSample) {
+			mu.Lock()
+			defer mu.Unlock()
+			samples = append(samples, sample)
+		}),
+		Rand: func() float64 {
+			n := random[0]
+			random = random[1:]
+			return n
+		},
+	}))
+
+	var v struct{ UID string }
+	for i := 0; i < 2; i++ {
+		if err := c.NewRequest("episodes", "ep_1").Execute(&v); err != nil {
+			t.Fatal(err)
+		}
+		if v.UID != "ep_1" {
+			t.Error("sampled response was not decoded")
+		}
+	}
+	c.NewRequest("missing", "").Execute(nil)
+
+	if len(samples) != 1 {
+		t.Fatal("expected one sample, got", len(samples))
+	}
+	if string(samples[0].Body) != `{"token":"[REDACTED]","uid":"ep_1"}` || samples[0].URL != server.URL+"/episodes/ep_1/" || samples[0].StatusCode != 200 {
+		t.Error("incorrect sample", samples[0].URL, string(samples[0].Body))
+	}
+}
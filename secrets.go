@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// SecretsProvider fetches auth material by name, for example from Vault or a cloud secrets manager.
+type SecretsProvider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// EnvSecrets is a SecretsProvider that reads secrets from environment variables.
+type EnvSecrets struct{}
+
+// Secret implements SecretsProvider.
+func (EnvSecrets) Secret(ctx context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// CachedSecrets wraps a provider and caches secrets for ttl, so not every request hits the provider.
+// Secrets expire by the clock of the client they are used by, see WithClock.
+func CachedSecrets(p SecretsProvider, ttl time.Duration) SecretsProvider {
+	return &cachedSecrets{provider: p, ttl: ttl, cache: make(map[string]cachedSecret)}
+}
+
+type cachedSecret struct {
+	value   string
+	expires time.Time
+}
+
+type cachedSecrets struct {
+	provider SecretsProvider
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	clock Clock
+	cache map[string]cachedSecret
+}
+
+// SetClock sets the clock used to expire secrets, clients set it to their clock.
+func (c *cachedSecrets) SetClock(clock Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+// now must be called with mu held.
+func (c *cachedSecrets) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+func (c *cachedSecrets) Secret(ctx context.Context, name string) (string, error) {
+	c.mu.Lock()
+	cached, ok := c.cache[name]
+	fresh := ok && c.now().Before(cached.expires)
+	c.mu.Unlock()
+	if fresh {
+		return cached.value, nil
+	}
+
+	value, err := c.provider.Secret(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.cache[name] = cachedSecret{value: value, expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// secretHeader is a header whose value is fetched from a SecretsProvider for every request.
+type secretHeader struct {
+	key      string
+	prefix   string
+	provider SecretsProvider
+	name     string
+}
+
+// WithSecretHeader sets a header to the prefix followed by the named secret, fetched when a request is made.
+func WithSecretHeader(key, prefix string, p SecretsProvider, name string) Option {
+	return func(s *settings) {
+		s.secretHeaders = append(s.secretHeaders, secretHeader{key: key, prefix: prefix, provider: p, name: name})
+		s.shareClock(p)
+	}
+}
+
+// WithTokenFromSecrets sends the named secret as bearer token.
+func WithTokenFromSecrets(p SecretsProvider, name string) Option {
+	return WithSecretHeader("Authorization", "Bearer ", p, name)
+}
+
+// WithAPIKeyFromSecrets sends the named secret in the Skylark-Api-Key header.
+func WithAPIKeyFromSecrets(p SecretsProvider, name string) Option {
+	return WithSecretHeader("Skylark-Api-Key", "", p, name)
+}
+
+func (h secretHeader) value(ctx context.Context) (string, error) {
+	secret, err := h.provider.Secret(ctx, h.name)
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch secret %s: %w", h.name, err)
+	}
+	return h.prefix + secret, nil
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type countingSecrets struct {
+	calls int
+}
+
+func (s *countingSecrets) Secret(ctx context.Context, name string) (string, error) {
+	s.calls++
+	return "secret-" + name, nil
+}
+
+func TestSecretsProvider(t *testing.T) {
+	var token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("Authorization")
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	provider := &countingSecrets{}
+	c := NewClient(WithBaseURL(server.URL), WithTokenFromSecrets(CachedSecrets(provider, time.Minute), "skylark"))
+	for i := 0; i < 2; i++ {
+		if err := c.NewRequest("sets", "").Execute(&struct{}{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if token != "Bearer secret-skylark" {
+		t.Error("incorrect token", token)
+	}
+	if provider.calls != 1 {
+		t.Error("secret should be cached, provider was called", provider.calls, "times")
+	}
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// SelfRef is a parsed self URL like /api/sets/set_123/.
+// It can be used as a field type in structs decoded from Skylark responses.
+type SelfRef struct {
+	Collection string
+	ID         string
+	// URL is the original self URL.
+	URL string
+}
+
+// ParseSelf parses a self URL into its collection and ID.
+func ParseSelf(self string) (SelfRef, error) {
+	collection, id, ok := parseSelf(self)
+	if !ok {
+		return SelfRef{}, fmt.Errorf("invalid self URL %q", self)
+	}
+	return SelfRef{Collection: collection, ID: id, URL: self}, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SelfRef) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*s = SelfRef{}
+		return nil
+	}
+	var self string
+	if err := json.Unmarshal(data, &self); err != nil {
+		return err
+	}
+	ref, err := ParseSelf(self)
+	if err != nil {
+		return err
+	}
+	*s = ref
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SelfRef) MarshalJSON() ([]byte, error) {
+	if s.URL == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(s.URL)
+}
+
+// String returns the self URL.
+func (s SelfRef) String() string {
+	return s.URL
+}
+
+// Request creates a request for the referenced object.
+func (s SelfRef) Request(endpoint string) *Request {
+	return NewRequest(endpoint, s.Collection, s.ID)
+}
+
+// RequestFor creates a request for the referenced object that uses the client's endpoint.
+func (c *Client) RequestFor(s SelfRef) *Request {
+	return c.NewRequest(s.Collection, s.ID)
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signer signs outgoing requests, for example for API gateways in front of Skylark.
+// Sign is called after all other headers are set, body is the request body or nil.
+type Signer interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+// WithSigner sets the signer applied to every request.
+func WithSigner(signer Signer) Option {
+	return func(s *settings) {
+		s.signer = signer
+		s.shareClock(signer)
+	}
+}
+
+// ErrInvalidSignature is returned by HMACSigner.Verify for requests that are not correctly signed.
+var ErrInvalidSignature = errors.New("invalid request signature")
+
+// HMACSigner signs requests with HMAC-SHA256 over the method, path with query, timestamp, nonce and body hash,
+// each on its own line. The signature, timestamp and nonce are sent in headers.
+type HMACSigner struct {
+	Secret []byte
+	// SignatureHeader defaults to X-Signature.
+	SignatureHeader string
+	// TimestampHeader defaults to X-Timestamp, the timestamp is in unix seconds.
+	TimestampHeader string
+	// NonceHeader defaults to X-Nonce.
+	NonceHeader string
+	// MaxSkew is how far a timestamp may differ from the current time to pass Verify. Defaults to 5 minutes.
+	MaxSkew time.Duration
+	// Clock tells the time of timestamps and Verify, it defaults to the system clock.
+	// Clients set it to their clock if it is nil, see WithClock.
+	Clock Clock
+}
+
+// SetClock implements the clock sharing of WithClock, it keeps a clock that was set.
+func (s *HMACSigner) SetClock(clock Clock) {
+	if s.Clock == nil {
+		s.Clock = clock
+	}
+}
+
+func (s *HMACSigner) now() time.Time {
+	if s.Clock == nil {
+		return time.Now()
+	}
+	return s.Clock.Now()
+}
+
+// Sign implements Signer.
+func (s *HMACSigner) Sign(req *http.Request, body []byte) error {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set(s.timestampHeader(), timestamp)
+	req.Header.Set(s.nonceHeader(), hex.EncodeToString(nonce))
+	req.Header.Set(s.signatureHeader(), s.signature(req, body))
+	return nil
+}
+
+// Verify checks the signature and timestamp of a request signed by Sign.
+func (s *HMACSigner) Verify(req *http.Request, body []byte) error {
+	timestamp, err := strconv.ParseInt(req.Header.Get(s.timestampHeader()), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	maxSkew := s.MaxSkew
+	if maxSkew <= 0 {
+		maxSkew = 5 * time.Minute
+	}
+	skew := s.now().Sub(time.Unix(timestamp, 0))
+	if skew > maxSkew || skew < -maxSkew {
+		return ErrInvalidSignature
+	}
+	expected := s.signature(req, body)
+	if !hmac.Equal([]byte(expected), []byte(req.Header.Get(s.signatureHeader()))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *HMACSigner) signature(req *http.Request, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	message := strings.Join([]string{
+		req.Method,
+		req.URL.RequestURI(),
+		req.Header.Get(s.timestampHeader()),
+		req.Header.Get(s.nonceHeader()),
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+	mac := hmac.New(sha256.New, s.Secret)
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *HMACSigner) signatureHeader() string {
+	if s.SignatureHeader == "" {
+		return "X-Signature"
+	}
+	return s.SignatureHeader
+}
+
+func (s *HMACSigner) timestampHeader() string {
+	if s.TimestampHeader == "" {
+		return "X-Timestamp"
+	}
+	return s.TimestampHeader
+}
+
+func (s *HMACSigner) nonceHeader() string {
+	if s.NonceHeader == "" {
+		return "X-Nonce"
+	}
+	return s.NonceHeader
+}
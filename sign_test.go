@@ -0,0 +1,49 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHMACSigner(t *testing.T) {
+	signer := &HMACSigner{Secret: []byte("secret")}
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		verifyErr = signer.Verify(r, body)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithSigner(signer))
+	if err := c.send(c.NewRequest("sets", ""), http.MethodPost, map[string]string{"title": "Race"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if verifyErr != nil {
+		t.Error("valid signature rejected:", verifyErr)
+	}
+
+	err := c.NewRequest("sets", "").
+		Finalize(func(req *http.Request) error {
+			req.URL.RawQuery = "extra=1"
+			return nil
+		}).
+		Execute(&struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verifyErr != nil {
+		t.Error("signature should cover finalized request:", verifyErr)
+	}
+
+	other := &HMACSigner{Secret: []byte("other")}
+	c = NewClient(WithBaseURL(server.URL), WithSigner(other))
+	if err := c.NewRequest("sets", "").Execute(&struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if verifyErr != ErrInvalidSignature {
+		t.Error("expected invalid signature, got", verifyErr)
+	}
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// QuerySnapshot records a query and a hash of its response, to verify later that the query still returns the same content.
+type QuerySnapshot struct {
+	// Query is the request path and canonically ordered query parameters, relative to the client's endpoint.
+	Query string `json:"query"`
+	// Hash is the hex encoded SHA-256 of the canonical JSON encoding of the response.
+	Hash  string    `json:"hash"`
+	Taken time.Time `json:"taken"`
+}
+
+// SnapshotOptions configures how responses are hashed.
+type SnapshotOptions struct {
+	// IgnoreFields are removed from objects at any depth before hashing, for example "modified".
+	IgnoreFields []string
+}
+
+// Snapshot executes the request and returns a snapshot of its response.
+func (c *Client) Snapshot(r *Request, opts SnapshotOptions) (QuerySnapshot, error) {
+	s := c.current()
+	if s.err != nil {
+		return QuerySnapshot{}, s.err
+	}
+	endpoint, _, err := s.endpointFor(r)
+	if err != nil {
+		return QuerySnapshot{}, err
+	}
+	u, err := s.buildURL(r, endpoint)
+	if err != nil {
+		return QuerySnapshot{}, err
+	}
+
+	var response json.RawMessage
+	if err := c.Do(r, &response); err != nil {
+		return QuerySnapshot{}, err
+	}
+	hash, err := opts.hash(response)
+	if err != nil {
+		return QuerySnapshot{}, err
+	}
+	return QuerySnapshot{Query: strings.TrimPrefix(canonicalURL(u), endpoint), Hash: hash, Taken: s.now()}, nil
+}
+
+// canonicalURL returns the URL with sorted parameters and sorted field lists,
+// so equal requests always have the same canonical form.
+func canonicalURL(u *url.URL) string {
+	q := u.Query()
+	for _, key := range []string{"fields", "fields_to_expand"} {
+		for i, value := range q[key] {
+			names := strings.Split(value, ",")
+			sort.Strings(names)
+			q[key][i] = strings.Join(names, ",")
+		}
+	}
+	canonical := *u
+	canonical.RawQuery = q.Encode()
+	return canonical.String()
+}
+
+// VerifySnapshot re-runs the snapshot's query against the client's endpoint and returns a new snapshot of the response
+// and whether it matches the original one.
+func (c *Client) VerifySnapshot(ctx context.Context, snapshot QuerySnapshot, opts SnapshotOptions) (QuerySnapshot, bool, error) {
+	s := c.current()
+	if s.err != nil {
+		return QuerySnapshot{}, false, s.err
+	}
+	if s.endpoint == "" {
+		return QuerySnapshot{}, false, fmt.Errorf("%w: no endpoint configured", ErrInvalidEndpoint)
+	}
+	u, err := url.Parse(s.endpoint + snapshot.Query)
+	if err != nil {
+		return QuerySnapshot{}, false, err
+	}
+
+	var response json.RawMessage
+	if _, err := s.do(&call{ctx: ctx, method: http.MethodGet, url: u}, &response); err != nil {
+		return QuerySnapshot{}, false, err
+	}
+	hash, err := opts.hash(response)
+	if err != nil {
+		return QuerySnapshot{}, false, err
+	}
+	current := QuerySnapshot{Query: snapshot.Query, Hash: hash, Taken: s.now()}
+	return current, current.Hash == snapshot.Hash, nil
+}
+
+// hash returns the hash of the canonical encoding of the response without ignored fields.
+// Object keys are sorted by encoding/json, so the hash doesn't depend on the order the server sent them in.
+func (o SnapshotOptions) hash(response json.RawMessage) (string, error) {
+	var v interface{}
+	if err := json.Unmarshal(response, &v); err != nil {
+		return "", err
+	}
+	if len(o.IgnoreFields) > 0 {
+		ignored := make(map[string]bool, len(o.IgnoreFields))
+		for _, field := range o.IgnoreFields {
+			ignored[field] = true
+		}
+		v = withoutFields(v, ignored)
+	}
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// withoutFields removes the ignored keys from all objects in v.
+func withoutFields(v interface{}, ignored map[string]bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if ignored[key] {
+				delete(v, key)
+				continue
+			}
+			v[key] = withoutFields(value, ignored)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = withoutFields(value, ignored)
+		}
+	}
+	return v
+}
+
+// WriteSnapshots writes the snapshots to w as newline delimited JSON.
+func WriteSnapshots(w io.Writer, snapshots []QuerySnapshot) error {
+	enc := json.NewEncoder(w)
+	for _, snapshot := range snapshots {
+		if err := enc.Encode(snapshot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSnapshots reads snapshots written by WriteSnapshots.
+func ReadSnapshots(rd io.Reader) ([]QuerySnapshot, error) {
+	var snapshots []QuerySnapshot
+	scanner := bufio.NewScanner(rd)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var snapshot QuerySnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			return nil, fmt.Errorf("invalid snapshot on line %d: %w", line, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, scanner.Err()
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	response := `{"objects": [{"uid": "ep_1", "title": "Race", "modified": "1"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/episodes/" && r.URL.Query().Get("fields") != "title,uid" {
+			t.Error("incorrect query", r.URL.RawQuery)
+		}
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	opts := SnapshotOptions{IgnoreFields: []string{"modified"}}
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	source := NewClient(WithBaseURL(server.URL+"/api/"), WithClock(clock))
+	snapshot, err := source.Snapshot(source.NewRequest("episodes", "").AddField(NewField("uid")).AddField(NewField("title")), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !snapshot.Taken.Equal(clock.Now()) {
+		t.Error("snapshot time does not use the client's clock", snapshot.Taken)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshots(&buf, []QuerySnapshot{snapshot}); err != nil {
+		t.Fatal(err)
+	}
+	snapshots, err := ReadSnapshots(&buf)
+	if err != nil || len(snapshots) != 1 {
+		t.Fatal("unable to read snapshots", err)
+	}
+
+	target := NewClient(WithBaseURL(server.URL + "/v2/"))
+	response = `{"objects": [{"modified": "2", "title": "Race", "uid": "ep_1"}]}`
+	if _, ok, err := target.VerifySnapshot(context.Background(), snapshots[0], opts); err != nil || !ok {
+		t.Error("expected matching snapshot", err)
+	}
+	response = `{"objects": [{"uid": "ep_1", "title": "Sprint", "modified": "1"}]}`
+	if _, ok, err := target.VerifySnapshot(context.Background(), snapshots[0], opts); err != nil || ok {
+		t.Error("expected mismatching snapshot", err)
+	}
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+)
+
+// Spill holds the objects of a collection written to a temporary file by ExecuteAllSpilled.
+// Close removes the file.
+type Spill struct {
+	file  *os.File
+	count int
+}
+
+// ExecuteAllSpilled fetches every page of the collection request like ExecuteAll, but writes the objects
+// to a temporary file in dir instead of keeping them in memory, so exports of any size need only memory for one page.
+// If dir is empty the default directory for temporary files is used.
+// The returned Spill must be closed to remove the file.
+func (r *Request) ExecuteAllSpilled(dir string) (*Spill, error) {
+	f, err := ioutil.TempFile(dir, "golark-spill-*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	s := &Spill{file: f}
+	w := bufio.NewWriter(f)
+	var line bytes.Buffer
+
+	limit, _ := strconv.Atoi(r.additionalFields["limit"])
+	progress := newProgressTracker(r.progress, r.executor().current().now)
+	err = r.executor().eachPage(r, limit, 0, progress, func(objects []json.RawMessage) error {
+		objects = r.dedupe.filter(objects)
+		for _, object := range objects {
+			// objects are compacted so every line holds exactly one
+			line.Reset()
+			if err := json.Compact(&line, object); err != nil {
+				return err
+			}
+			line.WriteByte('\n')
+			if _, err := w.Write(line.Bytes()); err != nil {
+				return err
+			}
+		}
+		s.count += len(objects)
+		progress.page(len(objects))
+		return nil
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	progress.done()
+	return s, nil
+}
+
+// Len returns the number of objects.
+func (s *Spill) Len() int {
+	return s.count
+}
+
+// Iterate returns an iterator over the objects in the order they were fetched.
+// Iterators read the file independently, but must not be used after Close.
+func (s *Spill) Iterate() *SpillIterator {
+	return &SpillIterator{r: bufio.NewReader(io.NewSectionReader(s.file, 0, 1<<62))}
+}
+
+// Close removes the temporary file.
+func (s *Spill) Close() error {
+	err := s.file.Close()
+	if rmErr := os.Remove(s.file.Name()); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// SpillIterator iterates over the objects of a Spill.
+type SpillIterator struct {
+	r     *bufio.Reader
+	value json.RawMessage
+	err   error
+}
+
+// Next advances to the next object, it returns false when all objects were returned or an error occurred.
+func (it *SpillIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	line, err := it.r.ReadBytes('\n')
+	if err != nil {
+		if err != io.EOF {
+			it.err = err
+		}
+		return false
+	}
+	it.value = line[:len(line)-1]
+	return true
+}
+
+// Value returns the current object.
+func (it *SpillIterator) Value() json.RawMessage {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration.
+func (it *SpillIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestExecuteAllSpilled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if offset >= 4 {
+			fmt.Fprint(w, `{"objects": [{"uid": "ep_4",
+				"title": "Pretty\nprinted"}]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"objects": [{"uid": "ep_%d"}, {"uid": "ep_%d"}]}`, offset, offset+1)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	c := NewClient(WithBaseURL(server.URL))
+	spill, err := c.NewRequest("episodes", "").Limit(2).ExecuteAllSpilled(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spill.Len() != 5 {
+		t.Error("expected 5 objects, got", spill.Len())
+	}
+
+	var uids []string
+	it := spill.Iterate()
+	for it.Next() {
+		var episode struct{ UID, Title string }
+		if err := json.Unmarshal(it.Value(), &episode); err != nil {
+			t.Fatal(err)
+		}
+		uids = append(uids, episode.UID)
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if fmt.Sprint(uids) != "[ep_0 ep_1 ep_2 ep_3 ep_4]" {
+		t.Error("incorrect objects", uids)
+	}
+
+	if err := spill.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Error("temporary file was not removed", files)
+	}
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// defaultMaxURLLength is below the URL length limit of common servers and proxies.
+const defaultMaxURLLength = 8000
+
+// WithMaxURLLength sets the URL length above which collection requests with an In filter are split into
+// multiple requests, see Client.Do. Requests that set a limit, offset or order are not split,
+// since the concatenated objects of the parts would not respect them. This includes all requests of clients
+// with a page size, which limits them. Requests are only split if the client's EnvelopeStrategy implements
+// EnvelopeEncoder, the default one does. Zero disables splitting.
+func WithMaxURLLength(n int) Option {
+	return func(s *settings) {
+		s.maxURLLength = n
+	}
+}
+
+// splitIn splits a collection request whose URL is too long into requests that each use part of the values
+// of its largest In filter. It returns nil if the request doesn't need to or can't be split.
+func (c *Client) splitIn(r *Request) []*Request {
+	s := c.current()
+	max := s.maxURLLength
+	// the merged objects of the parts can't be encoded for every envelope
+	if max <= 0 || r.ID != "" || s.encoder() == nil {
+		return nil
+	}
+	// the limit, offset and order of the parts can't be combined into those of the request,
+	// which is limited to the client's page size if it doesn't set a limit
+	if s.pageSize > 0 {
+		return nil
+	}
+	for _, param := range []string{"limit", "offset", "order"} {
+		if _, ok := r.additionalFields[param]; ok {
+			return nil
+		}
+	}
+	u, err := c.url(r)
+	if err != nil || len(u.String()) <= max {
+		return nil
+	}
+
+	index := -1
+	var values []*Filter
+	for i, f := range r.filters {
+		if f.filter.time != nil || f.filter.c != In {
+			continue
+		}
+		if items := inItems(f.filter); len(items) > len(values) {
+			index, values = i, items
+		}
+	}
+	if len(values) < 2 {
+		return nil
+	}
+
+	base := len(u.String()) - len(url.QueryEscape(r.filters[index].filter.encode(r.timeEncoder)))
+	var chunks []*Request
+	var chunk []*Filter
+	length := base
+	flush := func() {
+		part := r.copy()
+		in := *r.filters[index].filter
+		in.value = joinValues(chunk)
+		if len(in.items) > 0 {
+			in.items = chunk
+		}
+		part.filters[index] = &filterParam{field: r.filters[index].field, key: r.filters[index].key, filter: &in}
+		chunks = append(chunks, part)
+		chunk, length = nil, base
+	}
+	for _, value := range values {
+		n := len(url.QueryEscape(value.encode(r.timeEncoder)))
+		if len(chunk) > 0 {
+			// the escaped comma
+			n += 3
+		}
+		if len(chunk) > 0 && length+n > max {
+			flush()
+			n = len(url.QueryEscape(value.encode(r.timeEncoder)))
+		}
+		chunk = append(chunk, value)
+		length += n
+	}
+	flush()
+	return chunks
+}
+
+// inItems returns the values of an In filter, the filters of its times if it was created from some.
+func inItems(f *Filter) []*Filter {
+	if len(f.items) > 0 {
+		return f.items
+	}
+	var items []*Filter
+	for _, value := range strings.Split(f.value, ",") {
+		items = append(items, &Filter{value: value})
+	}
+	return items
+}
+
+// joinValues joins the unencoded values of filters with commas.
+func joinValues(filters []*Filter) string {
+	values := make([]string, len(filters))
+	for i, f := range filters {
+		values[i] = f.value
+	}
+	return strings.Join(values, ",")
+}
+
+// doSplit executes the parts of a split request concurrently and decodes their merged objects into v.
+// The merged metadata holds the summed count of the parts and no links to other pages.
+// If res is set it records the attempts of all parts.
+func (c *Client) doSplit(parts []*Request, v interface{}, res *Result) error {
+	s := c.current()
+	start := s.now()
+	results := make([]*Result, len(parts))
+	concurrency := s.bulkConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	objects := make([][]json.RawMessage, len(parts))
+	counts := make([]int, len(parts))
+	errs := make([]error, len(parts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, part := range parts {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, part *Request) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer c.recoverPanic(&errs[i])
+			var body json.RawMessage
+			results[i] = &Result{}
+			if errs[i] = c.transmit(part, http.MethodGet, nil, jsonContentType, &body, results[i]); errs[i] != nil {
+				return
+			}
+			var info PageInfo
+			objects[i], info, errs[i] = s.decodeEnvelope(body)
+			counts[i] = info.Count
+		}(i, part)
+	}
+	wg.Wait()
+	if res != nil {
+		for _, part := range results {
+			res.StatusCode = part.StatusCode
+			res.Attempts += part.Attempts
+			res.AttemptDurations = append(res.AttemptDurations, part.AttemptDurations...)
+		}
+		res.Duration = s.now().Sub(start)
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	if v == nil {
+		return nil
+	}
+
+	var merged []json.RawMessage
+	var info PageInfo
+	for i, part := range objects {
+		merged = append(merged, part...)
+		info.Count += counts[i]
+	}
+	body, err := s.replaceObjects(nil, merged, info)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSplitInFilter(t *testing.T) {
+	var mu sync.Mutex
+	var lengths []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lengths = append(lengths, len(r.URL.String()))
+		mu.Unlock()
+		var objects []string
+		for _, uid := range strings.Split(r.URL.Query().Get("uid__in"), ",") {
+			objects = append(objects, fmt.Sprintf(`{"uid": %q}`, uid))
+		}
+		fmt.Fprintf(w, `{"objects": [%s]}`, strings.Join(objects, ","))
+	}))
+	defer server.Close()
+
+	uids := make([]string, 100)
+	for i := range uids {
+		uids[i] = fmt.Sprintf("ep_%03d", i)
+	}
+	c := NewClient(WithBaseURL(server.URL), WithMaxURLLength(200))
+	var res struct {
+		Objects []episode `json:"objects"`
+	}
+	if err := c.NewRequest("episodes", "").WithFilter("uid", NewFilterValue(In, uids)).Execute(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Objects) != len(uids) || res.Objects[0].UID != "ep_000" || res.Objects[99].UID != "ep_099" {
+		t.Error("incorrect merged objects", len(res.Objects))
+	}
+	if len(lengths) < 2 {
+		t.Error("request was not split")
+	}
+	for _, length := range lengths {
+		if length > 200 {
+			t.Error("request URL too long:", length)
+		}
+	}
+}
+
+func TestSplitInFilterTimes(t *testing.T) {
+	var mu sync.Mutex
+	var dates []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		dates = append(dates, strings.Split(r.URL.Query().Get("date__in"), ",")...)
+		mu.Unlock()
+		fmt.Fprint(w, `{"objects": []}`)
+	}))
+	defer server.Close()
+
+	start := time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC)
+	times := make([]time.Time, 30)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * time.Hour)
+	}
+	c := NewClient(WithBaseURL(server.URL), WithMaxURLLength(200), WithTimeEncoding(TimeEpoch))
+	if err := c.NewRequest("episodes", "").WithFilter("date", NewFilterValue(In, times)).Execute(nil); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(dates)
+	if len(dates) != len(times) || dates[0] != "1584230400" {
+		t.Error("times were not encoded with the client's encoder", dates)
+	}
+}
+
+func TestSplitInFilterPageInfo(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		uids := strings.Split(r.URL.Query().Get("uid__in"), ",")
+		if limit := r.URL.Query().Get("limit"); limit != "" {
+			uids = uids[:10]
+		}
+		var objects []string
+		for _, uid := range uids {
+			objects = append(objects, fmt.Sprintf(`{"uid": %q}`, uid))
+		}
+		fmt.Fprintf(w, `{"objects": [%s], "meta": {"total_count": %d, "next": "/next"}}`,
+			strings.Join(objects, ","), len(strings.Split(r.URL.Query().Get("uid__in"), ",")))
+	}))
+	defer server.Close()
+
+	uids := make([]string, 100)
+	for i := range uids {
+		uids[i] = fmt.Sprintf("ep_%03d", i)
+	}
+	c := NewClient(WithBaseURL(server.URL), WithMaxURLLength(200))
+	list, err := ExecuteList[episode](c.NewRequest("episodes", "").WithFilter("uid", NewFilterValue(In, uids)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls < 2 {
+		t.Error("request was not split")
+	}
+	if list.Count != len(uids) || list.HasNext() {
+		t.Errorf("incorrect merged page info %+v", list.PageInfo)
+	}
+
+	calls = 0
+	list, err = ExecuteList[episode](c.NewRequest("episodes", "").WithFilter("uid", NewFilterValue(In, uids)).Limit(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Error("request with a limit was split")
+	}
+	if len(list.Objects) != 10 {
+		t.Error("limit not applied", len(list.Objects))
+	}
+
+	calls = 0
+	if _, err := ExecuteList[episode](c.NewRequest("episodes", "").WithFilter("uid", NewFilterValue(In, uids)).OrderBy(NewField("uid"))); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Error("ordered request was split")
+	}
+
+	calls = 0
+	c = NewClient(WithBaseURL(server.URL), WithMaxURLLength(200), WithPageSize(10))
+	list, err = ExecuteList[episode](c.NewRequest("episodes", "").WithFilter("uid", NewFilterValue(In, uids)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || len(list.Objects) != 10 {
+		t.Error("request limited by the page size was split", calls, len(list.Objects))
+	}
+}
+
+func TestSplitInFilterEnvelope(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		uids := strings.Split(r.URL.Query().Get("uid__in"), ",")
+		var objects []string
+		for _, uid := range uids {
+			objects = append(objects, fmt.Sprintf(`{"uid": %q}`, uid))
+		}
+		fmt.Fprintf(w, `{"results": [%s], "pagination": {"total": %d, "next_url": null}}`, strings.Join(objects, ","), len(uids))
+	}))
+	defer server.Close()
+
+	uids := make([]string, 50)
+	for i := range uids {
+		uids[i] = fmt.Sprintf("ep_%03d", i)
+	}
+	envelope := KeyEnvelope{Items: "results", Count: "pagination.total", Next: "pagination.next_url"}
+	c := NewClient(WithBaseURL(server.URL), WithMaxURLLength(200), WithEnvelope(envelope))
+	list, err := ExecuteList[episode](c.NewRequest("episodes", "").WithFilter("uid", NewFilterValue(In, uids)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls < 2 {
+		t.Error("request was not split")
+	}
+	if len(list.Objects) != len(uids) || list.Objects[49].UID != "ep_049" || list.Count != len(uids) || list.HasNext() {
+		t.Errorf("incorrect merged list: %d objects, %+v", len(list.Objects), list.PageInfo)
+	}
+
+	// strategies that can't encode objects are not split
+	calls = 0
+	c = NewClient(WithBaseURL(server.URL), WithMaxURLLength(200), WithEnvelope(decodeOnly{envelope}))
+	list, err = ExecuteList[episode](c.NewRequest("episodes", "").WithFilter("uid", NewFilterValue(In, uids)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || len(list.Objects) != len(uids) {
+		t.Error("request was split without an envelope encoder", calls, len(list.Objects))
+	}
+}
+
+// decodeOnly is an EnvelopeStrategy that doesn't implement EnvelopeEncoder.
+type decodeOnly struct {
+	envelope KeyEnvelope
+}
+
+func (d decodeOnly) Decode(body []byte) ([]json.RawMessage, PageInfo, error) {
+	return d.envelope.Decode(body)
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WithStaleIfError keeps the bodies of the last size successful GET responses for up to maxAge,
+// zero keeps them until they are evicted. If a later request for the same URL fails with a 5xx response
+// or times out, the kept body is decoded instead of returning the error and Result.Stale is set.
+func WithStaleIfError(size int, maxAge time.Duration) Option {
+	return func(s *settings) {
+		s.stale = NewObjectCache(size, maxAge)
+		if s.clock != nil {
+			s.stale.SetClock(s.clock)
+		}
+	}
+}
+
+// staleTarget returns where the response of a call is decoded to, a raw message if it should be kept for WithStaleIfError.
+func (s *settings) staleTarget(method string, v interface{}) (interface{}, *json.RawMessage) {
+	if _, ok := v.(*objectStream); ok || s.stale == nil || method != http.MethodGet || v == nil {
+		return v, nil
+	}
+	raw := &json.RawMessage{}
+	return raw, raw
+}
+
+// keepStale stores a successful response body that was decoded to raw and decodes it into v.
+func (s *settings) keepStale(u *url.URL, raw *json.RawMessage, v interface{}) error {
+	if len(*raw) == 0 {
+		return nil
+	}
+	s.stale.Add(http.MethodGet, u.String(), *raw)
+	return json.Unmarshal(*raw, v)
+}
+
+// serveStale decodes the kept response for the URL into v if err allows serving it.
+func (s *settings) serveStale(ctx context.Context, method string, u *url.URL, v interface{}, err error) bool {
+	if _, ok := v.(*objectStream); ok || s.stale == nil || method != http.MethodGet || v == nil || !staleable(err) {
+		return false
+	}
+	body, ok := s.stale.Get(http.MethodGet, u.String())
+	if !ok || json.Unmarshal(body, v) != nil {
+		return false
+	}
+	s.log(ctx, "serving stale response", "url", s.maskURL(u), "error", err)
+	return true
+}
+
+// staleable reports whether err is a 5xx response or a timeout.
+func staleable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout()
+}
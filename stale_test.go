@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStaleIfError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Write([]byte(`{"title":"cached"}`))
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		case 3:
+			time.Sleep(100 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithStaleIfError(10, 0))
+	var v struct {
+		Title string `json:"title"`
+	}
+	res, err := c.DoResult(c.NewRequest("sets", "set_1"), &v)
+	if err != nil || res.Stale {
+		t.Fatal("unexpected first response", err, res.Stale)
+	}
+
+	v.Title = ""
+	res, err = c.DoResult(c.NewRequest("sets", "set_1"), &v)
+	if err != nil || !res.Stale || v.Title != "cached" {
+		t.Fatal("expected the stale response for a 5xx", err, res.Stale, v.Title)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	v.Title = ""
+	res, err = c.DoResult(c.NewRequest("sets", "set_1").WithContext(ctx), &v)
+	if err != nil || !res.Stale || v.Title != "cached" {
+		t.Fatal("expected the stale response for a timeout", err, res.Stale, v.Title)
+	}
+
+	if err := c.Do(c.NewRequest("sets", "set_1"), &v); !IsNotFound(err) {
+		t.Error("expected client errors to be returned", err)
+	}
+	if err := c.Do(c.NewRequest("sets", "set_2"), &v); err == nil {
+		t.Error("expected an error without a kept response")
+	}
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Classes of errors counted in Stats.Errors.
+const (
+	ErrorClassClient      = "client"
+	ErrorClassServer      = "server"
+	ErrorClassRateLimited = "rate_limited"
+	ErrorClassTimeout     = "timeout"
+	ErrorClassCanceled    = "canceled"
+	ErrorClassNetwork     = "network"
+)
+
+// Stats are cumulative counters of the requests a client sent since it was created.
+type Stats struct {
+	// Requests counts the requests sent per collection, including failed ones and not counting retries.
+	Requests map[string]int64
+	// Errors counts the failed requests per error class, like ErrorClassServer.
+	Errors map[string]int64
+	// Retries is the number of attempts after the first one.
+	Retries int64
+	// CacheHits and CacheMisses count the GET requests checked against the cache set with WithCache,
+	// a hit is a response served from the cache.
+	CacheHits   int64
+	CacheMisses int64
+	// BytesSent and BytesReceived are the sizes of the request bodies sent and the response bodies read
+	// as they were on the wire.
+	BytesSent     int64
+	BytesReceived int64
+}
+
+// CacheHitRate returns the fraction of cache lookups that were hits, 0 if there were none.
+func (s Stats) CacheHitRate() float64 {
+	if s.CacheHits+s.CacheMisses == 0 {
+		return 0
+	}
+	return float64(s.CacheHits) / float64(s.CacheHits+s.CacheMisses)
+}
+
+// Stats returns a snapshot of the client's counters.
+func (c *Client) Stats() Stats {
+	return c.current().stats.snapshot()
+}
+
+// clientStats collects the counters of a client. It is shared by all its configurations.
+type clientStats struct {
+	mu       sync.Mutex
+	requests map[string]int64
+	errors   map[string]int64
+	retries  int64
+
+	cacheHits     int64
+	cacheMisses   int64
+	bytesSent     int64
+	bytesReceived int64
+}
+
+func (s *clientStats) snapshot() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := Stats{
+		Requests:      make(map[string]int64, len(s.requests)),
+		Errors:        make(map[string]int64, len(s.errors)),
+		Retries:       s.retries,
+		CacheHits:     atomic.LoadInt64(&s.cacheHits),
+		CacheMisses:   atomic.LoadInt64(&s.cacheMisses),
+		BytesSent:     atomic.LoadInt64(&s.bytesSent),
+		BytesReceived: atomic.LoadInt64(&s.bytesReceived),
+	}
+	for collection, n := range s.requests {
+		stats.Requests[collection] = n
+	}
+	for class, n := range s.errors {
+		stats.Errors[class] = n
+	}
+	return stats
+}
+
+// request counts an executed request that made the given number of attempts.
+func (s *clientStats) request(collection string, attempts int, err error) {
+	if s == nil || attempts == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.requests == nil {
+		s.requests = make(map[string]int64)
+		s.errors = make(map[string]int64)
+	}
+	s.requests[collection]++
+	s.retries += int64(attempts - 1)
+	if err != nil {
+		s.errors[errorClass(err)]++
+	}
+}
+
+// cacheLookup counts a cache hit or miss.
+func (s *clientStats) cacheLookup(hit bool) {
+	if s == nil {
+		return
+	}
+	if hit {
+		atomic.AddInt64(&s.cacheHits, 1)
+	} else {
+		atomic.AddInt64(&s.cacheMisses, 1)
+	}
+}
+
+// errorClass returns the class of a request error for Stats.Errors.
+func errorClass(err error) string {
+	var apiErr *APIError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests:
+		return ErrorClassRateLimited
+	case errors.As(err, &apiErr) && apiErr.StatusCode >= 500:
+		return ErrorClassServer
+	case errors.As(err, &apiErr):
+		return ErrorClassClient
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	}
+	return ErrorClassNetwork
+}
+
+// counted counts the bytes of request and response bodies.
+func (s *settings) counted(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if req.ContentLength > 0 {
+			atomic.AddInt64(&s.stats.bytesSent, req.ContentLength)
+		}
+		res, err := next(req)
+		if err == nil {
+			res.Body = &countingBody{ReadCloser: res.Body, n: &s.stats.bytesReceived}
+		}
+		return res, err
+	}
+}
+
+// countingBody adds the number of bytes read to n.
+type countingBody struct {
+	io.ReadCloser
+	n *int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	atomic.AddInt64(b.n, int64(n))
+	return n, err
+}
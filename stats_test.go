@@ -0,0 +1,70 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	failures := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/missing/":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case failures > 0:
+			failures--
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"uid":"1"}`))
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	c := NewClient(WithBaseURL(server.URL), WithSleeper(clock), WithCache(NewLRUCache(10)),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3}))
+	for i := 0; i < 3; i++ {
+		if err := c.NewRequest("episodes", "").Execute(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	failures = 1
+	var episode struct{ UID string }
+	if err := c.NewRequest("episodes", "").SkipCache().Execute(&episode); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.NewRequest("missing", "").Execute(nil); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	stats := c.Stats()
+	if stats.Requests["episodes"] != 4 || stats.Requests["missing"] != 1 {
+		t.Error("unexpected requests", stats.Requests)
+	}
+	if stats.Errors[ErrorClassClient] != 1 || len(stats.Errors) != 1 {
+		t.Error("unexpected errors", stats.Errors)
+	}
+	if stats.Retries != 1 {
+		t.Error("unexpected retries", stats.Retries)
+	}
+	if stats.CacheHits != 2 || stats.CacheMisses != 2 || stats.CacheHitRate() != 0.5 {
+		t.Error("unexpected cache counters", stats.CacheHits, stats.CacheMisses)
+	}
+	if stats.BytesReceived != 2*int64(len(`{"uid":"1"}`)) {
+		t.Error("unexpected bytes received", stats.BytesReceived)
+	}
+
+	// the counters survive configuration updates and snapshots are copies
+	stats.Requests["episodes"] = 0
+	c.UpdateConfig(WithTimeout(time.Second))
+	if c.Stats().Requests["episodes"] != 4 {
+		t.Error("counters were reset")
+	}
+}
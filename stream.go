@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ExecuteStream executes a collection request and calls fn for every object of the response while it is read,
+// instead of decoding the whole response at once. Decoding stops at the first error returned by fn,
+// which is returned, or when the request's context is done.
+// A request is not retried once fn was called, since objects would be passed to fn again.
+// Streamed responses bypass the client's cache, which would buffer them.
+func (r *Request) ExecuteStream(fn func(object json.RawMessage) error) (err error) {
+	c := r.executor()
+	defer c.recoverPanic(&err)
+	if d := r.dedupe; d != nil {
+		next := fn
+		fn = func(object json.RawMessage) error {
+			if d.SeenObject(object) {
+				return nil
+			}
+			return next(object)
+		}
+	}
+	stream := r.copy()
+	stream.skipCache = true
+	return c.transmit(stream, http.MethodGet, nil, jsonContentType, &objectStream{ctx: r.ctx, fn: fn}, nil)
+}
+
+// objectStream is decoded by execute, passing the objects of a collection response to fn one at a time.
+type objectStream struct {
+	ctx     context.Context
+	fn      func(json.RawMessage) error
+	started bool
+}
+
+// streamError is an error after objects were passed to the stream, the request must not be retried.
+type streamError struct {
+	err error
+}
+
+func (e *streamError) Error() string { return e.err.Error() }
+func (e *streamError) Unwrap() error { return e.err }
+
+// decode reads the response and passes the elements of its objects array to fn.
+func (st *objectStream) decode(body io.Reader) error {
+	err := st.read(json.NewDecoder(body))
+	if err != nil && st.started {
+		return &streamError{err}
+	}
+	return err
+}
+
+func (st *objectStream) read(dec *json.Decoder) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if key != "objects" {
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := expectDelim(dec, '['); err != nil {
+			return err
+		}
+		for dec.More() {
+			if err := st.ctx.Err(); err != nil {
+				return err
+			}
+			var object json.RawMessage
+			if err := dec.Decode(&object); err != nil {
+				return err
+			}
+			st.started = true
+			if err := st.fn(object); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t != delim {
+		return fmt.Errorf("invalid response: expected %v, got %v", delim, t)
+	}
+	return nil
+}
+
+// isStreamError reports whether err occurred after objects were passed to a stream.
+func isStreamError(err error) bool {
+	var se *streamError
+	return errors.As(err, &se)
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExecuteStream(t *testing.T) {
+	var sent int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		var objects []string
+		for i := 0; i < 100; i++ {
+			objects = append(objects, fmt.Sprintf(`{"uid": "ep_%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"meta": {"total_count": 100}, "objects": [%s], "extra": [1, {"a": 2}]}`, strings.Join(objects, ","))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 3}))
+	var uids []string
+	err := c.NewRequest("episodes", "").ExecuteStream(func(object json.RawMessage) error {
+		var ep episode
+		if err := json.Unmarshal(object, &ep); err != nil {
+			return err
+		}
+		uids = append(uids, ep.UID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uids) != 100 || uids[99] != "ep_99" {
+		t.Error("incorrect objects", len(uids))
+	}
+
+	stop := errors.New("stop")
+	var n int
+	err = c.NewRequest("episodes", "").ExecuteStream(func(object json.RawMessage) error {
+		n++
+		if n == 10 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || n != 10 {
+		t.Error("expected the stream to stop", err, n)
+	}
+	if sent != 2 {
+		t.Error("stopped stream was retried", sent)
+	}
+}
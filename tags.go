@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// taggedField is a struct field with a golark tag like `golark:"image_urls,expand"`.
+type taggedField struct {
+	index  int
+	name   string
+	expand bool
+}
+
+// taggedFields returns the golark tagged fields of a struct type.
+// Unexported fields are skipped like encoding/json does, they can't be set.
+func taggedFields(t reflect.Type) []taggedField {
+	var fields []taggedField
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("golark")
+		if !ok || tag == "-" || t.Field(i).PkgPath != "" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		f := taggedField{index: i, name: parts[0]}
+		for _, opt := range parts[1:] {
+			if opt == "expand" {
+				f.expand = true
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// elemType removes pointers and slices around a type.
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isTagged reports whether values of the type are decoded by their golark tags.
+func isTagged(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(unmarshalerType) && len(taggedFields(t)) > 0
+}
+
+// fieldsFromType builds the request fields for a struct type from its golark tags.
+// Expanded fields whose type is a tagged struct select that struct's fields as sub fields.
+func fieldsFromType(t reflect.Type, seen map[reflect.Type]bool) ([]*Field, error) {
+	t = elemType(t)
+	if !isTagged(t) {
+		return nil, fmt.Errorf("%s has no golark tags", t)
+	}
+	if seen[t] {
+		return nil, fmt.Errorf("%s references itself through expanded fields", t)
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	var fields []*Field
+	for _, tf := range taggedFields(t) {
+		f := NewField(tf.name)
+		if tf.expand {
+			f.IsExpanded = true
+			if sub := elemType(t.Field(tf.index).Type); isTagged(sub) {
+				subFields, err := fieldsFromType(sub, seen)
+				if err != nil {
+					return nil, err
+				}
+				for _, subField := range subFields {
+					f.WithSubField(subField)
+				}
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// decodeTagged decodes JSON into v, matching object keys to golark tags for tagged structs.
+func decodeTagged(data json.RawMessage, v reflect.Value) error {
+	if string(data) == "null" {
+		return nil
+	}
+	switch {
+	case v.Kind() == reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeTagged(data, v.Elem())
+	case v.Kind() == reflect.Slice && isTagged(elemType(v.Type())):
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeTagged(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	case isTagged(v.Type()):
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		for _, tf := range taggedFields(v.Type()) {
+			raw, ok := object[tf.name]
+			if !ok {
+				continue
+			}
+			if err := decodeTagged(raw, v.Field(tf.index)); err != nil {
+				return fmt.Errorf("unable to decode %s: %w", tf.name, err)
+			}
+		}
+		return nil
+	}
+	return json.Unmarshal(data, v.Addr().Interface())
+}
+
+// RequestFromStruct returns a request for the fields and expansions described by the golark tags of v,
+// see Request.FieldsFromStruct.
+func RequestFromStruct(endpoint, collection, id string, v interface{}) *Request {
+	return NewRequest(endpoint, collection, id).FieldsFromStruct(v)
+}
+
+// FieldsFromStruct adds the fields and expansions described by the golark tags of v, a tagged struct,
+// a slice of them or a pointer to either. Fields of expanded tagged structs are added as sub fields.
+// If v has no golark tags executing the request fails. Decode the response with ExecuteTagged.
+func (r *Request) FieldsFromStruct(v interface{}) *Request {
+	fields, err := fieldsFromType(reflect.TypeOf(v), make(map[reflect.Type]bool))
+	if err != nil {
+		if r.err == nil {
+			r.err = err
+		}
+		return r
+	}
+	for _, f := range fields {
+		r.AddField(f)
+	}
+	return r
+}
+
+// Fetch requests the fields and expansions described by the golark tags of dst and decodes the response into it.
+// dst must be a pointer to a tagged struct for a single object, or a pointer to a slice of them for a collection request.
+//
+//	type Episode struct {
+//		Title  string  `golark:"title"`
+//		Images []Image `golark:"image_urls,expand"`
+//	}
+//
+// Fields of expanded tagged structs are requested as sub fields.
+func (c *Client) Fetch(ctx context.Context, collection, id string, dst interface{}) error {
+	return c.NewRequest(collection, id).WithContext(ctx).FieldsFromStruct(dst).ExecuteTagged(dst)
+}
+
+// ExecuteTagged executes the request and decodes the response into dst by its golark tags,
+// dst must be a pointer to a tagged struct or to a slice of them for a collection request.
+func (r *Request) ExecuteTagged(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("destination must be a non-nil pointer, got %T", dst)
+	}
+
+	var data json.RawMessage
+	if err := r.Execute(&data); err != nil {
+		return err
+	}
+	if v.Elem().Kind() == reflect.Slice {
+		objects, _, err := r.executor().current().decodeEnvelope(data)
+		if err != nil {
+			return err
+		}
+		if data, err = json.Marshal(objects); err != nil {
+			return err
+		}
+	}
+	return decodeTagged(data, v.Elem())
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type taggedImage struct {
+	URL   string `golark:"url"`
+	Title string `golark:"title"`
+}
+
+type taggedEpisode struct {
+	Title   string        `golark:"title"`
+	Images  []taggedImage `golark:"image_urls,expand"`
+	Ignored string
+	// unexported fields are skipped even if they are tagged
+	slug string `golark:"slug"`
+}
+
+func TestFetch(t *testing.T) {
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		if r.URL.Path == "/episodes/ep_1/" {
+			w.Write([]byte(`{"title": "Race", "Ignored": "x", "slug": "race", "image_urls": [{"url": "a.jpg", "title": "A"}]}`))
+			return
+		}
+		w.Write([]byte(`{"objects": [{"title": "Race"}, {"title": "Qualifying"}]}`))
+	}))
+	defer server.Close()
+	c := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	var ep taggedEpisode
+	if err := c.Fetch(ctx, "episodes", "ep_1", &ep); err != nil {
+		t.Fatal(err)
+	}
+	if ep.Title != "Race" || len(ep.Images) != 1 || ep.Images[0].URL != "a.jpg" || ep.Ignored != "" || ep.slug != "" {
+		t.Error("incorrect episode", ep)
+	}
+	compareCSV("title,image_urls,image_urls__url,image_urls__title", query["fields"][0], t)
+	compareCSV("image_urls", query["fields_to_expand"][0], t)
+
+	var eps []*taggedEpisode
+	if err := c.Fetch(ctx, "episodes", "", &eps); err != nil {
+		t.Fatal(err)
+	}
+	if len(eps) != 2 || eps[1].Title != "Qualifying" {
+		t.Error("incorrect episodes", eps)
+	}
+
+	if err := c.Fetch(ctx, "episodes", "ep_1", &episode{}); err == nil {
+		t.Error("expected error for untagged destination")
+	}
+}
+
+func TestFieldsFromStruct(t *testing.T) {
+	r := RequestFromStruct("https://test.com/api/", "episodes", "", []taggedEpisode{})
+	u, err := r.ToURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareCSV("title,image_urls,image_urls__url,image_urls__title", u.Query().Get("fields"), t)
+	compareCSV("image_urls", u.Query().Get("fields_to_expand"), t)
+
+	if _, err := NewRequest("https://test.com/api/", "episodes", "").FieldsFromStruct(episode{}).ToURL(); err == nil {
+		t.Error("expected error for an untagged struct")
+	}
+}
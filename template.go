@@ -0,0 +1,67 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// WithPathTemplate sets the path of a collection relative to the endpoint, for backends that don't use
+// the standard <collection>/<id>/ layout. Placeholders in braces are replaced with the request's path params,
+// {id} is replaced with the request's ID and dropped together with the following slash if the ID is empty.
+//
+//	WithPathTemplate("items", "sets/{set}/items/{id}/")
+func WithPathTemplate(collection, template string) Option {
+	return func(s *settings) {
+		if s.pathTemplates == nil {
+			s.pathTemplates = make(map[string]string)
+		}
+		s.pathTemplates[collection] = template
+	}
+}
+
+// WithPathParam sets a value for a placeholder in the collection's path template.
+func (r *Request) WithPathParam(name, value string) *Request {
+	if r.pathParams == nil {
+		r.pathParams = make(map[string]string)
+	}
+	r.pathParams[name] = value
+	return r
+}
+
+// renderPath fills in the template with the request's ID and path params, which must be valid path segments.
+func renderPath(template string, r *Request) (string, error) {
+	if r.ID == "" {
+		template = strings.Replace(template, "{id}/", "", 1)
+		template = strings.Replace(template, "{id}", "", 1)
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			b.WriteString(template)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("invalid path template %q: unclosed placeholder", template)
+		}
+		end += start
+		name := template[start+1 : end]
+
+		value, ok := r.pathParams[name]
+		if name == "id" {
+			value, ok = r.ID, true
+		}
+		if !ok || value == "" {
+			return "", fmt.Errorf("missing path param %s for collection %s", name, r.Collection)
+		}
+		if err := checkSegment("path param "+name, value); err != nil {
+			return "", err
+		}
+		b.WriteString(template[:start])
+		b.WriteString(url.PathEscape(value))
+		template = template[end+1:]
+	}
+}
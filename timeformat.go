@@ -0,0 +1,28 @@
+package client
+
+import (
+	"strconv"
+	"time"
+)
+
+// TimeEncoder encodes times used as filter and query parameter values.
+type TimeEncoder func(time.Time) string
+
+var (
+	// TimeRFC3339 encodes times like 2006-01-02T15:04:05Z07:00, it is the default.
+	TimeRFC3339 TimeEncoder = func(t time.Time) string { return t.Format(time.RFC3339) }
+	// TimeDateOnly encodes the date of times in UTC like 2006-01-02.
+	TimeDateOnly TimeEncoder = func(t time.Time) string { return t.UTC().Format("2006-01-02") }
+	// TimeEpoch encodes times as seconds since the Unix epoch.
+	TimeEpoch TimeEncoder = func(t time.Time) string { return strconv.FormatInt(t.Unix(), 10) }
+	// TimeEpochMillis encodes times as milliseconds since the Unix epoch.
+	TimeEpochMillis TimeEncoder = func(t time.Time) string { return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10) }
+)
+
+// WithTimeEncoding sets how the client encodes times in filters and parameters created from time.Time values,
+// for example with NewFilterValue or Request.WithParam. Requests converted with ToURL use TimeRFC3339.
+func WithTimeEncoding(enc TimeEncoder) Option {
+	return func(s *settings) {
+		s.timeEncoder = enc
+	}
+}
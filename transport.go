@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// errCustomTransport is returned by options that modify the transport when the HTTP client uses a custom RoundTripper.
+var errCustomTransport = errors.New("transport options require the HTTP client to use an *http.Transport")
+
+// modifyTransport applies fn to a copy of the client's transport, so connections of
+// requests using the previous configuration are not affected.
+// fn is applied again to the transport of an HTTP client set later with WithHTTPClient.
+func (s *settings) modifyTransport(fn func(t *http.Transport)) {
+	s.transportOptions = append(s.transportOptions[:len(s.transportOptions):len(s.transportOptions)], fn)
+	s.applyTransport(fn)
+}
+
+// applyTransport applies fn to a copy of the client's transport.
+func (s *settings) applyTransport(fn func(t *http.Transport)) {
+	var t *http.Transport
+	switch rt := s.httpClient.Transport.(type) {
+	case nil:
+		def, ok := http.DefaultTransport.(*http.Transport)
+		if !ok {
+			s.err = errCustomTransport
+			return
+		}
+		t = def.Clone()
+	case *http.Transport:
+		t = rt.Clone()
+	default:
+		s.err = errCustomTransport
+		return
+	}
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	fn(t)
+	s.httpClient.Transport = t
+}
+
+// WithTLSMinVersion sets the minimum TLS version, for example tls.VersionTLS13.
+func WithTLSMinVersion(version uint16) Option {
+	return func(s *settings) {
+		s.modifyTransport(func(t *http.Transport) {
+			t.TLSClientConfig.MinVersion = version
+		})
+	}
+}
+
+// WithCipherSuites limits the cipher suites used for TLS 1.2 and below.
+// TLS 1.3 cipher suites are not configurable.
+func WithCipherSuites(suites ...uint16) Option {
+	return func(s *settings) {
+		s.modifyTransport(func(t *http.Transport) {
+			t.TLSClientConfig.CipherSuites = suites
+		})
+	}
+}
+
+// IPPreference selects the IP versions used to connect to the API.
+type IPPreference int
+
+const (
+	// DualStack connects over IPv4 or IPv6, racing them with Happy Eyeballs. It is the default.
+	DualStack IPPreference = iota
+	// IPv4Only only connects over IPv4.
+	IPv4Only
+	// IPv6Only only connects over IPv6.
+	IPv6Only
+)
+
+// WithIPPreference restricts the IP versions used to connect, for example to avoid a broken IPv6 path.
+func WithIPPreference(p IPPreference) Option {
+	return func(s *settings) {
+		s.ipPreference = p
+		s.installDialer()
+	}
+}
+
+// WithHappyEyeballsDelay sets how long a dual stack connection attempt waits for the preferred address family
+// before trying the other one in parallel. A negative delay disables the parallel attempt.
+func WithHappyEyeballsDelay(d time.Duration) Option {
+	return func(s *settings) {
+		s.fallbackDelay = d
+		s.installDialer()
+	}
+}
+
+// installDialer sets a dialer using the client's IP preference on the transport.
+func (s *settings) installDialer() {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, FallbackDelay: s.fallbackDelay}
+	preference := s.ipPreference
+	s.modifyTransport(func(t *http.Transport) {
+		t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if network == "tcp" {
+				switch preference {
+				case IPv4Only:
+					network = "tcp4"
+				case IPv6Only:
+					network = "tcp6"
+				}
+			}
+			return dialer.DialContext(ctx, network, addr)
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTLSMinVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithTLSMinVersion(tls.VersionTLS13))
+	c.current().httpClient.Transport.(*http.Transport).TLSClientConfig.RootCAs = server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	if err := c.NewRequest("sets", "").Execute(&struct{}{}); err == nil {
+		t.Error("expected handshake to fail with TLS 1.2 server")
+	}
+
+	c = NewClient(WithBaseURL(server.URL), WithTLSMinVersion(tls.VersionTLS12))
+	c.current().httpClient.Transport.(*http.Transport).TLSClientConfig.RootCAs = server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	if err := c.NewRequest("sets", "").Execute(&struct{}{}); err != nil {
+		t.Error("unexpected error:", err)
+	}
+
+	// the minimum version is kept when the HTTP client is set later, at construction or by UpdateConfig
+	c = NewClient(WithBaseURL(server.URL), WithTLSMinVersion(tls.VersionTLS13), WithHTTPClient(server.Client()))
+	if err := c.NewRequest("sets", "").Execute(&struct{}{}); err == nil {
+		t.Error("expected handshake to fail after setting an HTTP client")
+	}
+	c = NewClient(WithBaseURL(server.URL), WithTLSMinVersion(tls.VersionTLS13))
+	if err := c.UpdateConfig(WithHTTPClient(server.Client())); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.NewRequest("sets", "").Execute(&struct{}{}); err == nil {
+		t.Error("expected handshake to fail after updating the HTTP client")
+	}
+}
+
+func TestPinnedPublicKeys(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	rootCAs := server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	tests := []struct {
+		pin   string
+		valid bool
+	}{
+		{PublicKeyHash(server.Certificate()), true},
+		{"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=", false},
+	}
+
+	for _, test := range tests {
+		c := NewClient(WithBaseURL(server.URL), WithPinnedPublicKeys(test.pin))
+		c.current().httpClient.Transport.(*http.Transport).TLSClientConfig.RootCAs = rootCAs
+		err := c.NewRequest("sets", "").Execute(&struct{}{})
+		if test.valid && err != nil {
+			t.Error("unexpected error:", err)
+		}
+		if !test.valid && !errors.Is(err, ErrPinMismatch) {
+			t.Error("expected pin mismatch, got", err)
+		}
+	}
+}
+
+func TestPinsWithLaterHTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	const pin = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+
+	// the server's client trusts its certificate, only the pin can reject it
+	c := NewClient(WithBaseURL(server.URL), WithPinnedPublicKeys(pin), WithHTTPClient(server.Client()))
+	if err := c.NewRequest("sets", "").Execute(&struct{}{}); !errors.Is(err, ErrPinMismatch) {
+		t.Error("expected pin mismatch after setting an HTTP client, got", err)
+	}
+
+	c = NewClient(WithBaseURL(server.URL), WithPinnedPublicKeys(pin), WithTLSMinVersion(tls.VersionTLS12))
+	if err := c.UpdateConfig(WithHTTPClient(server.Client())); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.NewRequest("sets", "").Execute(&struct{}{}); !errors.Is(err, ErrPinMismatch) {
+		t.Error("expected pin mismatch after updating the HTTP client, got", err)
+	}
+
+	c = NewClient(WithBaseURL(server.URL), WithPinnedPublicKeys(pin), WithHTTPClient(&http.Client{Transport: roundTripperFunc(nil)}))
+	if err := c.NewRequest("sets", "").Execute(&struct{}{}); !errors.Is(err, errCustomTransport) {
+		t.Error("expected custom transport error, got", err)
+	}
+}
+
+func TestHeaderAllowlist(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	c := NewClient(
+		WithBaseURL(server.URL),
+		WithHeader("Authorization", "Bearer token"),
+		WithHeader("X-Debug", "true"),
+		WithHeaderAllowlist("authorization"),
+		WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("X-Leak", "true")
+				return next(req)
+			}
+		}))
+	if err := c.NewRequest("sets", "").Execute(&struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Get("Authorization") != "Bearer token" {
+		t.Error("allowlisted header was removed")
+	}
+	if header.Get("X-Debug") != "" {
+		t.Error("header not on the allowlist was sent")
+	}
+	if header.Get("X-Leak") != "" {
+		t.Error("header set by a middleware was sent")
+	}
+}
+
+func TestContextProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Write([]byte("{}"))
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	c := NewClient(WithBaseURL("http://skylark.test/api/"), WithContextProxy(nil))
+	if err := c.NewRequest("sets", "").WithProxy(proxyURL).Execute(&struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if proxied != "http://skylark.test/api/sets/" {
+		t.Error("request was not sent through proxy", proxied)
+	}
+
+	proxied = ""
+	if err := c.NewRequest("sets", "").WithProxy(proxyURL).WithContext(context.Background()).Execute(&struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if proxied != "http://skylark.test/api/sets/" {
+		t.Error("replacing the context removed the proxy", proxied)
+	}
+
+	c = NewClient(WithBaseURL("http://skylark.test/api/"))
+	if err := c.NewRequest("sets", "").WithProxy(proxyURL).Execute(&struct{}{}); !errors.Is(err, ErrProxyNotEnabled) {
+		t.Error("expected proxy not enabled error, got", err)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestTLSOptionCustomTransport(t *testing.T) {
+	s := &settings{httpClient: &http.Client{Transport: roundTripperFunc(nil)}}
+	WithTLSMinVersion(tls.VersionTLS13)(s)
+	if !errors.Is(s.err, errCustomTransport) {
+		t.Error("expected custom transport error, got", s.err)
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	var header http.Header
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		header = req.Header
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("{}"))}, nil
+	})
+	httpClient := &http.Client{Transport: rt}
+	c := NewClient(
+		WithHTTPClient(httpClient),
+		WithBaseURL("https://test.com"),
+		WithDefaultHeaders(http.Header{"x-team": {"ops"}}),
+		WithTimeout(time.Second),
+	)
+	if err := c.NewRequest("episodes", "").Execute(&struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("X-Team") != "ops" {
+		t.Error("default header not sent", header)
+	}
+	if httpClient.Timeout != 0 {
+		t.Error("options modified the passed HTTP client")
+	}
+}
+
+func TestIPPreference(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	// the test server listens on 127.0.0.1, dial it by name so the IP version is picked by the dialer
+	endpoint := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	c := NewClient(WithBaseURL(endpoint), WithIPPreference(IPv4Only), WithHappyEyeballsDelay(-1))
+	if err := c.Do(c.NewRequest("sets", ""), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	c = NewClient(WithBaseURL(server.URL), WithIPPreference(IPv6Only))
+	if err := c.Do(c.NewRequest("sets", ""), nil); err == nil {
+		t.Error("expected an IPv6 only client to fail connecting to an IPv4 address")
+	}
+}
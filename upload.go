@@ -0,0 +1,77 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+)
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// Upload sends the content of r as a multipart/form-data POST to the request's URL and decodes the response into result.
+// The part's content type is derived from the filename's extension, or detected from the content.
+// The content is read completely before the upload starts, uploads are not retried.
+func (r *Request) Upload(ctx context.Context, fieldName, filename string, content io.Reader, result interface{}) (err error) {
+	data, err := ioutil.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(filename))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(fieldName), quoteEscaper.Replace(filepath.Base(filename))))
+	header.Set("Content-Type", contentType)
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	upload := r.copy()
+	upload.ctx = ctx
+	c := upload.executor()
+	defer c.recoverPanic(&err)
+	return c.transmit(upload, http.MethodPost, body.Bytes(), w.FormDataContentType(), result, nil)
+}
+
+// WithUploadProgress sets a function called while the request body is sent with the number of bytes sent so far.
+func (r *Request) WithUploadProgress(fn func(sent, total int64)) *Request {
+	r.uploadProgress = fn
+	return r
+}
+
+// progressReader reports the number of bytes read from r.
+type progressReader struct {
+	r     io.Reader
+	sent  int64
+	total int64
+	fn    func(sent, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.sent += int64(n)
+		p.fn(p.sent, p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUpload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		content, _ := ioutil.ReadAll(file)
+		if header.Filename != "poster.png" || header.Header.Get("Content-Type") != "image/png" || string(content) != "png data" {
+			t.Error("incorrect upload", header.Filename, header.Header, string(content))
+		}
+		w.Write([]byte(`{"uid": "img_1"}`))
+	}))
+	defer server.Close()
+
+	var sent int64
+	var res struct {
+		UID string `json:"uid"`
+	}
+	err := NewClient(WithBaseURL(server.URL)).NewRequest("images", "").
+		WithUploadProgress(func(n, total int64) { sent = n }).
+		Upload(context.Background(), "image", "posters/poster.png", strings.NewReader("png data"), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.UID != "img_1" {
+		t.Error("incorrect result", res)
+	}
+	if sent == 0 {
+		t.Error("progress was not reported")
+	}
+}
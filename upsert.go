@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrAmbiguousMatch is returned by Upsert when the match filter matches more than one object.
+var ErrAmbiguousMatch = errors.New("match filter matches more than one object")
+
+// Upsert searches the collection for an object matching all filters in match.
+// If exactly one object matches it is patched with body, if none match a new object is created from body.
+// The server's response is written to v, the returned bool reports whether an object was created.
+// Upsert is not atomic on the server, concurrent calls with the same match filter can create duplicates.
+func (c *Client) Upsert(ctx context.Context, collection string, match map[string]*Filter, body, v interface{}) (bool, error) {
+	if len(match) == 0 {
+		return false, errors.New("upsert requires a match filter")
+	}
+
+	search := c.NewRequest(collection, "").
+		WithContext(ctx).
+		AddField(NewField("uid")).
+		Limit(2)
+	for field, filter := range match {
+		search.WithFilter(field, filter)
+	}
+
+	var res struct {
+		Objects []struct {
+			UID string `json:"uid"`
+		} `json:"objects"`
+	}
+	if err := search.Execute(&res); err != nil {
+		return false, fmt.Errorf("upsert search failed: %w", err)
+	}
+
+	switch len(res.Objects) {
+	case 0:
+		return true, c.send(c.NewRequest(collection, "").WithContext(ctx), http.MethodPost, body, v)
+	case 1:
+		return false, c.send(c.NewRequest(collection, res.Objects[0].UID).WithContext(ctx), http.MethodPatch, body, v)
+	default:
+		return false, ErrAmbiguousMatch
+	}
+}
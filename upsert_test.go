@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpsert(t *testing.T) {
+	var method, path string
+	existing := map[string]bool{"monaco": true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			var res struct {
+				Objects []map[string]string `json:"objects"`
+			}
+			if existing[r.URL.Query().Get("slug")] {
+				res.Objects = append(res.Objects, map[string]string{"uid": "set_123"})
+			}
+			json.NewEncoder(w).Encode(res)
+			return
+		}
+		method, path = r.Method, r.URL.Path
+		w.Write([]byte(`{"uid": "set_123"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	tests := []struct {
+		slug    string
+		created bool
+		method  string
+		path    string
+	}{
+		{"monaco", false, http.MethodPatch, "/sets/set_123/"},
+		{"imola", true, http.MethodPost, "/sets/"},
+	}
+
+	for _, test := range tests {
+		var res map[string]string
+		created, err := c.Upsert(context.Background(), "sets",
+			map[string]*Filter{"slug": NewFilter(Equals, test.slug)},
+			map[string]string{"slug": test.slug}, &res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created != test.created || method != test.method || path != test.path {
+			t.Errorf("incorrect upsert for %s: created %v with %s %s", test.slug, created, method, path)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package client
 
-import "net/url"
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
 
 func addValue(v url.Values, key string, value string) url.Values {
 	values := v.Get(key)
@@ -11,3 +16,43 @@ func addValue(v url.Values, key string, value string) url.Values {
 	v.Set(key, values)
 	return v
 }
+
+// ErrInvalidEndpoint is returned when a request or client is given an endpoint that can't be used.
+var ErrInvalidEndpoint = errors.New("invalid endpoint")
+
+// ErrInvalidPath is returned when a request's collection or ID can't be used as a path segment.
+var ErrInvalidPath = errors.New("invalid path")
+
+// checkSegment validates a collection or ID, they are escaped so they can contain any other characters.
+func checkSegment(kind, segment string) error {
+	switch {
+	case segment == "":
+		return fmt.Errorf("%w: empty %s", ErrInvalidPath, kind)
+	case segment == "." || segment == "..":
+		return fmt.Errorf("%w: %s %q", ErrInvalidPath, kind, segment)
+	case strings.IndexFunc(segment, func(r rune) bool { return r < 0x20 || r == 0x7f }) >= 0:
+		return fmt.Errorf("%w: %s %q contains control characters", ErrInvalidPath, kind, segment)
+	}
+	return nil
+}
+
+// normalizeEndpoint validates the endpoint and makes sure it ends with a slash.
+func normalizeEndpoint(endpoint string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %v", ErrInvalidEndpoint, endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%w %q: scheme must be http or https", ErrInvalidEndpoint, endpoint)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("%w %q: missing host", ErrInvalidEndpoint, endpoint)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("%w %q: must not contain a query or fragment", ErrInvalidEndpoint, endpoint)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Validator checks the JSON body of a write request before it is sent.
+// The method is http.MethodPost, http.MethodPut or http.MethodPatch.
+type Validator func(method string, body map[string]interface{}) error
+
+// ValidationError is returned when a write request is rejected by a validator.
+type ValidationError struct {
+	Collection string
+	Err        error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s body: %v", e.Collection, e.Err)
+}
+
+// Unwrap returns the error returned by the validator.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// WithValidator registers a validator that runs on every write request for the collection.
+func WithValidator(collection string, v Validator) Option {
+	return func(s *settings) {
+		if s.validators == nil {
+			s.validators = make(map[string][]Validator)
+		}
+		s.validators[collection] = append(s.validators[collection], v)
+	}
+}
+
+// RequireFields returns a validator that rejects created or replaced objects with any of the fields missing.
+// Partial updates may omit the fields, but not set them to null.
+func RequireFields(fields ...string) Validator {
+	return func(method string, body map[string]interface{}) error {
+		var missing []string
+		for _, field := range fields {
+			value, ok := body[field]
+			if (!ok && method != http.MethodPatch) || (ok && value == nil) {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("missing required fields %s", strings.Join(missing, ", "))
+		}
+		return nil
+	}
+}
+
+// MatchFormat returns a validator that rejects bodies where the field is set to a string not matching re.
+func MatchFormat(field string, re *regexp.Regexp) Validator {
+	return func(method string, body map[string]interface{}) error {
+		value, ok := body[field]
+		if !ok || value == nil {
+			return nil
+		}
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", field)
+		}
+		if !re.MatchString(s) {
+			return fmt.Errorf("%s %q does not match %s", field, s, re)
+		}
+		return nil
+	}
+}
+
+// validate runs the collection's validators on the encoded body of a write request.
+func (s *settings) validate(collection, method string, payload []byte) error {
+	validators := s.validators[collection]
+	if len(validators) == 0 || payload == nil {
+		return nil
+	}
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return nil
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return &ValidationError{Collection: collection, Err: fmt.Errorf("body is not a JSON object: %w", err)}
+	}
+	for _, v := range validators {
+		if err := v(method, body); err != nil {
+			return &ValidationError{Collection: collection, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+	c := NewClient(
+		WithBaseURL("https://test.com/api/"),
+		WithValidator("episodes", RequireFields("title", "slug")),
+		WithValidator("episodes", MatchFormat("slug", regexp.MustCompile(`^[a-z0-9-]+$`))))
+
+	tests := []struct {
+		method string
+		body   map[string]interface{}
+	}{
+		{http.MethodPost, map[string]interface{}{"title": "Race"}},
+		{http.MethodPost, map[string]interface{}{"title": "Race", "slug": "Not A Slug"}},
+		{http.MethodPatch, map[string]interface{}{"title": nil}},
+	}
+
+	for _, test := range tests {
+		err := c.send(c.NewRequest("episodes", ""), test.method, test.body, nil)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("expected validation error for %s %v, got %v", test.method, test.body, err)
+		}
+	}
+
+	validators := c.current().validators["episodes"]
+	if err := validators[0](http.MethodPatch, map[string]interface{}{"title": "Race"}); err != nil {
+		t.Error("partial update should be valid, got", err)
+	}
+}
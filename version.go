@@ -0,0 +1,23 @@
+package client
+
+// VersionStyle selects how the API version is communicated to the server.
+type VersionStyle int
+
+const (
+	// VersionPath appends the version to the endpoint as a path segment,
+	// turning https://test.com/api/ into https://test.com/api/v2/
+	VersionPath VersionStyle = iota
+	// VersionHeader sends the version in the Skylark-Api-Version header
+	VersionHeader
+)
+
+// VersionHeaderName is the header used by VersionHeader.
+const VersionHeaderName = "Skylark-Api-Version"
+
+// WithAPIVersion sets the API version used for every request made by the client.
+func WithAPIVersion(version string, style VersionStyle) Option {
+	return func(s *settings) {
+		s.version = version
+		s.versionStyle = style
+	}
+}
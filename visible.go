@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotVisible is returned by WaitVisible when a write did not become visible before the deadline.
+var ErrNotVisible = errors.New("write is not visible yet")
+
+// VisibilityPolicy controls how WaitVisible polls an object after a write.
+type VisibilityPolicy struct {
+	// Interval is the time to wait before the second poll, it defaults to 100ms.
+	Interval time.Duration
+	// Multiplier increases the interval for every further poll, it defaults to 2.
+	Multiplier float64
+	// MaxInterval caps the interval, it defaults to 5s.
+	MaxInterval time.Duration
+	// Timeout is how long to poll in total, it defaults to 30s. A deadline of ctx also ends polling.
+	Timeout time.Duration
+}
+
+// WaitVisible polls the object with the given uid until the read path returns it with a modified timestamp
+// of at least modified, which smooths over the eventual consistency of Skylark after writes.
+// Timestamps are compared as RFC 3339 times, so their precision and offsets don't matter.
+// Objects that are not found yet, like freshly created ones, are polled again. Other errors are returned.
+// ErrNotVisible is returned once the policy's timeout has passed.
+func (c *Client) WaitVisible(ctx context.Context, collection, uid, modified string, p VisibilityPolicy) error {
+	written, err := time.Parse(time.RFC3339Nano, modified)
+	if err != nil {
+		return fmt.Errorf("invalid modified timestamp: %w", err)
+	}
+	if p.Interval <= 0 {
+		p.Interval = 100 * time.Millisecond
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = 5 * time.Second
+	}
+	if p.Timeout <= 0 {
+		p.Timeout = 30 * time.Second
+	}
+	s := c.current()
+	deadline := s.now().Add(p.Timeout)
+
+	for interval := p.Interval; ; {
+		var object struct {
+			Modified string `json:"modified"`
+		}
+		err := c.NewRequest(collection, uid).
+			WithContext(ctx).
+			SkipCache().
+			AddField(NewField("uid")).
+			AddField(NewField("modified")).
+			Execute(&object)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			read, err := time.Parse(time.RFC3339Nano, object.Modified)
+			if err != nil {
+				return fmt.Errorf("invalid modified timestamp of %s/%s: %w", collection, uid, err)
+			}
+			if !read.Before(written) {
+				return nil
+			}
+		}
+
+		left := deadline.Sub(s.now())
+		if left <= 0 {
+			return fmt.Errorf("%w: %s/%s", ErrNotVisible, collection, uid)
+		}
+		if interval > left {
+			interval = left
+		}
+		if err := s.sleep(ctx, interval); err != nil {
+			return err
+		}
+		interval = time.Duration(float64(interval) * p.Multiplier)
+		if interval > p.MaxInterval {
+			interval = p.MaxInterval
+		}
+	}
+}
+
+// WaitWritten is WaitVisible for the response of a write like Request.Create or Request.Patch,
+// it takes the uid and modified timestamp from the returned object.
+func (c *Client) WaitWritten(ctx context.Context, collection string, written json.RawMessage, p VisibilityPolicy) error {
+	var object struct {
+		UID      string `json:"uid"`
+		Modified string `json:"modified"`
+	}
+	if err := json.Unmarshal(written, &object); err != nil {
+		return err
+	}
+	if object.UID == "" || object.Modified == "" {
+		return errors.New("written object has no uid or modified timestamp")
+	}
+	return c.WaitVisible(ctx, collection, object.UID, object.Modified, p)
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWaitVisible(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		switch polls {
+		case 1:
+			w.WriteHeader(http.StatusNotFound)
+		case 2:
+			w.Write([]byte(`{"uid":"ep_1","modified":"2020-01-01T10:00:00Z"}`))
+		default:
+			w.Write([]byte(`{"uid":"ep_1","modified":"2020-01-01T12:00:00Z"}`))
+		}
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	c := NewClient(WithBaseURL(server.URL), WithClock(clock), WithSleeper(clock))
+	written := json.RawMessage(`{"uid":"ep_1","modified":"2020-01-01T12:00:00Z"}`)
+	if err := c.WaitWritten(context.Background(), "episodes", written, VisibilityPolicy{}); err != nil {
+		t.Fatal(err)
+	}
+	if polls != 3 || len(clock.sleeps) != 2 || clock.sleeps[0] != 100*time.Millisecond || clock.sleeps[1] != 200*time.Millisecond {
+		t.Error("unexpected polls", polls, clock.sleeps)
+	}
+
+	polls = 0
+	err := c.WaitVisible(context.Background(), "episodes", "ep_1", "2021-01-01T00:00:00Z", VisibilityPolicy{Timeout: time.Second})
+	if !errors.Is(err, ErrNotVisible) {
+		t.Error("expected ErrNotVisible, got", err)
+	}
+}
+
+func TestWaitVisiblePrecision(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"uid":"ep_1","modified":"2020-01-01T13:00:00.5+01:00"}`))
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	c := NewClient(WithBaseURL(server.URL), WithClock(clock), WithSleeper(clock))
+	for _, modified := range []string{"2020-01-01T12:00:00Z", "2020-01-01T12:00:00.5Z", "2020-01-01T12:00:00.500000Z"} {
+		if err := c.WaitVisible(context.Background(), "episodes", "ep_1", modified, VisibilityPolicy{}); err != nil {
+			t.Error(modified, err)
+		}
+	}
+	if len(clock.sleeps) != 0 {
+		t.Error("visible writes were polled again", clock.sleeps)
+	}
+	err := c.WaitVisible(context.Background(), "episodes", "ep_1", "2020-01-01T12:00:00.6Z", VisibilityPolicy{Timeout: time.Second})
+	if !errors.Is(err, ErrNotVisible) {
+		t.Error("expected ErrNotVisible, got", err)
+	}
+}
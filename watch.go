@@ -0,0 +1,116 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WatchEvent is emitted by Watch when the watched object changed or polling it failed.
+type WatchEvent struct {
+	// Object is the raw JSON of the object, it is nil if Err is set.
+	Object json.RawMessage
+	Err    error
+}
+
+// Watch polls the request every interval and emits the object whenever it changes.
+// Changes are detected by the response's ETag, the object's modified timestamp if there is none,
+// or else by its content. The first successful poll is always emitted. Errors are emitted and polling continues.
+// An interval that isn't positive is emitted as an error and ends the watch.
+// The channel is closed when ctx is done or the client was closed.
+func (r *Request) Watch(ctx context.Context, interval time.Duration) <-chan WatchEvent {
+	events := make(chan WatchEvent)
+	go func() {
+		defer close(events)
+
+		if interval <= 0 {
+			select {
+			case events <- WatchEvent{Err: fmt.Errorf("invalid watch interval %v", interval)}:
+			case <-ctx.Done():
+			}
+			return
+		}
+		s := r.executor().current()
+
+		var last json.RawMessage
+		var lastETag string
+		for {
+			header := &responseHeader{}
+			poll := r.copy()
+			poll.ctx = context.WithValue(ctx, responseHeaderKey{}, header)
+
+			var object json.RawMessage
+			err := poll.Execute(&object)
+			if ctx.Err() != nil {
+				return
+			}
+
+			var event *WatchEvent
+			etag := header.Get("ETag")
+			if err != nil {
+				event = &WatchEvent{Err: err}
+			} else if last == nil || changed(last, object, lastETag, etag) {
+				last, lastETag = object, etag
+				event = &WatchEvent{Object: object}
+			}
+			if event != nil {
+				select {
+				case events <- *event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if errors.Is(err, ErrClientClosed) {
+				return
+			}
+
+			if s.sleep(ctx, interval) != nil {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// responseHeaderKey is the context key of the responseHeader that execute stores the response header in.
+type responseHeaderKey struct{}
+
+// responseHeader receives the header of the last response to a request.
+type responseHeader struct {
+	mu     sync.Mutex
+	header http.Header
+}
+
+func (h *responseHeader) set(header http.Header) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header = header
+}
+
+// Get returns the value of the header key of the last response.
+func (h *responseHeader) Get(key string) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.header.Get(key)
+}
+
+// changed compares two versions of an object by the ETags of their responses, their modified timestamp,
+// or their content.
+func changed(old, new json.RawMessage, oldETag, newETag string) bool {
+	if oldETag != "" && newETag != "" {
+		return oldETag != newETag
+	}
+	var oldMeta, newMeta struct {
+		Modified string `json:"modified"`
+	}
+	if json.Unmarshal(old, &oldMeta) == nil && json.Unmarshal(new, &newMeta) == nil &&
+		oldMeta.Modified != "" && newMeta.Modified != "" {
+		return oldMeta.Modified != newMeta.Modified
+	}
+	return !bytes.Equal(old, new)
+}
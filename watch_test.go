@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := 1
+		if atomic.AddInt32(&calls, 1) > 2 {
+			version = 2
+		}
+		fmt.Fprintf(w, `{"title": "v%d", "modified": "2020-01-0%dT00:00:00Z"}`, version, version)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	events := NewRequest(server.URL, "set", "set_123").Watch(ctx, 10*time.Millisecond)
+
+	for _, expected := range []string{"v1", "v2"} {
+		event, ok := <-events
+		if !ok {
+			t.Fatal("channel closed before receiving", expected)
+		}
+		if event.Err != nil {
+			t.Fatal("unexpected error:", event.Err)
+		}
+		var set struct {
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal(event.Object, &set); err != nil {
+			t.Fatal(err)
+		}
+		if set.Title != expected {
+			t.Errorf("incorrect object\nexpected: %s\ngot:      %s", expected, set.Title)
+		}
+	}
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatchETag(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := 1
+		if atomic.AddInt32(&calls, 1) > 2 {
+			version = 2
+		}
+		w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, version))
+		fmt.Fprint(w, `{"title": "set", "modified": "2020-01-01T00:00:00Z"}`)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	events := NewRequest(server.URL, "set", "set_123").Watch(ctx, 10*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		event, ok := <-events
+		if !ok {
+			t.Fatalf("channel closed after %d events", i)
+		}
+		if event.Err != nil {
+			t.Fatal("unexpected error:", event.Err)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n < 3 {
+		t.Errorf("change emitted after %d polls, expected at least 3", n)
+	}
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatchInvalidInterval(t *testing.T) {
+	events := NewRequest("http://example.com", "set", "set_123").Watch(context.Background(), 0)
+	event, ok := <-events
+	if !ok || event.Err == nil {
+		t.Fatal("expected an error for a zero interval")
+	}
+	if _, ok := <-events; ok {
+		t.Error("channel not closed after the error")
+	}
+}